@@ -0,0 +1,189 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"github.com/tommika/gorilla/algorithms/matrix"
+	"github.com/tommika/gorilla/util"
+)
+
+// noHop marks the absence of a path in the next-hop matrix.
+const noHop = -1
+
+// AllPairs is the result of an all-pairs shortest path computation. Distances
+// and next hops are held in matrices whose rows and columns are ordered as
+// the nodes returned by Nodes (the same order used by ToAdjacencyMatrix.)
+type AllPairs[N comparable, W Weight] struct {
+	nodes []N
+	index map[N]int
+	dist  matrix.Matrix[W]   // dist[i][j] is the shortest distance from i to j
+	next  matrix.Matrix[int] // next[i][j] is the node after i on the path to j
+}
+
+// Nodes returns the node represented by each row (and column) of the
+// distance and next-hop matrices.
+func (ap *AllPairs[N, W]) Nodes() []N {
+	return ap.nodes
+}
+
+// Distances returns the matrix of shortest distances. The distance between
+// two nodes that are not connected is the zero value; use NextHops (or
+// Distance) to distinguish these from paths of zero weight.
+func (ap *AllPairs[N, W]) Distances() matrix.Matrix[W] {
+	return ap.dist
+}
+
+// NextHops returns the matrix of next hops: cell (i,j) is the index of the
+// node that follows node i on the shortest path to node j, or -1 if there is
+// no such path.
+func (ap *AllPairs[N, W]) NextHops() matrix.Matrix[int] {
+	return ap.next
+}
+
+// Distance returns the weight of the shortest path between two nodes. If
+// there is no path, found will be false.
+func (ap *AllPairs[N, W]) Distance(from, to N) (dist W, found bool) {
+	i, okI := ap.index[from]
+	j, okJ := ap.index[to]
+	if !okI || !okJ || ap.next.Get(i, j) == noHop {
+		return
+	}
+	return ap.dist.Get(i, j), true
+}
+
+// FindPath determines if there is a path between two nodes. If so, the
+// shortest path and its accumulated weight are returned.
+func (ap *AllPairs[N, W]) FindPath(from, to N) (path []N, weight W) {
+	weight, found := ap.Distance(from, to)
+	if !found {
+		return
+	}
+	i, j := ap.index[from], ap.index[to]
+	path = []N{from}
+	for i != j {
+		i = ap.next.Get(i, j)
+		path = append(path, ap.nodes[i])
+	}
+	return
+}
+
+func (ap *AllPairs[N, W]) init(g *Graph[N, W]) {
+	ap.nodes = g.sortedNodes()
+	ap.index = make(map[N]int, len(ap.nodes))
+	for i, v := range ap.nodes {
+		ap.index[v] = i
+	}
+	n := len(ap.nodes)
+	ap.dist = matrix.NewMatrix(n, n, util.Zero[W]())
+	ap.next = matrix.NewMatrix(n, n, noHop)
+	for i := range n {
+		ap.next.Set(i, i, i)
+	}
+}
+
+// FloydWarshall computes the shortest paths between all pairs of nodes using
+// the Floyd-Warshall algorithm. Negative edge weights are allowed, but if the
+// graph contains a negative-weight cycle, ErrNegativeCycle is returned.
+// Runs in O(V^3) time and uses O(V^2) space, so is best suited to dense
+// graphs.
+func (g *Graph[N, W]) FloydWarshall() (ap AllPairs[N, W], err error) {
+	ap.init(g)
+	dist, next := ap.dist.Rows(), ap.next.Rows()
+	for v := range g.nodes {
+		i := ap.index[v]
+		for _, e := range g.outgoing(v) {
+			j := ap.index[e.node]
+			// keep the lightest of any parallel edges
+			if next[i][j] == noHop || e.weight < dist[i][j] {
+				dist[i][j] = e.weight
+				next[i][j] = j
+			}
+		}
+	}
+	n := len(ap.nodes)
+	for k := range n {
+		for i := range n {
+			if next[i][k] == noHop {
+				continue
+			}
+			for j := range n {
+				if next[k][j] == noHop {
+					continue
+				}
+				if d := dist[i][k] + dist[k][j]; next[i][j] == noHop || d < dist[i][j] {
+					dist[i][j] = d
+					next[i][j] = next[i][k]
+				}
+			}
+		}
+		// a negative distance from a node to itself means that the node is
+		// on a negative-weight cycle; stop early rather than let distances
+		// diverge.
+		for i := range n {
+			if dist[i][i] < util.Zero[W]() {
+				return ap, ErrNegativeCycle
+			}
+		}
+	}
+	return
+}
+
+// Johnson computes the shortest paths between all pairs of nodes using
+// Johnson's algorithm. Edges are first reweighted (using Bellman-Ford) so that
+// no weights are negative, and then Dijkstra's algorithm is run from every
+// node. Runs in O(VE log V) time, which is faster than Floyd-Warshall for
+// sparse graphs. If the graph contains a negative-weight cycle,
+// ErrNegativeCycle is returned.
+func (g *Graph[N, W]) Johnson() (ap AllPairs[N, W], err error) {
+	ap.init(g)
+	// Compute a potential h(v) for each node, as the shortest distance from a
+	// virtual source that has a zero-weight edge to every node.
+	h := make(map[N]W, len(g.nodes))
+	for v := range g.nodes {
+		h[v] = util.Zero[W]()
+	}
+	if _, relaxed := g.relaxAll(h, map[N]N{}); relaxed {
+		return ap, ErrNegativeCycle
+	}
+	// The reweighted edge weights w(u,v) + h(u) - h(v) are never negative,
+	// and preserve shortest paths.
+	reweight := func(u N, e edge[N, W]) (W, bool) {
+		return e.weight + h[u] - h[e.node], true
+	}
+	for i, s := range ap.nodes {
+		sp := g.dijkstra(s, reweight)
+		firstHop := make(map[N]N, len(sp.dist))
+		for v, d := range sp.dist {
+			j := ap.index[v]
+			ap.dist.Set(i, j, d-h[s]+h[v])
+			if v != s {
+				ap.next.Set(i, j, ap.index[johnsonFirstHop(s, v, sp.pred, firstHop)])
+			}
+		}
+	}
+	return
+}
+
+// johnsonFirstHop returns the first hop on the path from s to v in a shortest
+// path tree, given by the predecessor of each node. The first hop of a node is
+// the node itself if its predecessor is s, otherwise the first hop of its
+// predecessor. First hops are memoized, so that finding the first hop of
+// every node in the tree takes O(V) time.
+func johnsonFirstHop[N comparable](s, v N, pred map[N]N, firstHop map[N]N) N {
+	// walk back to a node whose first hop is known (or is a child of s)
+	path := []N{}
+	u := v
+	hop, found := firstHop[u]
+	for !found {
+		path = append(path, u)
+		if p := pred[u]; p == s {
+			hop, found = u, true
+		} else {
+			u = p
+			hop, found = firstHop[u]
+		}
+	}
+	for _, u := range path {
+		firstHop[u] = hop
+	}
+	return hop
+}
@@ -0,0 +1,131 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/tommika/gorilla/algorithms/matrix"
+	"github.com/tommika/gorilla/assert"
+)
+
+type allPairsFunc func(g *Graph[string, int]) (AllPairs[string, int], error)
+
+var allPairsFuncs = map[string]allPairsFunc{
+	"FloydWarshall": (*Graph[string, int]).FloydWarshall,
+	"Johnson":       (*Graph[string, int]).Johnson,
+}
+
+func TestAllPairs(t *testing.T) {
+	g := negativeEdgeGraph()
+	for name, allPairs := range allPairsFuncs {
+		t.Logf("%s", name)
+		ap, err := allPairs(g)
+		assert.Nil(t, err)
+		assert.DeepEqual(t, []string{"s", "t", "x", "y", "z"}, ap.Nodes())
+		ap.Distances().Fprintf(os.Stderr)
+		ap.NextHops().Fprintf(os.Stderr)
+		// every pair should agree with Bellman-Ford
+		for _, s := range ap.Nodes() {
+			sp, err := g.BellmanFord(s)
+			assert.Nil(t, err)
+			for _, v := range ap.Nodes() {
+				expected, _ := sp.Distance(v)
+				dist, found := ap.Distance(s, v)
+				assert.True(t, found)
+				assert.Equal(t, expected, dist)
+				path, weight := ap.FindPath(s, v)
+				assert.Equal(t, expected, weight)
+				assert.Equal(t, s, path[0])
+				assert.Equal(t, v, path[len(path)-1])
+				assert.Equal(t, expected, pathWeight(t, g, path))
+			}
+		}
+	}
+}
+
+func TestAllPairsDisconnected(t *testing.T) {
+	g := NewGraph[string, int](Directed)
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("c", "d", 1)
+	for _, allPairs := range allPairsFuncs {
+		ap, err := allPairs(g)
+		assert.Nil(t, err)
+		_, found := ap.Distance("a", "d")
+		assert.False(t, found)
+		_, found = ap.Distance("b", "a")
+		assert.False(t, found)
+		_, found = ap.Distance("a", "bogus")
+		assert.False(t, found)
+		path, _ := ap.FindPath("a", "d")
+		assert.Nil(t, path)
+		assert.Equal(t, -1, ap.NextHops().Get(0, 3))
+		assert.Equal(t, 1, ap.NextHops().Get(0, 1))
+	}
+}
+
+func TestAllPairsChain(t *testing.T) {
+	// a long chain, with a shortcut, so that paths have many hops
+	const n = 200
+	g := NewGraph[string, int](Directed)
+	for i := 1; i < n; i++ {
+		g.AddEdge(strconv.Itoa(i-1), strconv.Itoa(i), 1)
+	}
+	g.AddEdge("0", "100", -5)
+	fw, err := g.FloydWarshall()
+	assert.Nil(t, err)
+	j, err := g.Johnson()
+	assert.Nil(t, err)
+	assert.DeepEqual(t, fw.Distances().Rows(), j.Distances().Rows())
+	assert.DeepEqual(t, fw.NextHops().Rows(), j.NextHops().Rows())
+	path, weight := j.FindPath("0", "199")
+	assert.Equal(t, 94, weight)
+	assert.Equal(t, 101, len(path))
+}
+
+func TestAllPairsNegativeCycle(t *testing.T) {
+	g := NewGraph[string, int](Undirected)
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("b", "c", -1)
+	for _, allPairs := range allPairsFuncs {
+		_, err := allPairs(g)
+		assert.True(t, errors.Is(err, ErrNegativeCycle))
+	}
+}
+
+func TestToAdjacencyMatrix(t *testing.T) {
+	const ms = `0 1 0 3
+	            0 0 2 0
+	            7 0 0 0
+	            0 0 5 0`
+	m := matrix.ParseMatrix(ms, strconv.Atoi)
+	g := GraphFromAdjacencyMatrix(m, Directed)
+	mT, nodes := g.ToAdjacencyMatrix()
+	mT.Fprintf(os.Stderr)
+	assert.DeepEqual(t, []int{0, 1, 2, 3}, nodes)
+	assert.DeepEqual(t, m.Rows(), mT.Rows())
+
+	const ums = `0 1 0 3
+	             0 0 2 0
+	             0 0 0 4
+	             0 0 0 0`
+	m = matrix.ParseMatrix(ums, strconv.Atoi)
+	g = GraphFromAdjacencyMatrix(m, Undirected)
+	mT, _ = g.ToAdjacencyMatrix()
+	assert.DeepEqual(t, m.Rows(), mT.Rows())
+	gT := GraphFromAdjacencyMatrix(mT, Undirected)
+	assert.Equal(t, g.EdgeCount(), gT.EdgeCount())
+	assert.Equal(t, g.NodeCount(), gT.NodeCount())
+}
+
+func pathWeight[N comparable](t *testing.T, g *Graph[N, int], path []N) (sum int) {
+	t.Helper()
+	for i := 1; i < len(path); i++ {
+		w, found := g.GetEdgeWeight(path[i-1], path[i])
+		assert.True(t, found)
+		sum += w
+	}
+	return
+}
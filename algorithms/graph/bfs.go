@@ -2,14 +2,21 @@
 package graph
 
 import (
+	"cmp"
+	"io"
+	"maps"
+	"slices"
+
 	"github.com/tommika/gorilla/algorithms/queue"
 	"github.com/tommika/gorilla/must"
 	"github.com/tommika/gorilla/util"
 )
 
-// BFTree is the tree constructed in a breadth-first search of the graph.
+// BFTree is the tree constructed in a breadth-first search of the graph. If
+// the search started from multiple sources, it is a forest, with a tree rooted
+// at each source.
 type BFTree[N comparable, W Weight] struct {
-	root    N // source node
+	root    N // (first) source node
 	nodeMap map[N]bftNode[N, W]
 	nodes   []N // in BF order
 }
@@ -19,6 +26,7 @@ type BFTree[N comparable, W Weight] struct {
 type bftNode[N comparable, W Weight] struct {
 	pred   N
 	weight W
+	depth  int // number of hops from the source
 }
 
 // FindPath determines if there is a path between two nodes in the graph.  If
@@ -30,26 +38,54 @@ func (g *Graph[N, W]) FindPath(s, v N) (path []N, weight W) {
 
 // BFS performs a breadth-first search of the graph, starting
 // from the given node, and returns the resulting breadth-first tree.
+// A maxDepth of zero means no limit; otherwise only nodes within that many
+// hops are visited (so a negative maxDepth visits only the source.)
 func (g *Graph[N, W]) BFS(s N, maxDepth int) (bft BFTree[N, W]) {
 	if !g.HasNode(s) {
 		return
 	}
-	type bfqItem[N comparable] struct {
-		u     N
-		depth int
+	return g.bfs([]N{s}, maxDepth, g.outgoing)
+}
+
+// MultiSourceBFS performs a breadth-first search of the graph, starting from
+// all of the given nodes at once (at depth zero), and returns the resulting
+// breadth-first forest. Each node is reached from its nearest source, so its
+// depth is the number of hops from the nearest source. Sources that aren't
+// in the graph are ignored.
+func (g *Graph[N, W]) MultiSourceBFS(sources []N, maxDepth int) (bft BFTree[N, W]) {
+	present := make([]N, 0, len(sources))
+	for _, s := range sources {
+		if g.HasNode(s) {
+			present = append(present, s)
+		}
+	}
+	if len(present) == 0 {
+		return
+	}
+	return g.bfs(present, maxDepth, g.outgoing)
+}
+
+// bfs performs a breadth-first search of the graph, starting from the given
+// nodes, following the edges returned by the given adjacency function.
+func (g *Graph[N, W]) bfs(sources []N, maxDepth int, adjacent func(u N) []edge[N, W]) (bft BFTree[N, W]) {
+	bft.init(sources[0])
+	q := queue.DynamicCircularArrayQueue[N]{}
+	q.Enqueue(sources[0])
+	for _, s := range sources[1:] {
+		if !bft.hasNode(s) {
+			bft.nodeMap[s] = bftNode[N, W]{}
+			q.Enqueue(s)
+		}
 	}
-	bft.init(s)
-	q := queue.DynamicCircularArrayQueue[bfqItem[N]]{}
-	q.Enqueue(bfqItem[N]{u: s, depth: 0})
 	for q.Size() != 0 {
-		head := q.MustDequeue()
-		bft.nodes = append(bft.nodes, head.u)
-		if maxDepth == 0 || head.depth < maxDepth {
-			for _, e := range g.outgoing(head.u) {
+		u := q.MustDequeue()
+		bft.nodes = append(bft.nodes, u)
+		if maxDepth == 0 || bft.nodeMap[u].depth < maxDepth {
+			for _, e := range adjacent(u) {
 				if !bft.hasNode(e.node) {
 					// first time visiting this node
-					bft.addEdge(e, head.u)
-					q.Enqueue(bfqItem[N]{u: e.node, depth: head.depth + 1})
+					bft.addEdge(e, u)
+					q.Enqueue(e.node)
 				}
 			}
 		}
@@ -70,14 +106,14 @@ func (bft *BFTree[N, W]) FindPath(v N) (path []N, weight W) {
 	}
 	pathT := []N{}
 	var weightT W
-	for v != bft.root {
+	for node.depth > 0 {
 		pathT = append(pathT, v)
 		weightT += node.weight
 		v = node.pred
 		node, ok = bft.nodeMap[v]
 		must.BeTrue(ok)
 	}
-	pathT = append(pathT, bft.root)
+	pathT = append(pathT, v)
 	// we built the path from bottom-up, so need to reverse it
 	// before returning.
 	path = util.ReverseSlice(pathT)
@@ -96,6 +132,113 @@ func (bft *BFTree[N, W]) NodeCount() int {
 	return len(bft.nodes)
 }
 
+// Root returns the node at which the search started (or the first source, if
+// there were multiple sources.)
+func (bft *BFTree[N, W]) Root() N {
+	return bft.root
+}
+
+// Depth returns the depth of the given node in the tree: the number of hops
+// from the source (or nearest source) to the node. If the node isn't in the
+// tree, found will be false.
+func (bft *BFTree[N, W]) Depth(n N) (depth int, found bool) {
+	node, found := bft.nodeMap[n]
+	return node.depth, found
+}
+
+// Parent returns the node's parent (predecessor) in the tree. If the node
+// isn't in the tree, or is a source (and so has no parent), found will be
+// false.
+func (bft *BFTree[N, W]) Parent(n N) (parent N, found bool) {
+	node, found := bft.nodeMap[n]
+	if !found || node.depth == 0 {
+		return parent, false
+	}
+	return node.pred, true
+}
+
+// Source returns the source from which the given node was reached. If the
+// node isn't in the tree, found will be false.
+func (bft *BFTree[N, W]) Source(n N) (source N, found bool) {
+	node, found := bft.nodeMap[n]
+	if !found {
+		return
+	}
+	for node.depth > 0 {
+		n = node.pred
+		node = bft.nodeMap[n]
+	}
+	return n, true
+}
+
+// Level returns the nodes at the given depth (exactly k hops from the source,
+// or nearest source), in BF order. Level zero holds the sources.
+func (bft *BFTree[N, W]) Level(k int) []N {
+	// nodes are in order of depth, so the level is a contiguous range
+	depthOf := func(v N, k int) int {
+		return cmp.Compare(bft.nodeMap[v].depth, k)
+	}
+	lo, _ := slices.BinarySearchFunc(bft.nodes, k, depthOf)
+	hi, _ := slices.BinarySearchFunc(bft.nodes, k+1, depthOf)
+	return slices.Clone(bft.nodes[lo:hi])
+}
+
+// MaxDepth returns the depth of the deepest node in the tree.
+func (bft *BFTree[N, W]) MaxDepth() int {
+	if len(bft.nodes) == 0 {
+		return 0
+	}
+	return bft.nodeMap[bft.nodes[len(bft.nodes)-1]].depth
+}
+
+// ToGraph constructs a directed graph from the tree, with an edge from each
+// node's predecessor to the node.
+func (bft *BFTree[N, W]) ToGraph() *Graph[N, W] {
+	g := NewGraph[N, W](Directed)
+	for _, v := range bft.nodes {
+		g.AddNode(v)
+		if node := bft.nodeMap[v]; node.depth > 0 {
+			g.AddEdge(node.pred, v, node.weight)
+		}
+	}
+	return g
+}
+
+// WriteDOT writes the tree in the Graphviz DOT language, with the root (or
+// roots) of the search highlighted.
+func (bft *BFTree[N, W]) WriteDOT(out io.Writer, opts ExportOptions[N, W]) error {
+	return bft.ToGraph().WriteDOT(out, bft.exportOptions(opts))
+}
+
+// WriteGraphML writes the tree in the GraphML format, with the root (or roots)
+// of the search highlighted.
+func (bft *BFTree[N, W]) WriteGraphML(out io.Writer, opts ExportOptions[N, W]) error {
+	return bft.ToGraph().WriteGraphML(out, bft.exportOptions(opts))
+}
+
+// rootAttrs are the attributes used to highlight the root of the tree.
+var rootAttrs = map[string]string{
+	"root":      "true",
+	"style":     "filled",
+	"fillcolor": "gold",
+}
+
+// exportOptions extends the given options to highlight the roots of the tree.
+func (bft *BFTree[N, W]) exportOptions(opts ExportOptions[N, W]) ExportOptions[N, W] {
+	nodeAttrs := opts.NodeAttrs
+	opts.NodeAttrs = func(n N) map[string]string {
+		attrs := map[string]string{}
+		if nodeAttrs != nil {
+			maps.Copy(attrs, nodeAttrs(n))
+		}
+		if node, found := bft.nodeMap[n]; found && node.depth == 0 {
+			maps.Copy(attrs, rootAttrs)
+		}
+		return attrs
+	}
+	return opts
+}
+
 func (bft *BFTree[N, W]) init(root N) {
 	bft.root = root
 	bft.nodeMap = map[N]bftNode[N, W]{}
@@ -110,5 +253,6 @@ func (bft *BFTree[N, W]) addEdge(e edge[N, W], pred N) {
 	bft.nodeMap[e.node] = bftNode[N, W]{
 		weight: e.weight, // weight of edge leading to this node
 		pred:   pred,
+		depth:  bft.nodeMap[pred].depth + 1,
 	}
 }
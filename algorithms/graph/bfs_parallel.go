@@ -0,0 +1,271 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"runtime"
+	"sync"
+)
+
+// ParallelBFS performs a level-synchronous breadth-first search of the graph,
+// starting from the given node. The nodes of each level are divided among a
+// pool of workers (GOMAXPROCS workers, if workers isn't positive), which find
+// the nodes of the next level in parallel. The results of the workers are
+// merged in order, so the resulting tree is the same as the one returned by
+// BFS.
+func (g *Graph[N, W]) ParallelBFS(s N, maxDepth int, workers int) (bft BFTree[N, W]) {
+	if !g.HasNode(s) {
+		return
+	}
+	pool := newWorkerPool(workers)
+	defer pool.close()
+	bft.init(s)
+	frontier := []N{s}
+	for depth := 0; len(frontier) > 0; depth++ {
+		bft.nodes = append(bft.nodes, frontier...)
+		if maxDepth != 0 && depth >= maxDepth {
+			break
+		}
+		frontier = g.topDownStep(pool, &bft, frontier)
+	}
+	return
+}
+
+// DirectionOptimizingBFS performs a breadth-first search of the graph,
+// starting from the given node, that switches between top-down and bottom-up
+// steps. A top-down step (as in ParallelBFS) visits the neighbors of the
+// nodes in the current level. A bottom-up step instead looks for a parent in
+// the current level for each unvisited node, and stops looking once one is
+// found. This is much faster when the current level is large (as is typical
+// for the middle levels of a search of a low-diameter graph.) Steps are run
+// in parallel, as in ParallelBFS.
+//
+// The resulting tree is the same as the one returned by BFS: a bottom-up step
+// chooses the parent a top-down step would (the first node of the current
+// level with an edge to the node), and adds the nodes of the next level in the
+// same order.
+func (g *Graph[N, W]) DirectionOptimizingBFS(s N, maxDepth int, workers int) (bft BFTree[N, W]) {
+	// Heuristics from Beamer et al, "Direction-Optimizing Breadth-First
+	// Search": switch to bottom-up once the edges to check from the frontier
+	// exceed a fraction of the edges to check from the unvisited nodes, and
+	// switch back once the frontier is a small fraction of the nodes.
+	const (
+		alpha = 14
+		beta  = 24
+	)
+	if !g.HasNode(s) {
+		return
+	}
+	pool := newWorkerPool(workers)
+	defer pool.close()
+	bft.init(s)
+	// the unvisited nodes are only needed for bottom-up steps, so are
+	// filtered lazily
+	unvisited := g.sortedNodes()
+	unvisitedEdges := 0
+	for _, v := range unvisited {
+		unvisitedEdges += g.degree(v, true)
+	}
+	unvisitedEdges -= g.degree(s, true)
+	bottomUp := false
+	frontier := []N{s}
+	for depth := 0; len(frontier) > 0; depth++ {
+		bft.nodes = append(bft.nodes, frontier...)
+		if maxDepth != 0 && depth >= maxDepth {
+			break
+		}
+		frontierEdges := 0
+		for _, u := range frontier {
+			frontierEdges += g.degree(u, false)
+		}
+		if !bottomUp && frontierEdges > unvisitedEdges/alpha {
+			bottomUp = true
+		} else if bottomUp && len(frontier) < len(g.nodes)/beta {
+			bottomUp = false
+		}
+		if bottomUp {
+			j := 0
+			for _, v := range unvisited {
+				if !bft.hasNode(v) {
+					unvisited[j] = v
+					j++
+				}
+			}
+			unvisited = unvisited[:j]
+			frontier = g.bottomUpStep(pool, &bft, frontier, unvisited)
+		} else {
+			frontier = g.topDownStep(pool, &bft, frontier)
+		}
+		for _, v := range frontier {
+			unvisitedEdges -= g.degree(v, true)
+		}
+	}
+	return
+}
+
+// discovery records a node found by a worker during a step of a parallel
+// search, along with the edge that leads to it.
+type discovery[N comparable, W Weight] struct {
+	node   N
+	pred   N
+	weight W
+}
+
+// topDownStep finds the next level of a search by visiting the neighbors of
+// each node in the current level (frontier.) Nodes are added to the tree in
+// the order they would be added by a sequential search.
+func (g *Graph[N, W]) topDownStep(pool *workerPool, bft *BFTree[N, W], frontier []N) []N {
+	found := make([][]discovery[N, W], pool.chunks(len(frontier)))
+	// the tree is only read (and not modified) while the workers are running
+	pool.run(len(frontier), func(chunk, lo, hi int) {
+		for _, u := range frontier[lo:hi] {
+			for _, edges := range g.adjacency(u, false) {
+				for _, e := range edges {
+					if !bft.hasNode(e.node) {
+						found[chunk] = append(found[chunk], discovery[N, W]{e.node, u, e.weight})
+					}
+				}
+			}
+		}
+	})
+	return bft.addDiscoveries(found)
+}
+
+// bottomUpStep finds the next level of a search by looking for a parent in
+// the current level (frontier) for each unvisited node. The parent of a node
+// is the first node of the frontier with an edge to it, as for a top-down
+// step. The nodes of the next level are then added to the tree in the order a
+// top-down step would add them, by visiting the edges of each parent.
+func (g *Graph[N, W]) bottomUpStep(pool *workerPool, bft *BFTree[N, W], frontier []N, unvisited []N) []N {
+	position := make(map[N]int, len(frontier))
+	for i, u := range frontier {
+		position[u] = i
+	}
+	parent := make([]int, len(unvisited)) // position of the parent, or -1
+	pool.run(len(unvisited), func(chunk, lo, hi int) {
+		for i := lo; i < hi; i++ {
+			parent[i] = -1
+			for _, edges := range g.adjacency(unvisited[i], true) {
+				for _, e := range edges {
+					if j, found := position[e.node]; found && (parent[i] < 0 || j < parent[i]) {
+						parent[i] = j
+					}
+				}
+			}
+		}
+	})
+	parentOf := map[N]int{}
+	hasChild := make([]bool, len(frontier))
+	for i, v := range unvisited {
+		if parent[i] >= 0 {
+			parentOf[v] = parent[i]
+			hasChild[parent[i]] = true
+		}
+	}
+	found := make([][]discovery[N, W], pool.chunks(len(frontier)))
+	pool.run(len(frontier), func(chunk, lo, hi int) {
+		for i := lo; i < hi; i++ {
+			if !hasChild[i] {
+				continue
+			}
+			u := frontier[i]
+			for _, edges := range g.adjacency(u, false) {
+				for _, e := range edges {
+					if j, ok := parentOf[e.node]; ok && j == i {
+						found[chunk] = append(found[chunk], discovery[N, W]{e.node, u, e.weight})
+					}
+				}
+			}
+		}
+	})
+	return bft.addDiscoveries(found)
+}
+
+// addDiscoveries adds the nodes found by the workers to the tree, in order,
+// ignoring nodes that have already been added. Returns the added nodes.
+func (bft *BFTree[N, W]) addDiscoveries(found [][]discovery[N, W]) (added []N) {
+	for _, chunk := range found {
+		for _, d := range chunk {
+			if !bft.hasNode(d.node) {
+				bft.addEdge(edge[N, W]{d.node, d.weight}, d.pred)
+				added = append(added, d.node)
+			}
+		}
+	}
+	return
+}
+
+// adjacency returns the lists of edges leaving the given node (or entering
+// it, if reverse is true), in the same order as outgoing (or incoming), but
+// without merging the lists. Unlike outgoing (and incoming), this never
+// modifies the graph's storage, so is safe to call concurrently.
+func (g *Graph[N, W]) adjacency(u N, reverse bool) [2][]edge[N, W] {
+	node := g.nodes[u]
+	first, second := node.outgoing, node.incoming
+	if reverse {
+		first, second = second, first
+	}
+	if g.directed {
+		second = nil
+	}
+	return [2][]edge[N, W]{first, second}
+}
+
+// degree returns the number of edges leaving the given node (or entering it,
+// if reverse is true.)
+func (g *Graph[N, W]) degree(u N, reverse bool) int {
+	lists := g.adjacency(u, reverse)
+	return len(lists[0]) + len(lists[1])
+}
+
+// minChunkSize is the smallest number of items given to a worker, so that
+// small levels aren't split across workers.
+const minChunkSize = 256
+
+// workerPool runs tasks on a fixed number of goroutines.
+type workerPool struct {
+	workers int
+	tasks   chan func()
+}
+
+// newWorkerPool creates a pool with the given number of workers, or
+// GOMAXPROCS workers if not positive.
+func newWorkerPool(workers int) *workerPool {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	p := &workerPool{workers: workers, tasks: make(chan func())}
+	for range workers {
+		go func() {
+			for task := range p.tasks {
+				task()
+			}
+		}()
+	}
+	return p
+}
+
+// chunks returns the number of chunks into which n items are divided.
+func (p *workerPool) chunks(n int) int {
+	return max(1, min(p.workers, (n+minChunkSize-1)/minChunkSize))
+}
+
+// run divides n items into chunks, and runs the task for each chunk on the
+// workers. Returns once all chunks are done.
+func (p *workerPool) run(n int, task func(chunk, lo, hi int)) {
+	chunks := p.chunks(n)
+	size := (n + chunks - 1) / chunks
+	wg := sync.WaitGroup{}
+	wg.Add(chunks)
+	for i := range chunks {
+		lo, hi := min(i*size, n), min((i+1)*size, n)
+		p.tasks <- func() {
+			defer wg.Done()
+			task(i, lo, hi)
+		}
+	}
+	wg.Wait()
+}
+
+func (p *workerPool) close() {
+	close(p.tasks)
+}
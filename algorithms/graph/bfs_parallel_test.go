@@ -0,0 +1,99 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+func randomGraph(nodes, edges int, directed GraphType) *Graph[int, int] {
+	g := NewGraph[int, int](directed)
+	for i := range nodes {
+		g.AddNode(i)
+	}
+	g.ReadEdges(bytes.NewReader(randomEdges(nodes, edges)), readEdge)
+	return g
+}
+
+// depths returns the depth of each node in the tree
+func depths[N comparable, W Weight](bft *BFTree[N, W]) map[N]int {
+	depth := map[N]int{}
+	bft.VisitNodes(func(v N) {
+		depth[v], _ = bft.Depth(v)
+	})
+	return depth
+}
+
+func TestParallelBFS(t *testing.T) {
+	for _, directed := range []GraphType{Directed, Undirected} {
+		g := randomGraph(5000, 10000, directed)
+		for _, maxDepth := range []int{0, 1, 3} {
+			expected := g.BFS(0, maxDepth)
+			for _, workers := range []int{0, 1, 3} {
+				bft := g.ParallelBFS(0, maxDepth, workers)
+				assert.DeepEqual(t, expected, bft)
+			}
+		}
+	}
+	g := randomGraph(10, 10, Directed)
+	bft := g.ParallelBFS(-1, 0, 0)
+	assert.Equal(t, 0, bft.NodeCount())
+	bft = g.ParallelBFS(0, 0, 0)
+	assert.DeepEqual(t, g.BFS(0, 0), bft)
+}
+
+func TestDirectionOptimizingBFS(t *testing.T) {
+	for _, directed := range []GraphType{Directed, Undirected} {
+		// dense enough that bottom-up steps are used
+		g := randomGraph(5000, 50000, directed)
+		for _, maxDepth := range []int{0, 1, 2, 3} {
+			expected := g.BFS(0, maxDepth)
+			bft := g.DirectionOptimizingBFS(0, maxDepth, 0)
+			assert.Equal(t, expected.NodeCount(), bft.NodeCount())
+			assert.DeepEqual(t, depths(&expected), depths(&bft))
+			assert.DeepEqual(t, expected, bft)
+			// deterministic, regardless of the number of workers
+			assert.DeepEqual(t, bft, g.DirectionOptimizingBFS(0, maxDepth, 1))
+			assert.DeepEqual(t, bft, g.DirectionOptimizingBFS(0, maxDepth, 5))
+		}
+	}
+	g := NewGraph[string, int](Directed)
+	g.AddEdge("a", "b", 2)
+	g.AddEdge("b", "c", 3)
+	g.AddEdge("c", "a", 4)
+	bft := g.DirectionOptimizingBFS("b", 0, 0)
+	path, weight := bft.FindPath("a")
+	assert.DeepEqual(t, []string{"b", "c", "a"}, path)
+	assert.Equal(t, 7, weight)
+	bft = g.DirectionOptimizingBFS("x", 0, 0)
+	assert.Equal(t, 0, bft.NodeCount())
+}
+
+func BenchmarkParallelBFS(b *testing.B) {
+	g := NewGraph[int, int](Undirected)
+	g.ReadEdges(bytes.NewReader(benchEdgeList()), readEdge)
+	b.ResetTimer()
+	for range b.N {
+		g.ParallelBFS(0, 0, 0)
+	}
+}
+
+func BenchmarkDirectionOptimizingBFS(b *testing.B) {
+	g := NewGraph[int, int](Undirected)
+	g.ReadEdges(bytes.NewReader(benchEdgeList()), readEdge)
+	b.ResetTimer()
+	for range b.N {
+		g.DirectionOptimizingBFS(0, 0, 0)
+	}
+}
+
+func TestBFSNegativeMaxDepth(t *testing.T) {
+	// as with BFS, a negative depth limit visits only the source
+	g := randomGraph(100, 500, Undirected)
+	expected := g.BFS(0, -1)
+	assert.Equal(t, 1, expected.NodeCount())
+	assert.DeepEqual(t, expected, g.ParallelBFS(0, -1, 0))
+	assert.DeepEqual(t, expected, g.DirectionOptimizingBFS(0, -1, 0))
+}
@@ -103,3 +103,63 @@ func TestBFSUndirected(t *testing.T) {
 	assert.Equal(t, 0, weight)
 
 }
+
+func TestBFSDepth(t *testing.T) {
+	g := pathGraph(5, Directed)
+	bft := g.BFS(0, 0)
+	depth, found := bft.Depth(3)
+	assert.True(t, found)
+	assert.Equal(t, 3, depth)
+	_, found = bft.Depth(9)
+	assert.False(t, found)
+	parent, found := bft.Parent(3)
+	assert.True(t, found)
+	assert.Equal(t, 2, parent)
+	_, found = bft.Parent(0)
+	assert.False(t, found)
+	assert.DeepEqual(t, []int{2}, bft.Level(2))
+	assert.Equal(t, 4, bft.MaxDepth())
+
+	g = Grid(3, 3, GeneratorOptions[int]{})
+	bft = g.BFS(0, 0)
+	assert.DeepEqual(t, []int{0}, bft.Level(0))
+	assert.DeepEqual(t, []int{1, 3}, bft.Level(1))
+	assert.Equal(t, 3, len(bft.Level(2)))
+	assert.DeepEqual(t, []int{8}, bft.Level(4))
+	assert.Equal(t, 0, len(bft.Level(5)))
+	bft = g.BFS(9, 0)
+	assert.Equal(t, 0, len(bft.Level(0)))
+	assert.Equal(t, 0, bft.MaxDepth())
+}
+
+func TestMultiSourceBFS(t *testing.T) {
+	g := pathGraph(7, Undirected)
+	bft := g.MultiSourceBFS([]int{0, 6}, 0)
+	assert.Equal(t, 7, bft.NodeCount())
+	assert.Equal(t, 0, bft.Root())
+	assert.DeepEqual(t, []int{0, 6}, bft.Level(0))
+	assert.DeepEqual(t, []int{1, 5}, bft.Level(1))
+	assert.Equal(t, 3, bft.MaxDepth())
+	depth, _ := bft.Depth(4)
+	assert.Equal(t, 2, depth)
+	source, found := bft.Source(4)
+	assert.True(t, found)
+	assert.Equal(t, 6, source)
+	source, _ = bft.Source(2)
+	assert.Equal(t, 0, source)
+	_, found = bft.Source(9)
+	assert.False(t, found)
+	path, weight := bft.FindPath(4)
+	assert.DeepEqual(t, []int{6, 5, 4}, path)
+	assert.Equal(t, 2, weight)
+	assert.Equal(t, 5, bft.ToGraph().EdgeCount())
+
+	// missing and repeated sources are ignored
+	bft = g.MultiSourceBFS([]int{9, 3, 3}, 1)
+	assert.DeepEqual(t, []int{3}, bft.Level(0))
+	assert.Equal(t, 3, bft.NodeCount())
+	assert.Equal(t, 3, bft.Root())
+
+	bft = g.MultiSourceBFS([]int{9}, 0)
+	assert.Equal(t, 0, bft.NodeCount())
+}
@@ -0,0 +1,344 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"errors"
+	"math"
+
+	"github.com/tommika/gorilla/algorithms/matrix"
+	"github.com/tommika/gorilla/algorithms/queue"
+	"github.com/tommika/gorilla/util"
+)
+
+// ErrNotBipartite is returned by algorithms that require a bipartite graph,
+// when given a graph that is not.
+var ErrNotBipartite = errors.New("graph is not bipartite")
+
+// Bipartition determines if the graph is bipartite: i.e., if its nodes can be
+// divided into two sides (left and right) such that every edge connects a node
+// on one side to a node on the other. Edge direction is ignored. If the graph
+// is not bipartite, then it contains a cycle of odd length, which is returned
+// as a witness (the first node of the cycle is not repeated at the end.)
+//
+// The graph is 2-colored using a breadth-first search of each connected
+// component. The first node of each component (in order of value, when the
+// node type is ordered) is placed on the left.
+func (g *Graph[N, W]) Bipartition() (left, right []N, oddCycle []N) {
+	side := make(map[N]bool, len(g.nodes)) // true if on the right
+	pred := make(map[N]N, len(g.nodes))
+	for _, s := range g.sortedNodes() {
+		if _, found := side[s]; found {
+			continue
+		}
+		side[s] = false
+		q := queue.DynamicCircularArrayQueue[N]{}
+		q.Enqueue(s)
+		for q.Size() != 0 {
+			u := q.MustDequeue()
+			for _, edges := range g.bothDirections(u) {
+				for _, e := range edges {
+					v := e.node
+					sideV, found := side[v]
+					if !found {
+						side[v] = !side[u]
+						pred[v] = u
+						q.Enqueue(v)
+					} else if sideV == side[u] {
+						return nil, nil, oddCycleWitness(pred, u, v)
+					}
+				}
+			}
+		}
+	}
+	for _, v := range g.sortedNodes() {
+		if side[v] {
+			right = append(right, v)
+		} else {
+			left = append(left, v)
+		}
+	}
+	return
+}
+
+// IsBipartite determines if the graph is bipartite (see Bipartition.)
+func (g *Graph[N, W]) IsBipartite() bool {
+	_, _, oddCycle := g.Bipartition()
+	return oddCycle == nil
+}
+
+// bothDirections returns the edges leaving and entering the given node; i.e.,
+// all of the node's edges, ignoring direction.
+func (g *Graph[N, W]) bothDirections(u N) [2][]edge[N, W] {
+	return [2][]edge[N, W]{g.nodes[u].outgoing, g.nodes[u].incoming}
+}
+
+// oddCycleWitness constructs the odd cycle formed by an edge between two nodes
+// on the same side, and their paths to the root of the breadth-first
+// search. Since both nodes are at the same depth, the cycle goes from their
+// closest common ancestor down to u, across the edge to v, and back up.
+func oddCycleWitness[N comparable](pred map[N]N, u, v N) []N {
+	if u == v {
+		// self-loop
+		return []N{u}
+	}
+	pathU, pathV := []N{u}, []N{v}
+	for u != v {
+		u, v = pred[u], pred[v]
+		pathU, pathV = append(pathU, u), append(pathV, v)
+	}
+	// pathU and pathV both end with the common ancestor
+	cycle := util.ReverseSlice(pathU)
+	return append(cycle, pathV[:len(pathV)-1]...)
+}
+
+// MaximumMatching finds a maximum cardinality matching of a bipartite graph,
+// using the Hopcroft-Karp algorithm: a largest set of edges, no two of which
+// share a node. Each edge of the matching is returned as an arc from a node on
+// the left to a node on the right (see Bipartition.) Edge direction is
+// ignored. Returns ErrNotBipartite if the graph is not bipartite. Runs in
+// O(E sqrt(V)) time.
+func (g *Graph[N, W]) MaximumMatching() ([]Arc[N], error) {
+	left, right, oddCycle := g.Bipartition()
+	if oddCycle != nil {
+		return nil, ErrNotBipartite
+	}
+	rightIndex := make(map[N]int, len(right))
+	for j, v := range right {
+		rightIndex[v] = j
+	}
+	adj := make([][]int, len(left))
+	for i, u := range left {
+		for _, edges := range g.bothDirections(u) {
+			for _, e := range edges {
+				adj[i] = append(adj[i], rightIndex[e.node])
+			}
+		}
+	}
+	hk := hopcroftKarp{adj: adj}
+	matching := []Arc[N]{}
+	for i, j := range hk.match(len(right)) {
+		if j != noIndex {
+			matching = append(matching, Arc[N]{left[i], right[j]})
+		}
+	}
+	return matching, nil
+}
+
+// hopcroftKarp finds a maximum matching of a bipartite graph whose nodes are
+// numbered 0..L-1 on the left and 0..R-1 on the right.
+type hopcroftKarp struct {
+	adj        [][]int // right neighbors of each left node
+	matchLeft  []int   // right node matched to each left node
+	matchRight []int   // left node matched to each right node
+	dist       []int   // layer of each left node
+}
+
+// match returns the right node matched to each left node (or -1.)
+func (hk *hopcroftKarp) match(numRight int) []int {
+	hk.matchLeft = make([]int, len(hk.adj))
+	hk.matchRight = make([]int, numRight)
+	hk.dist = make([]int, len(hk.adj))
+	for i := range hk.matchLeft {
+		hk.matchLeft[i] = noIndex
+	}
+	for j := range hk.matchRight {
+		hk.matchRight[j] = noIndex
+	}
+	// Each phase finds a maximal set of vertex-disjoint shortest augmenting
+	// paths; at most O(sqrt(V)) phases are needed.
+	for hk.layer() {
+		for i := range hk.adj {
+			if hk.matchLeft[i] == noIndex {
+				hk.augment(i)
+			}
+		}
+	}
+	return hk.matchLeft
+}
+
+// layer performs a breadth-first search from the free left nodes, along
+// alternating paths, assigning each left node to a layer. Returns true if an
+// augmenting path exists.
+func (hk *hopcroftKarp) layer() (found bool) {
+	q := queue.DynamicCircularArrayQueue[int]{}
+	for i := range hk.adj {
+		if hk.matchLeft[i] == noIndex {
+			hk.dist[i] = 0
+			q.Enqueue(i)
+		} else {
+			hk.dist[i] = math.MaxInt
+		}
+	}
+	for q.Size() != 0 {
+		i := q.MustDequeue()
+		for _, j := range hk.adj[i] {
+			next := hk.matchRight[j]
+			if next == noIndex {
+				found = true
+			} else if hk.dist[next] == math.MaxInt {
+				hk.dist[next] = hk.dist[i] + 1
+				q.Enqueue(next)
+			}
+		}
+	}
+	return
+}
+
+// augment searches for an augmenting path from left node i, along the layers,
+// and flips the matching along the path if one is found.
+func (hk *hopcroftKarp) augment(i int) bool {
+	for _, j := range hk.adj[i] {
+		next := hk.matchRight[j]
+		if next == noIndex || (hk.dist[next] == hk.dist[i]+1 && hk.augment(next)) {
+			hk.matchLeft[i], hk.matchRight[j] = j, i
+			return true
+		}
+	}
+	// dead end; don't visit again in this phase
+	hk.dist[i] = math.MaxInt
+	return false
+}
+
+// MaximumWeightMatching finds a matching of a bipartite graph with the
+// greatest total weight, using the Hungarian algorithm. Each edge of the
+// matching is returned as an arc from a node on the left to a node on the
+// right (see Bipartition.) Edges with a negative weight are never matched,
+// and of any parallel edges, the heaviest is used. Edge direction is ignored.
+// Returns ErrNotBipartite if the graph is not bipartite. Uses a dense matrix
+// of the weights between the left and right nodes, so is best suited to small
+// (or dense) graphs.
+func (g *Graph[N, W]) MaximumWeightMatching() (matching []Arc[N], total W, err error) {
+	left, right, oddCycle := g.Bipartition()
+	if oddCycle != nil {
+		return nil, total, ErrNotBipartite
+	}
+	rightIndex := make(map[N]int, len(right))
+	for j, v := range right {
+		rightIndex[v] = j
+	}
+	// Find the assignment of least cost, where the cost of matching two nodes
+	// is the negated weight of the edge between them. A cost of zero is used
+	// when there is no edge (or a negative weight), as not matching the nodes
+	// is the better choice.
+	weights := matrix.NewMatrix(len(left), len(right), util.Zero[W]())
+	hasEdge := matrix.NewMatrix(len(left), len(right), false)
+	for i, u := range left {
+		for _, edges := range g.bothDirections(u) {
+			for _, e := range edges {
+				j := rightIndex[e.node]
+				if e.weight > util.Zero[W]() && (!hasEdge.Get(i, j) || e.weight > weights.Get(i, j)) {
+					weights.Set(i, j, e.weight)
+					hasEdge.Set(i, j, true)
+				}
+			}
+		}
+	}
+	matching = []Arc[N]{}
+	assignment, _ := hungarian(weights, true)
+	for i, j := range assignment {
+		if j != noIndex && hasEdge.Get(i, j) {
+			matching = append(matching, Arc[N]{left[i], right[j]})
+			total += weights.Get(i, j)
+		}
+	}
+	return
+}
+
+// Hungarian solves the assignment problem for the given cost matrix, using
+// the Hungarian algorithm: each row is assigned to a distinct column such that
+// the total cost is minimized. If there are more rows than columns, then some
+// rows can't be assigned. Returns the column assigned to each row (or -1), and
+// the total cost. Costs are compared as float64, so integer costs should be
+// exactly representable as such (i.e., less than 2^53.) Runs in O(n^2 m) time,
+// for n = min(rows, cols) and m = max(rows, cols).
+func Hungarian[W Weight](cost matrix.Matrix[W]) (assignment []int, total W) {
+	assignment, total = hungarian(cost, false)
+	return
+}
+
+// hungarian solves the assignment problem, either minimizing or maximizing
+// the total cost.
+func hungarian[W Weight](cost matrix.Matrix[W], maximize bool) (assignment []int, total W) {
+	rows, cols := cost.NumRows(), cost.NumCols()
+	assignment = make([]int, rows)
+	for i := range assignment {
+		assignment[i] = noIndex
+	}
+	if rows == 0 || cols == 0 {
+		return
+	}
+	// The algorithm requires that there are no more rows than columns; if
+	// there are, solve the transposed problem.
+	transposed := rows > cols
+	n, m := rows, cols
+	if transposed {
+		n, m = cols, rows
+	}
+	a := func(i, j int) float64 {
+		if transposed {
+			i, j = j, i
+		}
+		if maximize {
+			return -float64(cost.Get(i, j))
+		}
+		return float64(cost.Get(i, j))
+	}
+	// Potentials u (rows) and v (columns) are maintained such that
+	// u[i]+v[j] <= a(i,j), with equality for assigned pairs. Rows are added
+	// one at a time, each time finding a shortest augmenting path (as in
+	// Dijkstra's algorithm) using the reduced costs. Indices are 1-based, with
+	// column 0 as a virtual column for the row being added.
+	u, v := make([]float64, n+1), make([]float64, m+1)
+	p := make([]int, m+1) // row assigned to each column
+	way := make([]int, m+1)
+	minv := make([]float64, m+1)
+	used := make([]bool, m+1)
+	for i := 1; i <= n; i++ {
+		p[0] = i
+		j0 := 0
+		for j := range minv {
+			minv[j], used[j] = math.Inf(1), false
+		}
+		for p[j0] != 0 {
+			used[j0] = true
+			i0, delta, j1 := p[j0], math.Inf(1), 0
+			for j := 1; j <= m; j++ {
+				if !used[j] {
+					if cur := a(i0-1, j-1) - u[i0] - v[j]; cur < minv[j] {
+						minv[j], way[j] = cur, j0
+					}
+					if minv[j] < delta {
+						delta, j1 = minv[j], j
+					}
+				}
+			}
+			for j := range m + 1 {
+				if used[j] {
+					u[p[j]] += delta
+					v[j] -= delta
+				} else {
+					minv[j] -= delta
+				}
+			}
+			j0 = j1
+		}
+		// flip the assignment along the augmenting path
+		for j0 != 0 {
+			j1 := way[j0]
+			p[j0] = p[j1]
+			j0 = j1
+		}
+	}
+	for j := 1; j <= m; j++ {
+		if p[j] == 0 {
+			continue
+		}
+		i, c := p[j]-1, j-1
+		if transposed {
+			i, c = c, i
+		}
+		assignment[i] = c
+		total += cost.Get(i, c)
+	}
+	return
+}
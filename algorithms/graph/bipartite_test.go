@@ -0,0 +1,152 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/tommika/gorilla/algorithms/matrix"
+	"github.com/tommika/gorilla/assert"
+)
+
+func TestBipartition(t *testing.T) {
+	// even cycle plus an isolated node
+	g := NewGraph[int, int](Undirected)
+	for i := range 6 {
+		g.AddEdge(i, (i+1)%6, 1)
+	}
+	g.AddNode(10)
+	left, right, oddCycle := g.Bipartition()
+	assert.Nil(t, oddCycle)
+	assert.DeepEqual(t, []int{0, 2, 4, 10}, left)
+	assert.DeepEqual(t, []int{1, 3, 5}, right)
+	assert.True(t, g.IsBipartite())
+
+	// add a chord that creates an odd cycle
+	g.AddEdge(0, 2, 1)
+	left, right, oddCycle = g.Bipartition()
+	t.Logf("odd cycle: %v", oddCycle)
+	assert.Nil(t, left)
+	assert.Nil(t, right)
+	assertOddCycle(t, g, oddCycle)
+	assert.False(t, g.IsBipartite())
+
+	// direction is ignored
+	g = NewGraph[int, int](Directed)
+	for i := range 5 {
+		g.AddEdge(i, (i+1)%5, 1)
+	}
+	_, _, oddCycle = g.Bipartition()
+	assert.Equal(t, 5, len(oddCycle))
+
+	g = NewGraphWithPolicy[int, int](Undirected, EdgePolicy{SelfLoops: true})
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(2, 2, 1)
+	_, _, oddCycle = g.Bipartition()
+	assert.DeepEqual(t, []int{2}, oddCycle)
+}
+
+// assertOddCycle asserts that the given nodes form a cycle of odd length
+func assertOddCycle(t *testing.T, g *Graph[int, int], cycle []int) {
+	assert.Equal(t, 1, len(cycle)%2)
+	for i, u := range cycle {
+		v := cycle[(i+1)%len(cycle)]
+		assert.True(t, g.HasEdge(u, v) || g.HasEdge(v, u))
+	}
+}
+
+func TestMaximumMatching(t *testing.T) {
+	// jobs (0..4) and workers (10..14); a perfect matching exists only after
+	// reassigning along augmenting paths
+	g := NewGraph[int, int](Undirected)
+	for _, e := range [][2]int{{0, 10}, {0, 11}, {1, 10}, {2, 11}, {2, 12}, {3, 12}, {3, 13}, {4, 13}, {4, 14}, {1, 14}} {
+		g.AddEdge(e[0], e[1], 1)
+	}
+	matching, err := g.MaximumMatching()
+	assert.Nil(t, err)
+	t.Logf("matching: %v", matching)
+	assert.Equal(t, 5, len(matching))
+	assertMatching(t, g, matching)
+
+	// a star has a matching of size one
+	g = starGraph(5, Undirected)
+	matching, err = g.MaximumMatching()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(matching))
+
+	_, err = cliques(1, 3).MaximumMatching()
+	assert.Equal(t, ErrNotBipartite, err)
+
+	matching, err = NewGraph[int, int](Undirected).MaximumMatching()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(matching))
+}
+
+// assertMatching asserts that each arc is an edge, and no two arcs share a node
+func assertMatching[W Weight](t *testing.T, g *Graph[int, W], matching []Arc[int]) {
+	seen := map[int]bool{}
+	for _, a := range matching {
+		assert.True(t, g.HasEdge(a.From, a.To))
+		assert.False(t, seen[a.From] || seen[a.To])
+		seen[a.From], seen[a.To] = true, true
+	}
+}
+
+func TestHungarian(t *testing.T) {
+	const ms = `4 1 3
+	            2 0 5
+	            3 2 2`
+	cost := matrix.ParseMatrix(ms, strconv.Atoi)
+	cost.Fprintf(os.Stdout)
+	assignment, total := Hungarian(cost)
+	assert.DeepEqual(t, []int{1, 0, 2}, assignment)
+	assert.Equal(t, 5, total)
+
+	// more rows than columns: one row is left unassigned
+	const ms2 = `1 9
+	             9 1
+	             0 0`
+	assignment, total = Hungarian(matrix.ParseMatrix(ms2, strconv.Atoi))
+	assert.Equal(t, 1, total)
+	assigned := 0
+	for _, c := range assignment {
+		if c != -1 {
+			assigned++
+		}
+	}
+	assert.Equal(t, 2, assigned)
+
+	// unsigned costs
+	costU := matrix.NewMatrix(2, 3, uint8(0))
+	costU.Set(0, 0, 7)
+	costU.Set(0, 1, 3)
+	costU.Set(0, 2, 9)
+	costU.Set(1, 0, 2)
+	costU.Set(1, 1, 1)
+	costU.Set(1, 2, 8)
+	assignmentU, totalU := Hungarian(costU)
+	assert.DeepEqual(t, []int{1, 0}, assignmentU)
+	assert.Equal(t, uint8(5), totalU)
+
+	assignment, _ = Hungarian(matrix.NewMatrix(0, 0, 0))
+	assert.Equal(t, 0, len(assignment))
+}
+
+func TestMaximumWeightMatching(t *testing.T) {
+	g := NewGraph[int, float64](Undirected)
+	g.AddEdge(0, 10, 3)
+	g.AddEdge(0, 11, 2)
+	g.AddEdge(1, 10, 4)
+	g.AddEdge(1, 11, 1)
+	g.AddEdge(2, 11, -5)
+	matching, total, err := g.MaximumWeightMatching()
+	assert.Nil(t, err)
+	t.Logf("matching: %v", matching)
+	assert.Equal(t, 6.0, total)
+	assert.DeepEqual(t, []Arc[int]{{0, 11}, {1, 10}}, matching)
+	assertMatching(t, g, matching)
+
+	_, _, err = cliques(1, 3).MaximumWeightMatching()
+	assert.Equal(t, ErrNotBipartite, err)
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+// Centrality measures, for ranking the nodes of a graph by their importance.
+// Each measure is returned as a map of node to score, which can be ranked
+// using TopK.
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/tommika/gorilla/algorithms/heap"
+	"github.com/tommika/gorilla/algorithms/queue"
+)
+
+// InDegree returns the number of incoming edges of each node. If the graph is
+// undirected, this is the degree of each node (i.e., the same as OutDegree.)
+func (g *Graph[N, W]) InDegree() map[N]int {
+	degree := make(map[N]int, len(g.nodes))
+	for v, node := range g.nodes {
+		degree[v] = len(node.incoming)
+		if !g.directed {
+			degree[v] += len(node.outgoing)
+		}
+	}
+	return degree
+}
+
+// OutDegree returns the number of outgoing edges of each node. If the graph is
+// undirected, this is the degree of each node (i.e., the same as InDegree.)
+func (g *Graph[N, W]) OutDegree() map[N]int {
+	degree := make(map[N]int, len(g.nodes))
+	for v, node := range g.nodes {
+		degree[v] = len(node.outgoing)
+		if !g.directed {
+			degree[v] += len(node.incoming)
+		}
+	}
+	return degree
+}
+
+// Closeness returns the closeness centrality of each node, based on the
+// number of edges (hops) in the shortest path from the node to each node that
+// it can reach. To handle graphs that are not connected, the Wasserman and
+// Faust formula is used: (r/(n-1)) * (r/d), where r is the number of other
+// nodes that can be reached, and d is the sum of the distances to them.
+// Nodes that can't reach any other node have a closeness of zero.
+func (g *Graph[N, W]) Closeness() map[N]float64 {
+	closeness := make(map[N]float64, len(g.nodes))
+	n := len(g.nodes)
+	for s := range g.nodes {
+		closeness[s] = 0
+		dist := g.hopDistances(s)
+		reached, sum := len(dist)-1, 0
+		for _, d := range dist {
+			sum += d
+		}
+		if sum > 0 {
+			r := float64(reached)
+			closeness[s] = (r / float64(n-1)) * (r / float64(sum))
+		}
+	}
+	return closeness
+}
+
+// hopDistances returns the number of hops from the given node to each node
+// that it can reach.
+func (g *Graph[N, W]) hopDistances(s N) map[N]int {
+	dist := map[N]int{s: 0}
+	q := queue.DynamicCircularArrayQueue[N]{}
+	q.Enqueue(s)
+	for q.Size() != 0 {
+		u := q.MustDequeue()
+		for _, e := range g.outgoing(u) {
+			if _, found := dist[e.node]; !found {
+				dist[e.node] = dist[u] + 1
+				q.Enqueue(e.node)
+			}
+		}
+	}
+	return dist
+}
+
+// Betweenness returns the betweenness centrality of each node: the number of
+// shortest paths (by hop count) between other pairs of nodes that pass
+// through the node, computed using Brandes' algorithm. If the graph is
+// undirected, each pair of nodes is counted once.
+//
+// Computing the exact betweenness requires a search from every node, which
+// takes O(VE) time. For large graphs, an estimate can be computed by searching
+// from a random sample of nodes: if samples is positive (and less than the
+// number of nodes) then that many source nodes are chosen, using the given
+// seed, and the result is scaled to estimate the exact value.
+func (g *Graph[N, W]) Betweenness(samples int, seed int64) map[N]float64 {
+	betweenness := make(map[N]float64, len(g.nodes))
+	sources := make([]N, 0, len(g.nodes))
+	for v := range g.nodes {
+		betweenness[v] = 0
+		sources = append(sources, v)
+	}
+	scale := 1.0
+	if !g.directed {
+		// each path is found from both of its ends
+		scale = 0.5
+	}
+	if samples > 0 && samples < len(sources) {
+		// sort first, so that the sample is determined by the seed alone
+		sources = g.sortedNodes()
+		rnd := rand.New(rand.NewSource(seed))
+		rnd.Shuffle(len(sources), func(i, j int) {
+			sources[i], sources[j] = sources[j], sources[i]
+		})
+		scale *= float64(len(sources)) / float64(samples)
+		sources = sources[:samples]
+	}
+	for _, s := range sources {
+		g.accumulateBetweenness(s, betweenness)
+	}
+	for v := range betweenness {
+		betweenness[v] *= scale
+	}
+	return betweenness
+}
+
+// accumulateBetweenness performs a single iteration of Brandes' algorithm: a
+// breadth-first search from the given source, counting the shortest paths to
+// each node, followed by the accumulation of dependencies in reverse order.
+func (g *Graph[N, W]) accumulateBetweenness(s N, betweenness map[N]float64) {
+	order := []N{}               // nodes in order of non-decreasing distance
+	preds := map[N][]N{}         // predecessors on shortest paths
+	sigma := map[N]float64{s: 1} // number of shortest paths
+	dist := map[N]int{s: 0}      // distance from s
+	q := queue.DynamicCircularArrayQueue[N]{}
+	q.Enqueue(s)
+	for q.Size() != 0 {
+		u := q.MustDequeue()
+		order = append(order, u)
+		for _, e := range g.outgoing(u) {
+			v := e.node
+			if _, found := dist[v]; !found {
+				dist[v] = dist[u] + 1
+				q.Enqueue(v)
+			}
+			if dist[v] == dist[u]+1 {
+				sigma[v] += sigma[u]
+				preds[v] = append(preds[v], u)
+			}
+		}
+	}
+	delta := make(map[N]float64, len(order))
+	for i := len(order) - 1; i > 0; i-- {
+		w := order[i]
+		for _, v := range preds[w] {
+			delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+		}
+		betweenness[w] += delta[w]
+	}
+}
+
+// PageRankOptions control the computation of PageRank. The zero value of each
+// option selects its default.
+type PageRankOptions struct {
+	// Damping is the probability of following an edge, rather than jumping to
+	// a random node. Defaults to 0.85.
+	Damping float64
+	// Tolerance is the change in ranks (L1 norm) between iterations at which
+	// the computation has converged. Defaults to 1e-6.
+	Tolerance float64
+	// MaxIterations is the maximum number of iterations. Defaults to 100.
+	MaxIterations int
+}
+
+// PageRank returns the PageRank of each node. The ranks sum to one. Edges are
+// followed in proportion to their weight, and edges whose weight is not
+// positive are ignored. Nodes without any (positive) outgoing edges are
+// treated as if they link to every node. If the ranks don't converge within
+// the maximum number of iterations, the current ranks are returned along with
+// an error.
+func (g *Graph[N, W]) PageRank(opts PageRankOptions) (map[N]float64, error) {
+	damping, tolerance, maxIterations := opts.Damping, opts.Tolerance, opts.MaxIterations
+	if damping == 0 {
+		damping = 0.85
+	}
+	if tolerance == 0 {
+		tolerance = 1e-6
+	}
+	if maxIterations == 0 {
+		maxIterations = 100
+	}
+	if damping < 0 || damping > 1 {
+		return nil, fmt.Errorf("damping must be between 0 and 1; got %g", damping)
+	}
+	n := float64(len(g.nodes))
+	rank := make(map[N]float64, len(g.nodes))
+	outWeight := make(map[N]float64, len(g.nodes))
+	for v := range g.nodes {
+		rank[v] = 1 / n
+		for _, e := range g.outgoing(v) {
+			if w := float64(e.weight); w > 0 {
+				outWeight[v] += w
+			}
+		}
+	}
+	for range maxIterations {
+		// rank held by dangling nodes is spread across all nodes
+		dangling := 0.0
+		for v, r := range rank {
+			if outWeight[v] == 0 {
+				dangling += r
+			}
+		}
+		next := make(map[N]float64, len(g.nodes))
+		for v := range g.nodes {
+			next[v] += (1-damping)/n + damping*dangling/n
+			if outWeight[v] == 0 {
+				continue
+			}
+			for _, e := range g.outgoing(v) {
+				if w := float64(e.weight); w > 0 {
+					next[e.node] += damping * rank[v] * w / outWeight[v]
+				}
+			}
+		}
+		change := 0.0
+		for v, r := range next {
+			change += math.Abs(r - rank[v])
+		}
+		rank = next
+		if change < tolerance {
+			return rank, nil
+		}
+	}
+	return rank, fmt.Errorf("PageRank did not converge after %d iterations", maxIterations)
+}
+
+// Ranked is a node along with its score.
+type Ranked[N comparable, S cmp.Ordered] struct {
+	Node  N
+	Score S
+}
+
+// TopK returns the k nodes with the highest scores, in order of decreasing
+// score. Ties are broken by node value, when the node type is ordered.
+func TopK[N comparable, S cmp.Ordered](scores map[N]S, k int) []Ranked[N, S] {
+	if k <= 0 {
+		return []Ranked[N, S]{}
+	}
+	// Keep the top k in a min-heap, so that the lowest ranked is on top, and
+	// is replaced when a higher ranked node is found.
+	compare := func(a, b Ranked[N, S]) int {
+		if c := cmp.Compare(a.Score, b.Score); c != 0 {
+			return c
+		}
+		return compareNodes(b.Node, a.Node)
+	}
+	h := heap.NewHeap(compare)
+	for v, s := range scores {
+		item := Ranked[N, S]{v, s}
+		if h.Size() < k {
+			h.Push(item)
+		} else if top, _ := h.Peek(); compare(item, top) > 0 {
+			h.Pop()
+			h.Push(item)
+		}
+	}
+	top := make([]Ranked[N, S], h.Size())
+	for i := len(top) - 1; i >= 0; i-- {
+		top[i], _ = h.Pop()
+	}
+	return top
+}
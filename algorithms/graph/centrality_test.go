@@ -0,0 +1,158 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+// pathGraph creates the path graph 0-1-2-...-(n-1)
+func pathGraph(n int, directed GraphType) *Graph[int, int] {
+	g := NewGraph[int, int](directed)
+	for i := 1; i < n; i++ {
+		g.AddEdge(i-1, i, 1)
+	}
+	return g
+}
+
+// starGraph creates a graph with an edge from node 0 to each of nodes 1..n-1
+func starGraph(n int, directed GraphType) *Graph[int, int] {
+	g := NewGraph[int, int](directed)
+	for i := 1; i < n; i++ {
+		g.AddEdge(0, i, 1)
+	}
+	return g
+}
+
+func TestDegree(t *testing.T) {
+	g := starGraph(5, Directed)
+	g.AddEdge(1, 2, 1)
+	in, out := g.InDegree(), g.OutDegree()
+	assert.Equal(t, 0, in[0])
+	assert.Equal(t, 4, out[0])
+	assert.Equal(t, 2, in[2])
+	assert.Equal(t, 1, out[1])
+	assert.Equal(t, 0, out[4])
+
+	g = starGraph(5, Undirected)
+	in, out = g.InDegree(), g.OutDegree()
+	assert.DeepEqual(t, in, out)
+	assert.Equal(t, 4, in[0])
+	assert.Equal(t, 1, in[3])
+}
+
+func TestCloseness(t *testing.T) {
+	g := starGraph(5, Undirected)
+	closeness := g.Closeness()
+	assert.EqualEpsilon(t, 1, closeness[0], 1e-9)
+	// 1 hop to the center, and 2 hops to each of the other 3 leaves
+	assert.EqualEpsilon(t, 4.0/7.0, closeness[1], 1e-9)
+
+	// disconnected: node 3 can only reach node 4
+	g = pathGraph(3, Undirected)
+	g.AddEdge(3, 4, 1)
+	closeness = g.Closeness()
+	assert.EqualEpsilon(t, (2.0/4.0)*(2.0/2.0), closeness[1], 1e-9)
+	assert.EqualEpsilon(t, (1.0/4.0)*(1.0/1.0), closeness[3], 1e-9)
+
+	// directed: the leaves can't reach any node
+	g = starGraph(3, Directed)
+	closeness = g.Closeness()
+	assert.EqualEpsilon(t, 1, closeness[0], 1e-9)
+	assert.Equal(t, 0.0, closeness[1])
+}
+
+func TestBetweenness(t *testing.T) {
+	g := pathGraph(5, Undirected)
+	b := g.Betweenness(0, 0)
+	for i, expected := range []float64{0, 3, 4, 3, 0} {
+		assert.EqualEpsilon(t, expected, b[i], 1e-9)
+	}
+	g = pathGraph(5, Directed)
+	b = g.Betweenness(0, 0)
+	for i, expected := range []float64{0, 3, 4, 3, 0} {
+		assert.EqualEpsilon(t, expected, b[i], 1e-9)
+	}
+	// two shortest paths between 0 and 3, each through one of 1 and 2
+	g = NewGraph[int, int](Undirected)
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(0, 2, 1)
+	g.AddEdge(1, 3, 1)
+	g.AddEdge(2, 3, 1)
+	b = g.Betweenness(0, 0)
+	for i := range 4 {
+		assert.EqualEpsilon(t, 0.5, b[i], 1e-9)
+	}
+}
+
+func TestBetweennessSampled(t *testing.T) {
+	g := starGraph(101, Undirected)
+	exact := g.Betweenness(0, 0)
+	assert.EqualEpsilon(t, 100*99/2, exact[0], 1e-9)
+	b1 := g.Betweenness(50, 42)
+	b2 := g.Betweenness(50, 42)
+	assert.DeepEqual(t, b1, b2)
+	t.Logf("exact=%g, estimate=%g", exact[0], b1[0])
+	// with a source sampled from the leaves, the estimate for the center
+	// is exact; otherwise it's slightly lower
+	assert.EqualEpsilon(t, exact[0], b1[0], exact[0]*0.05)
+	assert.Equal(t, 0.0, b1[1])
+	assert.EqualEpsilon(t, exact[0], g.Betweenness(len(exact), 42)[0], 1e-9)
+}
+
+func TestPageRank(t *testing.T) {
+	// ranks are equal for a cycle
+	g := pathGraph(4, Directed)
+	g.AddEdge(3, 0, 1)
+	rank, err := g.PageRank(PageRankOptions{})
+	assert.Nil(t, err)
+	for i := range 4 {
+		assert.EqualEpsilon(t, 0.25, rank[i], 1e-6)
+	}
+
+	// every leaf links to the center (and a dangling node)
+	g = NewGraph[int, int](Directed)
+	for i := 1; i <= 4; i++ {
+		g.AddEdge(i, 0, 1)
+	}
+	g.AddNode(5)
+	rank, err = g.PageRank(PageRankOptions{Damping: 0.9, Tolerance: 1e-9})
+	assert.Nil(t, err)
+	sum := 0.0
+	for _, r := range rank {
+		sum += r
+	}
+	assert.EqualEpsilon(t, 1, sum, 1e-9)
+	top := TopK(rank, 2)
+	assert.Equal(t, 0, top[0].Node)
+	assert.True(t, rank[0] > 2*rank[1])
+	assert.EqualEpsilon(t, rank[1], rank[4], 1e-9)
+
+	// edges are followed in proportion to their weight
+	g = NewGraph[int, int](Directed)
+	g.AddEdge(0, 1, 3)
+	g.AddEdge(0, 2, 1)
+	g.AddEdge(1, 0, 1)
+	g.AddEdge(2, 0, 1)
+	rank, _ = g.PageRank(PageRankOptions{})
+	assert.True(t, rank[1] > rank[2])
+
+	_, err = g.PageRank(PageRankOptions{MaxIterations: 1})
+	assert.NotNil(t, err)
+	_, err = g.PageRank(PageRankOptions{Damping: 2})
+	assert.NotNil(t, err)
+}
+
+func TestTopK(t *testing.T) {
+	scores := map[string]int{"a": 1, "b": 5, "c": 3, "d": 5, "e": 0}
+	top := TopK(scores, 3)
+	assert.Equal(t, "[{b 5} {d 5} {c 3}]", fmt.Sprint(top))
+	assert.Equal(t, 5, len(TopK(scores, 10)))
+	assert.Equal(t, 0, len(TopK(scores, 0)))
+
+	g := pathGraph(5, Undirected)
+	top2 := TopK(g.Betweenness(0, 0), 1)
+	assert.Equal(t, 2, top2[0].Node)
+}
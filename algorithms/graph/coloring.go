@@ -0,0 +1,118 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+// Graph coloring: assigning a color to each node such that no two neighbors
+// have the same color, using as few colors as possible. Finding the fewest
+// colors is NP-hard, so heuristics are used. Colors are numbered from zero.
+// Edge direction and self-loops are ignored.
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/tommika/gorilla/algorithms/heap"
+)
+
+// GreedyColoring colors the nodes greedily, giving each node the least color
+// not used by any of its neighbors. Nodes are colored in order of decreasing
+// degree (the Welsh-Powell order), which tends to use fewer colors. Returns
+// the color of each node, and the number of colors used. Uses at most one
+// more color than the maximum degree.
+func (g *Graph[N, W]) GreedyColoring() (colors map[N]int, count int) {
+	nodes, adj := g.neighborIndices()
+	order := identity(len(nodes))
+	slices.SortStableFunc(order, func(i, j int) int {
+		return cmp.Compare(len(adj[j]), len(adj[i]))
+	})
+	color := make([]int, len(nodes))
+	for i := range color {
+		color[i] = noIndex
+	}
+	used := []bool{} // colors used by the current node's neighbors
+	for _, i := range order {
+		clear(used)
+		for _, j := range adj[i] {
+			if c := color[j]; c != noIndex {
+				if c >= len(used) {
+					used = append(used, make([]bool, c+1-len(used))...)
+				}
+				used[c] = true
+			}
+		}
+		color[i] = leastUnused(used)
+		count = max(count, color[i]+1)
+	}
+	return colorMap(nodes, color), count
+}
+
+// DSaturColoring colors the nodes using the DSatur heuristic: the next node
+// to color is the one whose neighbors have the most distinct colors (its
+// saturation), with ties broken by degree; it's given the least color not
+// used by its neighbors. This is exact for bipartite graphs (using at most two
+// colors), and typically uses fewer colors than GreedyColoring. Returns the
+// color of each node, and the number of colors used.
+func (g *Graph[N, W]) DSaturColoring() (colors map[N]int, count int) {
+	nodes, adj := g.neighborIndices()
+	type candidate struct {
+		node, saturation int
+	}
+	// The uncolored nodes are kept in a priority queue, by saturation, then
+	// degree, then index. Saturation only increases, so a node is pushed again
+	// when its saturation changes; outdated entries are skipped.
+	pq := heap.NewHeap(func(a, b candidate) int {
+		if c := cmp.Compare(b.saturation, a.saturation); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(len(adj[b.node]), len(adj[a.node])); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.node, b.node)
+	})
+	color := make([]int, len(nodes))
+	neighborColors := make([]map[int]bool, len(nodes))
+	for i := range nodes {
+		color[i] = noIndex
+		neighborColors[i] = map[int]bool{}
+		pq.Push(candidate{i, 0})
+	}
+	used := []bool{}
+	for pq.Size() > 0 {
+		top, _ := pq.Pop()
+		i := top.node
+		if color[i] != noIndex || top.saturation != len(neighborColors[i]) {
+			continue
+		}
+		clear(used)
+		used = append(used, make([]bool, count+1-len(used))...)
+		for c := range neighborColors[i] {
+			used[c] = true
+		}
+		color[i] = leastUnused(used)
+		count = max(count, color[i]+1)
+		for _, j := range adj[i] {
+			if color[j] == noIndex && !neighborColors[j][color[i]] {
+				neighborColors[j][color[i]] = true
+				pq.Push(candidate{j, len(neighborColors[j])})
+			}
+		}
+	}
+	return colorMap(nodes, color), count
+}
+
+// leastUnused returns the least color that is not used.
+func leastUnused(used []bool) int {
+	for c, u := range used {
+		if !u {
+			return c
+		}
+	}
+	return len(used)
+}
+
+func colorMap[N comparable](nodes []N, color []int) map[N]int {
+	colors := make(map[N]int, len(nodes))
+	for i, v := range nodes {
+		colors[v] = color[i]
+	}
+	return colors
+}
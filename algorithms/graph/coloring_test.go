@@ -0,0 +1,83 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+// assertColoring asserts that every node has one of the colors, and that no
+// two neighbors have the same color
+func assertColoring[W Weight](t *testing.T, g *Graph[int, W], colors map[int]int, count int) {
+	t.Helper()
+	assert.Equal(t, g.NodeCount(), len(colors))
+	for _, c := range colors {
+		assert.True(t, c >= 0 && c < count)
+	}
+	g.visitEdges(func(from, to int, _ W) {
+		if from != to {
+			assert.NotEqual(t, colors[from], colors[to])
+		}
+	})
+}
+
+func TestGreedyColoring(t *testing.T) {
+	g := cliques(1, 5)
+	colors, count := g.GreedyColoring()
+	assertColoring(t, g, colors, count)
+	assert.Equal(t, 5, count)
+
+	g = starGraph(6, Directed)
+	colors, count = g.GreedyColoring()
+	assertColoring(t, g, colors, count)
+	assert.Equal(t, 2, count)
+	// the center has the greatest degree, so is colored first
+	assert.Equal(t, 0, colors[0])
+
+	g = ErdosRenyi(300, 0.05, GeneratorOptions[int]{Seed: 1})
+	colors, count = g.GreedyColoring()
+	assertColoring(t, g, colors, count)
+	stats := g.Stats(1, 1)
+	assert.True(t, count <= stats.MaxDegree+1)
+
+	colors, count = NewGraph[int, int](Undirected).GreedyColoring()
+	assert.Equal(t, 0, count)
+	assert.Equal(t, 0, len(colors))
+}
+
+func TestDSaturColoring(t *testing.T) {
+	g := cliques(1, 5)
+	colors, count := g.DSaturColoring()
+	assertColoring(t, g, colors, count)
+	assert.Equal(t, 5, count)
+
+	// bipartite graphs use two colors
+	g = Grid(8, 8, GeneratorOptions[int]{})
+	colors, count = g.DSaturColoring()
+	assertColoring(t, g, colors, count)
+	assert.Equal(t, 2, count)
+
+	// odd cycles require three
+	g = WattsStrogatz(7, 2, 0, GeneratorOptions[int]{})
+	colors, count = g.DSaturColoring()
+	assertColoring(t, g, colors, count)
+	assert.Equal(t, 3, count)
+
+	// self-loops are ignored
+	g = NewGraphWithPolicy[int, int](Directed, EdgePolicy{SelfLoops: true})
+	g.AddEdge(0, 0, 1)
+	g.AddEdge(0, 1, 1)
+	colors, count = g.DSaturColoring()
+	assertColoring(t, g, colors, count)
+	assert.Equal(t, 2, count)
+
+	g = ErdosRenyi(300, 0.05, GeneratorOptions[int]{Seed: 1})
+	colors, count = g.DSaturColoring()
+	assertColoring(t, g, colors, count)
+	_, greedy := g.GreedyColoring()
+	assert.True(t, count <= greedy)
+
+	_, count = NewGraph[int, int](Undirected).DSaturColoring()
+	assert.Equal(t, 0, count)
+}
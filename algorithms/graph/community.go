@@ -0,0 +1,356 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+// Community detection: partitioning the nodes of a graph into communities
+// (clusters) that are densely connected internally, and sparsely connected to
+// each other. Edge direction is ignored, and edge weights (which should be
+// positive) give the strength of each connection.
+
+import (
+	"math/rand"
+	"slices"
+)
+
+// Communities is an assignment of the nodes of a graph to communities, along
+// with the modularity of the assignment.
+type Communities[N comparable] struct {
+	assignment map[N]int
+	count      int
+	modularity float64
+}
+
+// Community returns the community of the given node. Communities are numbered
+// from 0 to Count()-1. If there is no such node, found will be false.
+func (c *Communities[N]) Community(n N) (community int, found bool) {
+	community, found = c.assignment[n]
+	return
+}
+
+// Assignment returns the community of each node.
+func (c *Communities[N]) Assignment() map[N]int {
+	return c.assignment
+}
+
+// Count returns the number of communities.
+func (c *Communities[N]) Count() int {
+	return c.count
+}
+
+// Members returns the nodes in each community.
+func (c *Communities[N]) Members() [][]N {
+	members := make([][]N, c.count)
+	for v, community := range c.assignment {
+		members[community] = append(members[community], v)
+	}
+	for _, m := range members {
+		slices.SortStableFunc(m, compareNodes[N])
+	}
+	return members
+}
+
+// Modularity returns the modularity of the assignment (see Graph.Modularity.)
+func (c *Communities[N]) Modularity() float64 {
+	return c.modularity
+}
+
+// Modularity returns the modularity of the given assignment of nodes to
+// communities: the fraction of the total edge weight that is within
+// communities, less the fraction expected if edges were placed at random
+// (while preserving the weighted degree of each node.) Ranges from -1/2 to 1;
+// higher values indicate a stronger community structure. Nodes that aren't
+// assigned are considered to each be in their own community.
+func (g *Graph[N, W]) Modularity(assignment map[N]int) float64 {
+	wg, nodes := g.weightedGraph()
+	communities := make([]int, len(nodes))
+	next := len(nodes) // for unassigned nodes
+	for i, v := range nodes {
+		if c, found := assignment[v]; found {
+			communities[i] = c
+		} else {
+			communities[i] = next
+			next++
+		}
+	}
+	return wg.modularity(communities)
+}
+
+// LabelPropagation detects communities using asynchronous label propagation.
+// Each node starts with its own label; then, repeatedly, the nodes (in random
+// order) adopt the label with the greatest total weight among their neighbors,
+// with ties broken at random. This stops once every node has such a label.
+// The randomness is determined by the given seed. Runs in near-linear time,
+// but the communities found vary with the seed.
+func (g *Graph[N, W]) LabelPropagation(seed int64) Communities[N] {
+	const maxIterations = 100
+	wg, nodes := g.weightedGraph()
+	rnd := rand.New(rand.NewSource(seed))
+	labels := identity(len(nodes))
+	order := identity(len(nodes))
+	weights := map[int]float64{} // weight of each neighboring label
+	best := []int{}              // labels with the greatest weight
+	for range maxIterations {
+		rnd.Shuffle(len(order), func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+		changed := false
+		for _, i := range order {
+			if len(wg.adj[i]) == 0 {
+				continue
+			}
+			clear(weights)
+			for _, e := range wg.adj[i] {
+				weights[labels[e.node]] += e.weight
+			}
+			best = best[:0]
+			maxWeight := 0.0
+			for _, e := range wg.adj[i] {
+				// visit labels in a fixed order, so that ties are broken
+				// consistently for a given seed
+				label := labels[e.node]
+				if w := weights[label]; len(best) == 0 || w > maxWeight {
+					best, maxWeight = append(best[:0], label), w
+				} else if w == maxWeight && !slices.Contains(best, label) {
+					best = append(best, label)
+				}
+			}
+			if !slices.Contains(best, labels[i]) {
+				labels[i] = best[rnd.Intn(len(best))]
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+	return newCommunities(nodes, labels, wg.modularity(labels))
+}
+
+// Louvain detects communities using the Louvain method, which greedily
+// optimizes modularity. In each pass, nodes are repeatedly moved to the
+// neighboring community that most increases modularity, until no move
+// improves it; the communities are then merged into single nodes, and the
+// next pass runs on the resulting graph. This stops once a pass makes no
+// moves. The result is deterministic when the node type is ordered (e.g.,
+// integers or strings); otherwise nodes are visited in no particular order,
+// and the communities found may vary from run to run.
+func (g *Graph[N, W]) Louvain() Communities[N] {
+	original, nodes := g.weightedGraph()
+	wg := original
+	// community of each of the original nodes
+	assignment := identity(len(nodes))
+	for {
+		communities, moved := wg.localMoves()
+		if !moved {
+			break
+		}
+		count := renumber(communities)
+		for i, c := range assignment {
+			assignment[i] = communities[c]
+		}
+		wg = wg.aggregate(communities, count)
+	}
+	return newCommunities(nodes, assignment, original.modularity(assignment))
+}
+
+func newCommunities[N comparable](nodes []N, labels []int, modularity float64) Communities[N] {
+	c := Communities[N]{
+		assignment: make(map[N]int, len(nodes)),
+		count:      renumber(labels),
+		modularity: modularity,
+	}
+	for i, v := range nodes {
+		c.assignment[v] = labels[i]
+	}
+	return c
+}
+
+// renumber numbers the labels consecutively from zero, in order of first
+// appearance, and returns the number of distinct labels.
+func renumber(labels []int) int {
+	numbers := map[int]int{}
+	for i, label := range labels {
+		number, found := numbers[label]
+		if !found {
+			number = len(numbers)
+			numbers[label] = number
+		}
+		labels[i] = number
+	}
+	return len(numbers)
+}
+
+func identity(n int) []int {
+	labels := make([]int, n)
+	for i := range labels {
+		labels[i] = i
+	}
+	return labels
+}
+
+// weightedGraph is an undirected graph, with nodes numbered 0..n-1, and
+// floating-point weights, used for community detection. Parallel edges are
+// merged, and self-loops are recorded separately.
+type weightedGraph struct {
+	adj      [][]weightedEdge
+	selfLoop []float64 // twice the weight of each node's self-loops
+	degree   []float64 // weighted degree of each node
+	total    float64   // sum of the degrees (twice the total edge weight)
+}
+
+type weightedEdge struct {
+	node   int
+	weight float64
+}
+
+// weightedGraph converts the graph to a weighted graph, along with the node
+// represented by each index.
+func (g *Graph[N, W]) weightedGraph() (*weightedGraph, []N) {
+	nodes := g.sortedNodes()
+	index := make(map[N]int, len(nodes))
+	for i, v := range nodes {
+		index[v] = i
+	}
+	weights := make([]map[int]float64, len(nodes))
+	for i := range weights {
+		weights[i] = map[int]float64{}
+	}
+	for v, node := range g.nodes {
+		for _, e := range node.outgoing {
+			i, j := index[v], index[e.node]
+			weights[i][j] += float64(e.weight)
+			if i != j {
+				weights[j][i] += float64(e.weight)
+			}
+		}
+	}
+	wg := newWeightedGraph(len(nodes))
+	for i, w := range weights {
+		for j, weight := range w {
+			if i == j {
+				wg.selfLoop[i] += 2 * weight
+			} else {
+				wg.adj[i] = append(wg.adj[i], weightedEdge{j, weight})
+			}
+		}
+		// order the neighbors, so that results are deterministic
+		slices.SortFunc(wg.adj[i], func(a, b weightedEdge) int {
+			return a.node - b.node
+		})
+	}
+	wg.initDegrees()
+	return wg, nodes
+}
+
+func newWeightedGraph(n int) *weightedGraph {
+	return &weightedGraph{
+		adj:      make([][]weightedEdge, n),
+		selfLoop: make([]float64, n),
+		degree:   make([]float64, n),
+	}
+}
+
+func (wg *weightedGraph) initDegrees() {
+	wg.total = 0
+	for i, edges := range wg.adj {
+		wg.degree[i] = wg.selfLoop[i]
+		for _, e := range edges {
+			wg.degree[i] += e.weight
+		}
+		wg.total += wg.degree[i]
+	}
+}
+
+// modularity computes the modularity of the given assignment of nodes to
+// communities.
+func (wg *weightedGraph) modularity(communities []int) float64 {
+	if wg.total == 0 {
+		return 0
+	}
+	internal := map[int]float64{} // twice the weight of edges within each community
+	degree := map[int]float64{}   // total degree of each community
+	for i, c := range communities {
+		internal[c] += wg.selfLoop[i]
+		degree[c] += wg.degree[i]
+		for _, e := range wg.adj[i] {
+			if communities[e.node] == c {
+				internal[c] += e.weight
+			}
+		}
+	}
+	q := 0.0
+	for c, d := range degree {
+		q += internal[c]/wg.total - (d/wg.total)*(d/wg.total)
+	}
+	return q
+}
+
+// localMoves performs the first phase of a pass of the Louvain method: moving
+// nodes between communities until modularity can't be increased. Returns
+// the community of each node, and whether any node was moved.
+func (wg *weightedGraph) localMoves() (communities []int, moved bool) {
+	// ignore gains smaller than this, to avoid moves due to rounding errors
+	const epsilon = 1e-12
+	n := len(wg.adj)
+	communities = identity(n)
+	degree := slices.Clone(wg.degree) // total degree of each community
+	weights := map[int]float64{}      // weight of the edges to each neighboring community
+	for improved := wg.total > 0; improved; {
+		improved = false
+		for i := range n {
+			ci := communities[i]
+			clear(weights)
+			for _, e := range wg.adj[i] {
+				weights[communities[e.node]] += e.weight
+			}
+			// remove the node from its community, and then find the
+			// community where it increases modularity the most
+			degree[ci] -= wg.degree[i]
+			gain := func(c int) float64 {
+				return weights[c] - degree[c]*wg.degree[i]/wg.total
+			}
+			best, bestGain := ci, gain(ci)
+			for _, e := range wg.adj[i] {
+				if c := communities[e.node]; gain(c) > bestGain+epsilon {
+					best, bestGain = c, gain(c)
+				}
+			}
+			degree[best] += wg.degree[i]
+			if best != ci {
+				communities[i] = best
+				improved, moved = true, true
+			}
+		}
+	}
+	return
+}
+
+// aggregate constructs the graph whose nodes are the given communities (which
+// are numbered 0..count-1.)
+func (wg *weightedGraph) aggregate(communities []int, count int) *weightedGraph {
+	weights := make([]map[int]float64, count)
+	for i := range weights {
+		weights[i] = map[int]float64{}
+	}
+	agg := newWeightedGraph(count)
+	for i, edges := range wg.adj {
+		ci := communities[i]
+		agg.selfLoop[ci] += wg.selfLoop[i]
+		for _, e := range edges {
+			if cj := communities[e.node]; ci == cj {
+				agg.selfLoop[ci] += e.weight
+			} else {
+				weights[ci][cj] += e.weight
+			}
+		}
+	}
+	for i, w := range weights {
+		for j, weight := range w {
+			agg.adj[i] = append(agg.adj[i], weightedEdge{j, weight})
+		}
+		slices.SortFunc(agg.adj[i], func(a, b weightedEdge) int {
+			return a.node - b.node
+		})
+	}
+	agg.initDegrees()
+	return agg
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+// cliques creates a graph of k cliques, each of n nodes, connected in a ring
+// by a single edge between consecutive cliques.
+func cliques(k, n int) *Graph[int, int] {
+	g := NewGraph[int, int](Undirected)
+	for c := range k {
+		for i := range n {
+			for j := i + 1; j < n; j++ {
+				g.AddEdge(c*n+i, c*n+j, 1)
+			}
+		}
+		if k > 1 {
+			g.AddEdge(c*n, ((c+1)%k)*n+1, 1)
+		}
+	}
+	return g
+}
+
+func TestModularity(t *testing.T) {
+	g := cliques(2, 4)
+	// each clique has 6 internal edges, and one of the 2 bridges
+	expected := 2 * (12.0/28.0 - (14.0/28.0)*(14.0/28.0))
+	assignment := map[int]int{}
+	for i := range 8 {
+		assignment[i] = i / 4
+	}
+	assert.EqualEpsilon(t, expected, g.Modularity(assignment), 1e-9)
+	for i := range 8 {
+		assignment[i] = 0
+	}
+	assert.EqualEpsilon(t, 0, g.Modularity(assignment), 1e-9)
+	// every node on its own
+	assert.True(t, g.Modularity(map[int]int{}) < 0)
+	assert.Equal(t, 0.0, NewGraph[int, int](Directed).Modularity(nil))
+}
+
+func TestLouvain(t *testing.T) {
+	g := cliques(4, 5)
+	c := g.Louvain()
+	assert.Equal(t, 4, c.Count())
+	for i := range 20 {
+		community, found := c.Community(i)
+		assert.True(t, found)
+		first, _ := c.Community(i / 5 * 5)
+		assert.Equal(t, first, community)
+	}
+	assert.EqualEpsilon(t, g.Modularity(c.Assignment()), c.Modularity(), 1e-9)
+	assert.DeepEqual(t, []int{0, 1, 2, 3, 4}, c.Members()[0])
+	assert.DeepEqual(t, c, g.Louvain())
+
+	// weights: the heavy edges form the communities
+	g = NewGraph[int, int](Undirected)
+	g.AddEdge(0, 1, 10)
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(2, 3, 10)
+	g.AddEdge(3, 0, 1)
+	c = g.Louvain()
+	assert.Equal(t, 2, c.Count())
+	assert.Equal(t, c.Assignment()[0], c.Assignment()[1])
+	assert.Equal(t, c.Assignment()[2], c.Assignment()[3])
+
+	c = NewGraph[int, int](Undirected).Louvain()
+	assert.Equal(t, 0, c.Count())
+}
+
+func TestLabelPropagation(t *testing.T) {
+	g := cliques(4, 5)
+	g.AddNode(100)
+	c := g.LabelPropagation(1)
+	t.Logf("communities: %v", c.Members())
+	assert.DeepEqual(t, c, g.LabelPropagation(1))
+	assert.EqualEpsilon(t, g.Modularity(c.Assignment()), c.Modularity(), 1e-9)
+	// the isolated node is in a community of its own
+	community, _ := c.Community(100)
+	assert.DeepEqual(t, []int{100}, c.Members()[community])
+	for i := range 20 {
+		community, _ := c.Community(i)
+		first, _ := c.Community(i / 5 * 5)
+		assert.Equal(t, first, community)
+	}
+	assert.True(t, c.Count() <= 5)
+}
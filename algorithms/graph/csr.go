@@ -0,0 +1,308 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"cmp"
+	"errors"
+	"io"
+
+	"github.com/tommika/gorilla/algorithms/heap"
+)
+
+// CSR is an immutable graph stored in compressed sparse row format, suited to
+// very large graphs. Nodes are identified by dense indices 0..n-1, and the
+// neighbors of every node are held in a single pair of arrays (targets and
+// weights), with node i's neighbors at offsets[i] through offsets[i+1]-1.
+// Compared to Graph, this uses much less memory, and neighbors can be
+// iterated without allocating. If the graph is undirected, each edge is
+// stored in the neighbors of both of its nodes.
+type CSR[N comparable, W Weight] struct {
+	directed GraphType
+	numEdges int
+	nodes    []N // index to node
+	index    map[N]int32
+	offsets  []int
+	targets  []int32
+	weights  []W
+}
+
+// noIndex marks the absence of a node index; e.g., the predecessor of the
+// source in a search.
+const noIndex = -1
+
+// CSRFromGraph constructs a CSR graph from the given graph. Nodes are indexed
+// in order of their value, when the node type is ordered (see
+// ToAdjacencyMatrix.)
+func CSRFromGraph[N comparable, W Weight](g *Graph[N, W]) *CSR[N, W] {
+	c := &CSR[N, W]{
+		directed: g.directed,
+		numEdges: g.numEdges,
+		nodes:    g.sortedNodes(),
+	}
+	c.initIndex()
+	c.offsets = make([]int, len(c.nodes)+1)
+	for i, v := range c.nodes {
+		degree := len(g.nodes[v].outgoing)
+		if !g.directed {
+			degree += len(g.nodes[v].incoming)
+		}
+		c.offsets[i+1] = c.offsets[i] + degree
+	}
+	c.targets = make([]int32, c.offsets[len(c.nodes)])
+	c.weights = make([]W, len(c.targets))
+	for i, v := range c.nodes {
+		j := c.offsets[i]
+		add := func(edges []edge[N, W]) {
+			for _, e := range edges {
+				c.targets[j], c.weights[j] = c.index[e.node], e.weight
+				j++
+			}
+		}
+		add(g.nodes[v].outgoing)
+		if !g.directed {
+			add(g.nodes[v].incoming)
+		}
+	}
+	return c
+}
+
+// CSRFromEdges constructs a CSR graph directly from a stream of edges, without
+// first constructing a Graph. Nodes are indexed in order of their first
+// appearance in the stream. As with the default policy of Graph, parallel
+// edges are kept, and self-loops and edges with a NaN weight are ignored.
+func CSRFromEdges[N comparable, W Weight](in io.Reader, readEdge EdgeReader[N, W], directed GraphType) (*CSR[N, W], error) {
+	c := &CSR[N, W]{
+		directed: directed,
+		index:    map[N]int32{},
+	}
+	intern := func(n N) int32 {
+		i, found := c.index[n]
+		if !found {
+			i = int32(len(c.nodes))
+			c.index[n] = i
+			c.nodes = append(c.nodes, n)
+		}
+		return i
+	}
+	var from, to []int32
+	var weights []W
+	for {
+		f, t, w, err := readEdge(in)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		fi, ti := intern(f), intern(t)
+		if fi == ti || isNaN(w) {
+			continue
+		}
+		from, to, weights = append(from, fi), append(to, ti), append(weights, w)
+	}
+	c.numEdges = len(from)
+	// count the neighbors of each node, and then place each edge
+	c.offsets = make([]int, len(c.nodes)+1)
+	for i := range from {
+		c.offsets[from[i]+1]++
+		if !directed {
+			c.offsets[to[i]+1]++
+		}
+	}
+	for i := range c.nodes {
+		c.offsets[i+1] += c.offsets[i]
+	}
+	c.targets = make([]int32, c.offsets[len(c.nodes)])
+	c.weights = make([]W, len(c.targets))
+	next := make([]int, len(c.nodes))
+	copy(next, c.offsets)
+	place := func(u, v int32, w W) {
+		c.targets[next[u]], c.weights[next[u]] = v, w
+		next[u]++
+	}
+	for i := range from {
+		place(from[i], to[i], weights[i])
+		if !directed {
+			place(to[i], from[i], weights[i])
+		}
+	}
+	return c, nil
+}
+
+func (c *CSR[N, W]) initIndex() {
+	c.index = make(map[N]int32, len(c.nodes))
+	for i, v := range c.nodes {
+		c.index[v] = int32(i)
+	}
+}
+
+func (c *CSR[N, W]) Type() GraphType {
+	return c.directed
+}
+
+func (c *CSR[N, W]) NodeCount() int {
+	return len(c.nodes)
+}
+
+func (c *CSR[N, W]) EdgeCount() int {
+	return c.numEdges
+}
+
+// Node returns the node with the given index.
+func (c *CSR[N, W]) Node(i int) N {
+	return c.nodes[i]
+}
+
+// Index returns the index of the given node. If there is no such node, found
+// will be false.
+func (c *CSR[N, W]) Index(n N) (i int, found bool) {
+	i32, found := c.index[n]
+	return int(i32), found
+}
+
+// Degree returns the number of neighbors of the node with the given index.
+func (c *CSR[N, W]) Degree(i int) int {
+	return c.offsets[i+1] - c.offsets[i]
+}
+
+// Neighbors returns the indices of the neighbors of the node with the given
+// index, along with the weights of the corresponding edges. The returned
+// slices share the graph's storage, and must not be modified.
+func (c *CSR[N, W]) Neighbors(i int) (targets []int32, weights []W) {
+	lo, hi := c.offsets[i], c.offsets[i+1]
+	return c.targets[lo:hi:hi], c.weights[lo:hi:hi]
+}
+
+// CSRSearch is the result of a search of a CSR graph from a source node: the
+// distance to each reached node (hops, for a breadth-first search) and its
+// predecessor on the path from the source. Nodes are identified by index.
+type CSRSearch[D Weight] struct {
+	source  int
+	reached []bool
+	dist    []D
+	pred    []int32
+	order   []int32 // reached nodes, in the order they were visited
+}
+
+func newCSRSearch[D Weight](n, s int) CSRSearch[D] {
+	cs := CSRSearch[D]{
+		source:  s,
+		reached: make([]bool, n),
+		dist:    make([]D, n),
+		pred:    make([]int32, n),
+		order:   make([]int32, 0, n),
+	}
+	for i := range cs.pred {
+		cs.pred[i] = noIndex
+	}
+	return cs
+}
+
+// Source returns the index of the node from which the search started.
+func (cs *CSRSearch[D]) Source() int {
+	return cs.source
+}
+
+// Reached determines if the node with the given index was reached.
+func (cs *CSRSearch[D]) Reached(v int) bool {
+	return cs.reached[v]
+}
+
+// Distance returns the distance from the source to the node with the given
+// index. If the node wasn't reached, found will be false.
+func (cs *CSRSearch[D]) Distance(v int) (dist D, found bool) {
+	return cs.dist[v], cs.reached[v]
+}
+
+// Pred returns the index of the predecessor of the node with the given index,
+// or -1 if the node is the source, or wasn't reached.
+func (cs *CSRSearch[D]) Pred(v int) int {
+	return int(cs.pred[v])
+}
+
+// Order returns the indices of the reached nodes, in the order in which they
+// were visited.
+func (cs *CSRSearch[D]) Order() []int32 {
+	return cs.order
+}
+
+// FindPath returns the path (of node indices) from the source to the node with
+// the given index, or nil if the node wasn't reached.
+func (cs *CSRSearch[D]) FindPath(v int) (path []int) {
+	if !cs.reached[v] {
+		return nil
+	}
+	n := 1
+	for u := v; u != cs.source; u = int(cs.pred[u]) {
+		n++
+	}
+	path = make([]int, n)
+	for i, u := n-1, v; i >= 0; i, u = i-1, int(cs.pred[u]) {
+		path[i] = u
+	}
+	return
+}
+
+// BFS performs a breadth-first search of the graph, starting from the node
+// with the given index. As with Graph.BFS, a maxDepth of zero means no limit;
+// otherwise only nodes within that many hops are visited (so a negative
+// maxDepth visits only the source.)
+func (c *CSR[N, W]) BFS(s int, maxDepth int) CSRSearch[int] {
+	cs := newCSRSearch[int](len(c.nodes), s)
+	cs.reached[s] = true
+	cs.order = append(cs.order, int32(s))
+	// the visit order doubles as the queue
+	for head := 0; head < len(cs.order); head++ {
+		u := cs.order[head]
+		if maxDepth != 0 && cs.dist[u] >= maxDepth {
+			continue
+		}
+		targets, _ := c.Neighbors(int(u))
+		for _, v := range targets {
+			if !cs.reached[v] {
+				cs.reached[v] = true
+				cs.dist[v] = cs.dist[u] + 1
+				cs.pred[v] = u
+				cs.order = append(cs.order, v)
+			}
+		}
+	}
+	return cs
+}
+
+// Dijkstra computes the shortest paths from the node with the given index to
+// all reachable nodes, using Dijkstra's algorithm. Edge weights must not be
+// negative; use BellmanFord on a Graph otherwise.
+func (c *CSR[N, W]) Dijkstra(s int) CSRSearch[W] {
+	type pqItem struct {
+		v    int32
+		dist W
+	}
+	cs := newCSRSearch[W](len(c.nodes), s)
+	done := make([]bool, len(c.nodes))
+	q := heap.NewPriorityQueue(func(a, b pqItem) int {
+		return cmp.Compare(a.dist, b.dist)
+	})
+	cs.reached[s] = true
+	q.Enqueue(pqItem{int32(s), cs.dist[s]})
+	for q.Size() > 0 {
+		head := q.MustDequeue()
+		if done[head.v] {
+			// stale entry; node was already reached by a shorter path
+			continue
+		}
+		done[head.v] = true
+		cs.order = append(cs.order, head.v)
+		targets, weights := c.Neighbors(int(head.v))
+		for i, v := range targets {
+			d := head.dist + weights[i]
+			if !cs.reached[v] || d < cs.dist[v] {
+				cs.reached[v] = true
+				cs.dist[v] = d
+				cs.pred[v] = head.v
+				q.Enqueue(pqItem{v, d})
+			}
+		}
+	}
+	return cs
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"bytes"
+	"math/rand"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/must"
+)
+
+func TestCSRFromGraph(t *testing.T) {
+	for _, directed := range []GraphType{Directed, Undirected} {
+		g := negativeEdgeGraph()
+		if !directed {
+			g = NewGraph[string, int](Undirected)
+			g.AddEdge("s", "t", 6)
+			g.AddEdge("s", "y", 7)
+			g.AddEdge("t", "x", 5)
+			g.AddEdge("y", "z", 9)
+		}
+		c := CSRFromGraph(g)
+		assert.Equal(t, directed, c.Type())
+		assert.Equal(t, g.NodeCount(), c.NodeCount())
+		assert.Equal(t, g.EdgeCount(), c.EdgeCount())
+		for i := range c.NodeCount() {
+			v := c.Node(i)
+			j, found := c.Index(v)
+			assert.True(t, found)
+			assert.Equal(t, i, j)
+			assert.Equal(t, len(g.outgoing(v)), c.Degree(i))
+			targets, weights := c.Neighbors(i)
+			for k, u := range targets {
+				w, found := g.GetEdgeWeight(v, c.Node(int(u)))
+				assert.True(t, found)
+				assert.Equal(t, w, weights[k])
+			}
+		}
+		_, found := c.Index("bogus")
+		assert.False(t, found)
+	}
+}
+
+func TestCSRFromEdges(t *testing.T) {
+	const edges = "1|2|3\n2|3|4\n3|3|1\n3|1|5\n"
+	c, err := CSRFromEdges(stringReader(edges), readEdge, Undirected)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, c.NodeCount())
+	assert.Equal(t, 3, c.EdgeCount())
+	assert.Equal(t, 1, c.Node(0))
+	targets, weights := c.Neighbors(2)
+	assert.DeepEqual(t, []int32{1, 0}, targets)
+	assert.DeepEqual(t, []int{4, 5}, weights)
+
+	_, err = CSRFromEdges(stringReader("1|2|x\n"), readEdge, Directed)
+	assert.NotNil(t, err)
+}
+
+func TestCSRBFS(t *testing.T) {
+	g := NewGraph[int, int](Undirected)
+	for i := range 10 {
+		g.AddEdge(i, i+1, 1)
+	}
+	g.AddEdge(20, 21, 1)
+	c := CSRFromGraph(g)
+	s, _ := c.Index(5)
+	cs := c.BFS(s, 0)
+	assert.Equal(t, s, cs.Source())
+	assert.Equal(t, 11, len(cs.Order()))
+	d, found := cs.Distance(0)
+	assert.True(t, found)
+	assert.Equal(t, 5, d)
+	assert.Equal(t, -1, cs.Pred(s))
+	assert.Equal(t, s, cs.Pred(4))
+	assert.Equal(t, 4, cs.Pred(3))
+	i20, _ := c.Index(20)
+	assert.False(t, cs.Reached(i20))
+	assert.Nil(t, cs.FindPath(i20))
+	assert.DeepEqual(t, []int{5, 6, 7, 8}, cs.FindPath(8))
+
+	cs = c.BFS(s, 2)
+	assert.Equal(t, 5, len(cs.Order()))
+	assert.False(t, cs.Reached(8))
+
+	// agrees with BFS of the graph
+	bft := g.BFS(5, 0)
+	cs = c.BFS(s, 0)
+	assert.Equal(t, bft.NodeCount(), len(cs.Order()))
+	for _, maxDepth := range []int{-1, 1, 3} {
+		bft = g.BFS(5, maxDepth)
+		cs = c.BFS(s, maxDepth)
+		assert.Equal(t, bft.NodeCount(), len(cs.Order()))
+	}
+	cs = c.BFS(s, -1)
+	assert.Equal(t, 1, len(cs.Order()))
+}
+
+func TestCSRDijkstra(t *testing.T) {
+	g := NewGraph[string, float64](Directed)
+	g.AddEdge("s", "t", 10)
+	g.AddEdge("s", "y", 5)
+	g.AddEdge("t", "x", 1)
+	g.AddEdge("t", "y", 2)
+	g.AddEdge("y", "t", 3)
+	g.AddEdge("y", "x", 9)
+	g.AddEdge("y", "z", 2)
+	g.AddEdge("z", "x", 6)
+	g.AddEdge("x", "z", 4)
+	g.AddNode("u")
+	c := CSRFromGraph(g)
+	s, _ := c.Index("s")
+	cs := c.Dijkstra(s)
+	for v, expected := range map[string]float64{"s": 0, "t": 8, "x": 9, "y": 5, "z": 7} {
+		i, _ := c.Index(v)
+		d, found := cs.Distance(i)
+		assert.True(t, found)
+		assert.Equal(t, expected, d)
+	}
+	x, _ := c.Index("x")
+	path := []string{}
+	for _, i := range cs.FindPath(x) {
+		path = append(path, c.Node(i))
+	}
+	assert.DeepEqual(t, []string{"s", "y", "t", "x"}, path)
+	u, _ := c.Index("u")
+	assert.False(t, cs.Reached(u))
+}
+
+// randomEdges generates a random edge list, in the format read by readEdge
+func randomEdges(nodes, edges int) []byte {
+	rnd := rand.New(rand.NewSource(1))
+	buffer := bytes.Buffer{}
+	for range edges {
+		writeEdge(&buffer, rnd.Intn(nodes), rnd.Intn(nodes), 1+rnd.Intn(10))
+	}
+	return buffer.Bytes()
+}
+
+const (
+	benchNodes = 100_000
+	benchEdges = 500_000
+)
+
+var benchEdgeList = sync.OnceValue(func() []byte { return randomEdges(benchNodes, benchEdges) })
+
+// heapInUse returns the number of bytes allocated on the heap after
+// collecting garbage.
+func heapInUse() uint64 {
+	runtime.GC()
+	stats := runtime.MemStats{}
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+func BenchmarkGraphMemory(b *testing.B) {
+	edges := benchEdgeList()
+	for range b.N {
+		before := heapInUse()
+		g := NewGraph[int, int](Undirected)
+		g.ReadEdges(bytes.NewReader(edges), readEdge)
+		b.ReportMetric(float64(heapInUse()-before)/float64(g.EdgeCount()), "bytes/edge")
+		runtime.KeepAlive(g)
+	}
+}
+
+func BenchmarkCSRMemory(b *testing.B) {
+	edges := benchEdgeList()
+	for range b.N {
+		before := heapInUse()
+		c, _ := CSRFromEdges(bytes.NewReader(edges), readEdge, Undirected)
+		b.ReportMetric(float64(heapInUse()-before)/float64(c.EdgeCount()), "bytes/edge")
+		runtime.KeepAlive(c)
+	}
+}
+
+func BenchmarkGraphBFS(b *testing.B) {
+	g := NewGraph[int, int](Undirected)
+	g.ReadEdges(bytes.NewReader(benchEdgeList()), readEdge)
+	b.ResetTimer()
+	for range b.N {
+		g.BFS(0, 0)
+	}
+}
+
+func BenchmarkCSRBFS(b *testing.B) {
+	c, _ := CSRFromEdges(bytes.NewReader(benchEdgeList()), readEdge, Undirected)
+	s, _ := c.Index(0)
+	b.ResetTimer()
+	for range b.N {
+		c.BFS(s, 0)
+	}
+}
+
+func TestCSRBFSMatchesGraph(t *testing.T) {
+	edges := randomEdges(1000, 3000)
+	g := NewGraph[int, int](Directed)
+	g.ReadEdges(bytes.NewReader(edges), readEdge)
+	c, err := CSRFromEdges(bytes.NewReader(edges), readEdge, Directed)
+	assert.Nil(t, err)
+	assert.Equal(t, g.EdgeCount(), c.EdgeCount())
+	bft := g.BFS(0, 0)
+	s, _ := c.Index(0)
+	cs := c.BFS(s, 0)
+	assert.Equal(t, bft.NodeCount(), len(cs.Order()))
+	for _, i := range cs.Order() {
+		path, _ := bft.FindPath(c.Node(int(i)))
+		assert.Equal(t, len(path)-1, must.BeOk(cs.Distance(int(i))))
+	}
+}
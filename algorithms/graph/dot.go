@@ -0,0 +1,396 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+// Reading and writing graphs in the Graphviz DOT language.
+// See: https://graphviz.org/doc/info/lang.html
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// WriteDOT writes the graph in the Graphviz DOT language. All nodes are
+// written (including isolated nodes), followed by all edges. The weight of
+// each edge is written as the "edge_weight" attribute, which Graphviz
+// ignores; Graphviz's "weight" attribute can be given by EdgeAttrs.
+func (g *Graph[N, W]) WriteDOT(out io.Writer, opts ExportOptions[N, W]) error {
+	w := bufio.NewWriter(out)
+	kind, edgeOp := "graph", "--"
+	if g.directed {
+		kind, edgeOp = "digraph", "->"
+	}
+	fmt.Fprintf(w, "%s", kind)
+	if len(opts.Name) > 0 {
+		fmt.Fprintf(w, " %s", dotQuote(opts.Name))
+	}
+	fmt.Fprintf(w, " {\n")
+	nodes := g.sortedNodes()
+	for _, v := range nodes {
+		fmt.Fprintf(w, "  %s%s;\n", dotQuote(opts.nodeId(v)), dotAttrs(opts.nodeAttrs(v)))
+	}
+	for _, v := range nodes {
+		for _, e := range g.nodes[v].outgoing {
+			fmt.Fprintf(w, "  %s %s %s%s;\n",
+				dotQuote(opts.nodeId(v)), edgeOp, dotQuote(opts.nodeId(e.node)),
+				dotAttrs(opts.edgeAttrs(v, e.node, e.weight, dotWeightAttr)))
+		}
+	}
+	fmt.Fprintf(w, "}\n")
+	return w.Flush()
+}
+
+// dotQuote quotes the given identifier
+func dotQuote(id string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(id) + `"`
+}
+
+// dotAttrs formats an attribute list
+func dotAttrs(attrs map[string]string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+	list := []string{}
+	for _, name := range sortedAttrNames(attrs) {
+		list = append(list, dotQuote(name)+"="+dotQuote(attrs[name]))
+	}
+	return " [" + strings.Join(list, ", ") + "]"
+}
+
+// ReadDOT reads a graph written in the Graphviz DOT language. A useful
+// subset of the language is supported: node and edge statements (including
+// chains of edges, such as "a -> b -> c") with attribute lists, quoted
+// identifiers, and comments. Graph attributes and default node and edge
+// attributes are ignored. Subgraphs and ports are not supported. The weight of
+// an edge is read from its "edge_weight" attribute (as written by WriteDOT),
+// or if it has none, its "weight" attribute.
+func ReadDOT[N comparable, W Weight](in io.Reader, opts ImportOptions[N, W]) (*Graph[N, W], error) {
+	p := dotParser{lex: dotLexer{in: bufio.NewReader(in), line: 1}}
+	p.next()
+	if p.tok.is("strict") {
+		p.next()
+	}
+	var directed GraphType
+	switch {
+	case p.tok.is("graph"):
+		directed = Undirected
+	case p.tok.is("digraph"):
+		directed = Directed
+	default:
+		return nil, p.errorf("expected graph or digraph; got %s", p.tok)
+	}
+	p.next()
+	if p.tok.kind == dotId {
+		// name of the graph
+		p.next()
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	b := newBuilder(&opts, directed, dotWeightAttr, weightAttr)
+	for p.err == nil && !p.tok.is("}") {
+		if err := dotStatement(&p, b, directed); err != nil {
+			return nil, err
+		}
+		if p.tok.is(";") {
+			p.next()
+		}
+	}
+	if err := p.expect("}"); err != nil {
+		return nil, err
+	}
+	return b.g, nil
+}
+
+type dotTokenKind int
+
+const (
+	dotEOF   dotTokenKind = iota
+	dotId                 // identifier, number, or quoted string
+	dotPunct              // punctuation, or edge operator
+)
+
+type dotToken struct {
+	kind   dotTokenKind
+	text   string
+	quoted bool
+}
+
+// is determines if the token is the given punctuation or (unquoted) keyword.
+// Keywords are case-independent.
+func (t dotToken) is(s string) bool {
+	return t.kind != dotEOF && !t.quoted && strings.EqualFold(t.text, s)
+}
+
+func (t dotToken) String() string {
+	if t.kind == dotEOF {
+		return "end of input"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+type dotParser struct {
+	lex dotLexer
+	tok dotToken
+	err error
+}
+
+func (p *dotParser) next() {
+	if p.err == nil {
+		p.tok, p.err = p.lex.token()
+	}
+}
+
+func (p *dotParser) errorf(format string, args ...any) error {
+	if p.err != nil {
+		return p.err
+	}
+	return fmt.Errorf("line %d: %s", p.lex.line, fmt.Sprintf(format, args...))
+}
+
+func (p *dotParser) expect(s string) error {
+	if !p.tok.is(s) {
+		return p.errorf("expected %q; got %s", s, p.tok)
+	}
+	p.next()
+	return p.err
+}
+
+// dotStatement parses a single statement of the graph body
+func dotStatement[N comparable, W Weight](p *dotParser, b *builder[N, W], directed GraphType) error {
+	switch {
+	case p.tok.is("graph") || p.tok.is("node") || p.tok.is("edge"):
+		// default attributes; ignored
+		p.next()
+		_, err := p.attrList()
+		return err
+	case p.tok.is("subgraph") || p.tok.is("{"):
+		return p.errorf("subgraphs are not supported")
+	case p.tok.kind != dotId:
+		return p.errorf("unexpected %s", p.tok)
+	}
+	ids := []string{p.tok.text}
+	p.next()
+	if p.tok.is("=") {
+		// graph attribute; ignored
+		p.next()
+		if p.tok.kind != dotId {
+			return p.errorf("expected attribute value; got %s", p.tok)
+		}
+		p.next()
+		return p.err
+	}
+	if p.tok.is(":") {
+		return p.errorf("ports are not supported")
+	}
+	edgeOp := "--"
+	if directed {
+		edgeOp = "->"
+	}
+	for p.tok.is("->") || p.tok.is("--") {
+		if !p.tok.is(edgeOp) {
+			return p.errorf("unexpected edge operator %s", p.tok)
+		}
+		p.next()
+		if p.tok.kind != dotId {
+			return p.errorf("expected node id; got %s", p.tok)
+		}
+		ids = append(ids, p.tok.text)
+		p.next()
+	}
+	attrs, err := p.attrList()
+	if err != nil {
+		return err
+	}
+	if len(ids) == 1 {
+		_, err = b.node(ids[0], attrs)
+	}
+	for i := 1; i < len(ids) && err == nil; i++ {
+		err = b.edge(ids[i-1], ids[i], attrs)
+	}
+	if err != nil {
+		err = fmt.Errorf("line %d: %w", p.lex.line, err)
+	}
+	return err
+}
+
+// attrList parses zero or more bracketed attribute lists.
+func (p *dotParser) attrList() (attrs map[string]string, err error) {
+	attrs = map[string]string{}
+	for p.err == nil && p.tok.is("[") {
+		p.next()
+		for p.err == nil && !p.tok.is("]") {
+			if p.tok.kind != dotId {
+				return nil, p.errorf("expected attribute name; got %s", p.tok)
+			}
+			name := p.tok.text
+			p.next()
+			if err = p.expect("="); err != nil {
+				return nil, err
+			}
+			if p.tok.kind != dotId {
+				return nil, p.errorf("expected attribute value; got %s", p.tok)
+			}
+			attrs[name] = p.tok.text
+			p.next()
+			if p.tok.is(",") || p.tok.is(";") {
+				p.next()
+			}
+		}
+		if err = p.expect("]"); err != nil {
+			return nil, err
+		}
+	}
+	return attrs, p.err
+}
+
+// dotLexer splits DOT input into tokens
+type dotLexer struct {
+	in   *bufio.Reader
+	line int
+}
+
+func (l *dotLexer) read() (rune, bool) {
+	r, _, err := l.in.ReadRune()
+	if err != nil {
+		return 0, false
+	}
+	if r == '\n' {
+		l.line++
+	}
+	return r, true
+}
+
+func (l *dotLexer) unread(r rune) {
+	l.in.UnreadRune()
+	if r == '\n' {
+		l.line--
+	}
+}
+
+func (l *dotLexer) peek() rune {
+	r, ok := l.read()
+	if ok {
+		l.unread(r)
+	}
+	return r
+}
+
+func (l *dotLexer) token() (tok dotToken, err error) {
+	if err = l.skipSpaceAndComments(); err != nil {
+		return
+	}
+	r, ok := l.read()
+	switch {
+	case !ok:
+		tok.kind = dotEOF
+	case r == '"':
+		tok.kind, tok.quoted = dotId, true
+		tok.text, err = l.quoted()
+	case r == '-' && (l.peek() == '>' || l.peek() == '-'):
+		op, _ := l.read()
+		tok.kind, tok.text = dotPunct, string([]rune{r, op})
+	case isDotIdRune(r) || r == '-' || r == '.':
+		text := []rune{r}
+		for r, ok = l.read(); ok && (isDotIdRune(r) || r == '.'); r, ok = l.read() {
+			text = append(text, r)
+		}
+		if ok {
+			l.unread(r)
+		}
+		tok.kind, tok.text = dotId, string(text)
+	case strings.ContainsRune("{}[]=;,:", r):
+		tok.kind, tok.text = dotPunct, string(r)
+	default:
+		err = fmt.Errorf("line %d: unexpected character %q", l.line, r)
+	}
+	return
+}
+
+func isDotIdRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// quoted reads the remainder of a quoted string
+func (l *dotLexer) quoted() (string, error) {
+	sb := strings.Builder{}
+	for {
+		r, ok := l.read()
+		if !ok {
+			return "", fmt.Errorf("line %d: unterminated string", l.line)
+		}
+		switch r {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if r, ok = l.read(); ok {
+				switch r {
+				case 'n':
+					sb.WriteRune('\n')
+				case '"', '\\':
+					sb.WriteRune(r)
+				case '\n':
+					// line continuation
+				default:
+					sb.WriteRune('\\')
+					sb.WriteRune(r)
+				}
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+func (l *dotLexer) skipSpaceAndComments() error {
+	atLineStart := l.line == 1
+	for {
+		r, ok := l.read()
+		switch {
+		case !ok:
+			return nil
+		case r == '\n':
+			atLineStart = true
+		case unicode.IsSpace(r):
+		case r == '#' && atLineStart:
+			// preprocessor output line
+			l.skipLine()
+		case r == '/':
+			switch c, _ := l.read(); c {
+			case '/':
+				l.skipLine()
+				atLineStart = true
+			case '*':
+				if err := l.skipBlockComment(); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("line %d: unexpected character %q", l.line, r)
+			}
+		default:
+			l.unread(r)
+			return nil
+		}
+	}
+}
+
+func (l *dotLexer) skipLine() {
+	for r, ok := l.read(); ok && r != '\n'; r, ok = l.read() {
+	}
+}
+
+func (l *dotLexer) skipBlockComment() error {
+	prev := rune(0)
+	for {
+		r, ok := l.read()
+		if !ok {
+			return fmt.Errorf("line %d: unterminated comment", l.line)
+		}
+		if prev == '*' && r == '/' {
+			return nil
+		}
+		prev = r
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tommika/gorilla/algorithms/matrix"
+	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/must"
+)
+
+func TestWriteReadDOT(t *testing.T) {
+	const ams = `0 1 2 0
+	             0 0 3 0
+	             4 0 0 0
+	             0 0 0 0`
+	am := matrix.ParseMatrix(ams, strconv.Atoi)
+	for _, directed := range []GraphType{Directed, Undirected} {
+		g := GraphFromAdjacencyMatrix(am, directed)
+		g.AddNode(3)
+		buffer := bytes.Buffer{}
+		err := g.WriteDOT(&buffer, ExportOptions[int, int]{
+			Name: "test",
+			NodeAttrs: func(n int) map[string]string {
+				return map[string]string{"label": fmt.Sprintf("Node \"%d\"", n)}
+			},
+		})
+		assert.Nil(t, err)
+		t.Logf("\n%s", buffer.String())
+
+		labels := map[int]string{}
+		gT, err := ReadDOT(&buffer, ImportOptions[int, int]{
+			NodeAttrs: func(n int, attrs map[string]string) {
+				labels[n] = attrs["label"]
+			},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, directed, gT.Type())
+		assert.Equal(t, g.NodeCount(), gT.NodeCount())
+		assert.Equal(t, g.EdgeCount(), gT.EdgeCount())
+		assert.Equal(t, `Node "3"`, labels[3])
+		m, _ := g.ToAdjacencyMatrix()
+		mT, _ := gT.ToAdjacencyMatrix()
+		assert.DeepEqual(t, m.Rows(), mT.Rows())
+	}
+}
+
+func TestReadDOT(t *testing.T) {
+	const dot = `
+# generated by hand
+strict digraph G {
+	// default attributes are ignored
+	graph [rankdir=LR];
+	node [shape=box]
+	rankdir = LR
+	/* a chain
+	   of edges */
+	a -> b -> c [weight=2.5, color=red];
+	"node d" [label="D"; color=blue]
+	c -> "node d" [weight=-1] [style=dashed]
+	e
+}`
+	nodeAttrs := map[string]map[string]string{}
+	g, err := ReadDOT(strings.NewReader(dot), ImportOptions[string, float64]{
+		NodeAttrs: func(n string, attrs map[string]string) {
+			nodeAttrs[n] = attrs
+		},
+	})
+	assert.Nil(t, err)
+	g.Fprint(os.Stderr)
+	assert.Equal(t, 5, g.NodeCount())
+	assert.Equal(t, 3, g.EdgeCount())
+	assert.Equal(t, 2.5, must.BeOk(g.GetEdgeWeight("a", "b")))
+	assert.Equal(t, 2.5, must.BeOk(g.GetEdgeWeight("b", "c")))
+	assert.Equal(t, -1.0, must.BeOk(g.GetEdgeWeight("c", "node d")))
+	assert.Equal(t, "D", nodeAttrs["node d"]["label"])
+	assert.Equal(t, "blue", nodeAttrs["node d"]["color"])
+	assert.True(t, g.HasNode("e"))
+}
+
+func TestWriteDOTWeights(t *testing.T) {
+	g := NewGraph[string, float64](Directed)
+	g.AddEdge("a", "b", -1.5)
+	g.AddEdge("b", "c", 0.25)
+	buffer := bytes.Buffer{}
+	err := g.WriteDOT(&buffer, ExportOptions[string, float64]{
+		EdgeAttrs: func(from, to string, weight float64) map[string]string {
+			return map[string]string{"weight": "2"}
+		},
+	})
+	assert.Nil(t, err)
+	t.Logf("\n%s", buffer.String())
+	assert.True(t, strings.Contains(buffer.String(), `"a" -> "b" ["edge_weight"="-1.5", "weight"="2"];`))
+	gT, err := ReadDOT(&buffer, ImportOptions[string, float64]{})
+	assert.Nil(t, err)
+	assert.Equal(t, -1.5, must.BeOk(gT.GetEdgeWeight("a", "b")))
+	assert.Equal(t, 0.25, must.BeOk(gT.GetEdgeWeight("b", "c")))
+}
+
+func TestReadDOTErrors(t *testing.T) {
+	bad := []string{
+		``,
+		`tree { }`,
+		`digraph { a -> }`,
+		`digraph { a -- b }`,
+		`graph { a -- b [weight=x] }`,
+		`graph { a [label=] }`,
+		`graph { a [label x] }`,
+		`graph { subgraph s { a } }`,
+		`graph { a:p1 -- b }`,
+		`graph { a -- b`,
+		`graph { "a -- b }`,
+		`graph { a /* -- b }`,
+		`graph { a / b }`,
+		`graph { a ! b }`,
+	}
+	for _, dot := range bad {
+		_, err := ReadDOT(strings.NewReader(dot), ImportOptions[string, int]{})
+		t.Logf("%q: %v", dot, err)
+		assert.NotNil(t, err)
+	}
+	_, err := ReadDOT(strings.NewReader("graph {\n\n a -- b }"), ImportOptions[int, int]{})
+	assert.NotNil(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "line 3:"))
+	// numbers must be well-formed
+	malformed := []string{
+		`graph { 1 -- 2 [weight="3x"] }`,
+		`graph { 1 -- 2 [weight=1.5] }`,
+		`graph { 1 -- 2 [weight="1 2"] }`,
+		`graph { 7.9 -- 2 }`,
+		`graph { "12abc" }`,
+	}
+	for _, dot := range malformed {
+		_, err := ReadDOT(strings.NewReader(dot), ImportOptions[int, int]{})
+		t.Logf("%q: %v", dot, err)
+		assert.NotNil(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), "line 1:"))
+	}
+}
+
+func TestBFTreeDOT(t *testing.T) {
+	g := NewGraph[string, int](Undirected)
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("b", "c", 2)
+	g.AddEdge("a", "c", 3)
+	bft := g.BFS("b", 0)
+	assert.Equal(t, "b", bft.Root())
+	tree := bft.ToGraph()
+	assert.Equal(t, 3, tree.NodeCount())
+	assert.Equal(t, 2, tree.EdgeCount())
+	assert.True(t, tree.HasEdge("b", "a"))
+	assert.True(t, tree.HasEdge("b", "c"))
+
+	buffer := bytes.Buffer{}
+	err := bft.WriteDOT(&buffer, ExportOptions[string, int]{})
+	assert.Nil(t, err)
+	t.Logf("\n%s", buffer.String())
+	roots := []string{}
+	gT, err := ReadDOT(&buffer, ImportOptions[string, int]{
+		NodeAttrs: func(n string, attrs map[string]string) {
+			if attrs["root"] == "true" {
+				roots = append(roots, n)
+			}
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, Directed, gT.Type())
+	assert.DeepEqual(t, []string{"b"}, roots)
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+)
+
+// binaryMagic identifies a stream of binary-encoded edges
+var binaryMagic = []byte("GEL1")
+
+// DefaultMaxNameLength is the default limit on the length of a node name read
+// by a BinaryCodec.
+const DefaultMaxNameLength = 64 * 1024
+
+// BinaryCodec reads and writes edges using a compact binary format. The
+// codec's ReadEdge and WriteEdge methods can be used with Graph.ReadEdges and
+// Graph.WriteEdges, respectively.
+//
+// The stream begins with a 4-byte magic number, followed by one record per
+// edge: the from and to nodes, followed by the weight. Each node is assigned
+// an index in order of first appearance, and is written as a uvarint index.
+// The first time a node appears, its index is followed by the node's name,
+// written as a uvarint length followed by the bytes of the name. Integer
+// weights are written as (zig-zag) varints; floating-point weights are written
+// in IEEE 754 format (little-endian.)
+//
+// A codec holds state for a single stream (e.g. the node indices); use a new
+// codec for each stream.
+type BinaryCodec[N comparable, W Weight] struct {
+	// FormatNode and ParseNode convert nodes to and from their names. Default
+	// to fmt.Sprint, and parsing the whole name as a number, respectively
+	// (strings are used as-is.)
+	FormatNode func(n N) string
+	ParseNode  func(s string) (N, error)
+	// MaxNameLength is the longest node name that will be read; longer names
+	// are rejected, rather than allocated. Defaults to DefaultMaxNameLength.
+	MaxNameLength int
+
+	r       *bufio.Reader
+	records int
+	started bool         // the magic number has been written
+	index   map[N]uint64 // for writing
+	added   []N          // nodes first written by the current record
+	nodes   []N          // for reading
+	buf     []byte
+}
+
+// NewBinaryCodec creates a codec for binary-encoded edges.
+func NewBinaryCodec[N comparable, W Weight]() *BinaryCodec[N, W] {
+	return &BinaryCodec[N, W]{}
+}
+
+// ReadEdge reads the next edge from the stream. Returns io.EOF at the end of
+// the stream. Errors for malformed records include the record number.
+func (c *BinaryCodec[N, W]) ReadEdge(in io.Reader) (from, to N, weight W, err error) {
+	if c.r == nil {
+		c.r = bufio.NewReader(in)
+		magic := make([]byte, len(binaryMagic))
+		if _, err = io.ReadFull(c.r, magic); err != nil || string(magic) != string(binaryMagic) {
+			if !errors.Is(err, io.EOF) {
+				err = fmt.Errorf("not a binary edge stream")
+			}
+			return
+		}
+	}
+	if _, err = c.r.Peek(1); err != nil {
+		// clean end of stream (between records)
+		return
+	}
+	c.records++
+	if from, err = c.readNode(); err == nil {
+		if to, err = c.readNode(); err == nil {
+			weight, err = c.readWeight()
+		}
+	}
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		err = fmt.Errorf("record %d: %w", c.records, err)
+	}
+	return
+}
+
+func (c *BinaryCodec[N, W]) readNode() (n N, err error) {
+	i, err := binary.ReadUvarint(c.r)
+	if err != nil {
+		return
+	}
+	switch {
+	case i < uint64(len(c.nodes)):
+		return c.nodes[i], nil
+	case i > uint64(len(c.nodes)):
+		return n, fmt.Errorf("invalid node index %d", i)
+	}
+	// first appearance of this node; read its name
+	size, err := binary.ReadUvarint(c.r)
+	if err != nil {
+		return
+	}
+	if size > uint64(c.maxNameLength()) {
+		return n, fmt.Errorf("invalid node name length %d", size)
+	}
+	name := make([]byte, size)
+	if _, err = io.ReadFull(c.r, name); err != nil {
+		return
+	}
+	if c.ParseNode != nil {
+		n, err = c.ParseNode(string(name))
+	} else {
+		n, err = parseValue[N](string(name))
+	}
+	if err != nil {
+		return n, fmt.Errorf("invalid node %q: %v", name, err)
+	}
+	c.nodes = append(c.nodes, n)
+	return
+}
+
+func (c *BinaryCodec[N, W]) maxNameLength() int {
+	if c.MaxNameLength > 0 {
+		return c.MaxNameLength
+	}
+	return DefaultMaxNameLength
+}
+
+func (c *BinaryCodec[N, W]) readWeight() (w W, err error) {
+	switch p := any(&w).(type) {
+	case *float32:
+		var b [4]byte
+		if _, err = io.ReadFull(c.r, b[:]); err == nil {
+			*p = math.Float32frombits(binary.LittleEndian.Uint32(b[:]))
+		}
+	case *float64:
+		var b [8]byte
+		if _, err = io.ReadFull(c.r, b[:]); err == nil {
+			*p = math.Float64frombits(binary.LittleEndian.Uint64(b[:]))
+		}
+	default:
+		// the weight must fit the weight type
+		if isSigned[W]() {
+			var v int64
+			if v, err = binary.ReadVarint(c.r); err == nil {
+				if w = W(v); int64(w) != v {
+					err = fmt.Errorf("weight %d out of range", v)
+				}
+			}
+		} else {
+			var v uint64
+			if v, err = binary.ReadUvarint(c.r); err == nil {
+				if w = W(v); uint64(w) != v {
+					err = fmt.Errorf("weight %d out of range", v)
+				}
+			}
+		}
+	}
+	return
+}
+
+// WriteEdge writes an edge to the stream (preceded by the magic number, if
+// this is the first edge written.) Nodes are only assigned indices once the
+// record has been written, so a failed write can be retried.
+func (c *BinaryCodec[N, W]) WriteEdge(out io.Writer, from, to N, weight W) (int, error) {
+	b := c.buf[:0]
+	if !c.started {
+		b = append(b, binaryMagic...)
+	}
+	c.added = c.added[:0]
+	b = c.appendNode(b, from)
+	b = c.appendNode(b, to)
+	switch v := any(weight).(type) {
+	case float32:
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(v))
+	case float64:
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	default:
+		if isSigned[W]() {
+			b = binary.AppendVarint(b, int64(weight))
+		} else {
+			b = binary.AppendUvarint(b, uint64(weight))
+		}
+	}
+	c.buf = b
+	n, err := out.Write(b)
+	if err != nil {
+		return n, err
+	}
+	c.started = true
+	if c.index == nil {
+		c.index = map[N]uint64{}
+	}
+	for _, v := range c.added {
+		c.index[v] = uint64(len(c.index))
+	}
+	return n, nil
+}
+
+func (c *BinaryCodec[N, W]) appendNode(b []byte, n N) []byte {
+	if i, found := c.index[n]; found {
+		return binary.AppendUvarint(b, i)
+	}
+	if i := slices.Index(c.added, n); i >= 0 {
+		// a self-loop, to a node first written by this record
+		return binary.AppendUvarint(b, uint64(len(c.index)+i))
+	}
+	i := uint64(len(c.index) + len(c.added))
+	c.added = append(c.added, n)
+	var name string
+	if c.FormatNode != nil {
+		name = c.FormatNode(n)
+	} else {
+		name = formatValue(n)
+	}
+	b = binary.AppendUvarint(b, i)
+	b = binary.AppendUvarint(b, uint64(len(name)))
+	return append(b, name...)
+}
+
+// isSigned determines if the weight type is a signed type
+func isSigned[W Weight]() bool {
+	var zero W
+	return zero-1 < zero
+}
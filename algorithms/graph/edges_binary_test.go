@@ -0,0 +1,159 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+func TestBinaryCodec(t *testing.T) {
+	g := NewGraph[string, int](Undirected)
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("b", "c", -200)
+	g.AddEdge("c", "a", math.MaxInt)
+	g.AddEdge("a", "d", math.MinInt)
+
+	buffer := bytes.Buffer{}
+	assert.Nil(t, g.WriteEdges(&buffer, NewBinaryCodec[string, int]().WriteEdge))
+	t.Logf("%d bytes", buffer.Len())
+
+	gT := NewGraph[string, int](Undirected)
+	assert.Nil(t, gT.ReadEdges(&buffer, NewBinaryCodec[string, int]().ReadEdge))
+	assert.Equal(t, g.EdgeCount(), gT.EdgeCount())
+	assert.Equal(t, g.NodeCount(), gT.NodeCount())
+	for _, test := range []struct {
+		from, to string
+		w        int
+	}{{"a", "b", 1}, {"b", "c", -200}, {"a", "c", math.MaxInt}, {"d", "a", math.MinInt}} {
+		w, found := gT.GetEdgeWeight(test.from, test.to)
+		assert.True(t, found)
+		assert.Equal(t, test.w, w)
+	}
+}
+
+func TestBinaryCodecTypes(t *testing.T) {
+	g := NewGraph[int, float32](Directed)
+	g.AddEdge(1, 2, 0.1)
+	g.AddEdge(2, 3, float32(math.Inf(-1)))
+	buffer := bytes.Buffer{}
+	assert.Nil(t, g.WriteEdges(&buffer, NewBinaryCodec[int, float32]().WriteEdge))
+	gT := NewGraph[int, float32](Directed)
+	assert.Nil(t, gT.ReadEdges(&buffer, NewBinaryCodec[int, float32]().ReadEdge))
+	w, _ := gT.GetEdgeWeight(1, 2)
+	assert.Equal(t, float32(0.1), w)
+	w, _ = gT.GetEdgeWeight(2, 3)
+	assert.True(t, math.IsInf(float64(w), -1))
+
+	gU := NewGraph[int, uint64](Directed)
+	gU.AddEdge(10, 20, math.MaxUint64)
+	buffer.Reset()
+	assert.Nil(t, gU.WriteEdges(&buffer, NewBinaryCodec[int, uint64]().WriteEdge))
+	gUT := NewGraph[int, uint64](Directed)
+	assert.Nil(t, gUT.ReadEdges(&buffer, NewBinaryCodec[int, uint64]().ReadEdge))
+	wU, _ := gUT.GetEdgeWeight(10, 20)
+	assert.Equal(t, uint64(math.MaxUint64), wU)
+}
+
+func TestBinaryCodecErrors(t *testing.T) {
+	// empty stream
+	g := NewGraph[string, int](Directed)
+	assert.Nil(t, g.ReadEdges(strings.NewReader(""), NewBinaryCodec[string, int]().ReadEdge))
+	assert.Equal(t, 0, g.EdgeCount())
+
+	// bad magic
+	err := g.ReadEdges(strings.NewReader("a,b,1\n"), NewBinaryCodec[string, int]().ReadEdge)
+	assert.NotNil(t, err)
+	t.Log(err)
+
+	// name too long
+	huge := append(append([]byte{}, binaryMagic...), 0)
+	huge = binary.AppendUvarint(huge, math.MaxInt32)
+	err = g.ReadEdges(bytes.NewReader(huge), NewBinaryCodec[string, int]().ReadEdge)
+	assert.NotNil(t, err)
+	t.Log(err)
+	assert.True(t, strings.HasPrefix(err.Error(), "record 1: invalid node name length"))
+	long := bytes.Buffer{}
+	NewBinaryCodec[string, int]().WriteEdge(&long, strings.Repeat("a", 100), "b", 1)
+	codec := NewBinaryCodec[string, int]()
+	codec.MaxNameLength = 99
+	assert.NotNil(t, g.ReadEdges(bytes.NewReader(long.Bytes()), codec.ReadEdge))
+	codec = NewBinaryCodec[string, int]()
+	codec.MaxNameLength = 100
+	assert.Nil(t, g.ReadEdges(bytes.NewReader(long.Bytes()), codec.ReadEdge))
+
+	// weight out of range
+	wide := bytes.Buffer{}
+	NewBinaryCodec[string, uint]().WriteEdge(&wide, "a", "b", 300)
+	g8 := NewGraph[string, uint8](Directed)
+	err = g8.ReadEdges(bytes.NewReader(wide.Bytes()), NewBinaryCodec[string, uint8]().ReadEdge)
+	t.Log(err)
+	assert.NotNil(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "record 1: weight 300 out of range"))
+	wide.Reset()
+	NewBinaryCodec[string, int]().WriteEdge(&wide, "a", "b", -200)
+	gi8 := NewGraph[string, int8](Directed)
+	assert.NotNil(t, gi8.ReadEdges(bytes.NewReader(wide.Bytes()), NewBinaryCodec[string, int8]().ReadEdge))
+
+	// truncated
+	buffer := bytes.Buffer{}
+	codec = NewBinaryCodec[string, int]()
+	codec.WriteEdge(&buffer, "a", "b", 1)
+	codec.WriteEdge(&buffer, "b", "c", 2)
+	data := buffer.Bytes()
+	for n := len(data) - 1; n > len(binaryMagic); n-- {
+		codec = NewBinaryCodec[string, int]()
+		r := bytes.NewReader(data[:n])
+		var err error
+		for err == nil {
+			_, _, _, err = codec.ReadEdge(r)
+		}
+		if errors.Is(err, io.EOF) {
+			// truncated at a record boundary
+			assert.Equal(t, 1, codec.records)
+			continue
+		}
+		t.Log(err)
+		assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+		assert.True(t, strings.HasPrefix(err.Error(), "record "))
+	}
+}
+
+// flakyWriter fails every other write
+type flakyWriter struct {
+	bytes.Buffer
+	fail bool
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if w.fail = !w.fail; w.fail {
+		return 0, errors.New("write failed")
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestBinaryCodecWriteRetry(t *testing.T) {
+	out := flakyWriter{}
+	codec := NewBinaryCodec[string, int]()
+	edges := [][2]string{{"a", "b"}, {"b", "c"}, {"d", "d"}, {"c", "a"}}
+	for i, e := range edges {
+		_, err := codec.WriteEdge(&out, e[0], e[1], i)
+		assert.NotNil(t, err)
+		_, err = codec.WriteEdge(&out, e[0], e[1], i)
+		assert.Nil(t, err)
+	}
+	g := NewGraphWithPolicy[string, int](Directed, EdgePolicy{SelfLoops: true})
+	assert.Nil(t, g.ReadEdges(&out.Buffer, NewBinaryCodec[string, int]().ReadEdge))
+	assert.Equal(t, 4, g.NodeCount())
+	for i, e := range edges {
+		w, found := g.GetEdgeWeight(e[0], e[1])
+		assert.True(t, found)
+		assert.Equal(t, i, w)
+	}
+}
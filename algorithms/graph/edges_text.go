@@ -0,0 +1,161 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DelimitedCodec reads and writes edges as delimited text (e.g., CSV or TSV),
+// one edge per line, as: from, to, weight. Node names are quoted as needed.
+// The codec's ReadEdge and WriteEdge methods can be used with
+// Graph.ReadEdges and Graph.WriteEdges, respectively.
+//
+// A codec holds state for a single stream (e.g. whether the header has been
+// read or written); use a new codec for each stream.
+type DelimitedCodec[N comparable, W Weight] struct {
+	// Separator between fields; e.g. ',' or '\t'
+	Separator rune
+	// Header indicates that the first line of the stream is a header. The
+	// header is skipped when reading, and written before the first edge.
+	Header bool
+	// Comment, if not zero, is the character that begins a comment line.
+	// Comment lines are ignored when reading.
+	Comment rune
+	// DefaultWeight is the weight of edges that are read without a weight
+	// field.
+	DefaultWeight W
+	// FormatNode and ParseNode convert nodes to and from text. Default to
+	// fmt.Sprint, and parsing the whole field as a number, respectively
+	// (strings are used as-is.)
+	FormatNode func(n N) string
+	ParseNode  func(s string) (N, error)
+	// FormatWeight and ParseWeight convert weights to and from text, and
+	// default as for nodes.
+	FormatWeight func(w W) string
+	ParseWeight  func(s string) (W, error)
+
+	r             *csv.Reader
+	w             *csv.Writer
+	out           countingWriter // the stream being written, for w
+	headerWritten bool
+}
+
+// countingWriter counts the bytes written to a stream
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += n
+	return n, err
+}
+
+// NewCSVCodec creates a codec for comma-separated edges.
+func NewCSVCodec[N comparable, W Weight]() *DelimitedCodec[N, W] {
+	return &DelimitedCodec[N, W]{Separator: ',', Comment: '#'}
+}
+
+// NewTSVCodec creates a codec for tab-separated edges.
+func NewTSVCodec[N comparable, W Weight]() *DelimitedCodec[N, W] {
+	return &DelimitedCodec[N, W]{Separator: '\t', Comment: '#'}
+}
+
+// headerFields are the field names written in the header
+var headerFields = []string{"from", "to", "weight"}
+
+// ReadEdge reads the next edge from the stream. Returns io.EOF at the end of
+// the stream. Errors for malformed records include the line number.
+func (c *DelimitedCodec[N, W]) ReadEdge(in io.Reader) (from, to N, weight W, err error) {
+	if c.r == nil {
+		c.r = csv.NewReader(in)
+		c.r.Comma = c.Separator
+		c.r.Comment = c.Comment
+		c.r.FieldsPerRecord = -1 // checked below
+		c.r.TrimLeadingSpace = true
+		c.r.ReuseRecord = true
+		if c.Header {
+			if _, err = c.r.Read(); err != nil {
+				return
+			}
+		}
+	}
+	record, err := c.r.Read()
+	if err != nil {
+		var pe *csv.ParseError
+		if errors.As(err, &pe) {
+			err = fmt.Errorf("line %d: %w", pe.Line, pe.Err)
+		}
+		return
+	}
+	line, _ := c.r.FieldPos(0)
+	if len(record) != 2 && len(record) != 3 {
+		err = fmt.Errorf("line %d: expected 2 or 3 fields; got %d", line, len(record))
+		return
+	}
+	if from, err = c.parseNode(record[0]); err != nil {
+		err = fmt.Errorf("line %d: invalid node %q: %v", line, record[0], err)
+		return
+	}
+	if to, err = c.parseNode(record[1]); err != nil {
+		err = fmt.Errorf("line %d: invalid node %q: %v", line, record[1], err)
+		return
+	}
+	weight = c.DefaultWeight
+	if len(record) == 3 {
+		if weight, err = c.parseWeight(record[2]); err != nil {
+			err = fmt.Errorf("line %d: invalid weight %q: %v", line, record[2], err)
+		}
+	}
+	return
+}
+
+// WriteEdge writes an edge to the stream (preceded by the header, if this is
+// the first edge written.) Each record is written to the stream before
+// WriteEdge returns.
+func (c *DelimitedCodec[N, W]) WriteEdge(out io.Writer, from, to N, weight W) (int, error) {
+	if c.w == nil {
+		c.w = csv.NewWriter(&c.out)
+		c.w.Comma = c.Separator
+	}
+	c.out.w, c.out.n = out, 0
+	if c.Header && !c.headerWritten {
+		c.w.Write(headerFields)
+		c.headerWritten = true
+	}
+	c.w.Write([]string{c.formatNode(from), c.formatNode(to), c.formatWeight(weight)})
+	c.w.Flush()
+	return c.out.n, c.w.Error()
+}
+
+func (c *DelimitedCodec[N, W]) parseNode(s string) (N, error) {
+	if c.ParseNode != nil {
+		return c.ParseNode(s)
+	}
+	return parseValue[N](s)
+}
+
+func (c *DelimitedCodec[N, W]) parseWeight(s string) (W, error) {
+	if c.ParseWeight != nil {
+		return c.ParseWeight(s)
+	}
+	return parseValue[W](s)
+}
+
+func (c *DelimitedCodec[N, W]) formatNode(n N) string {
+	if c.FormatNode != nil {
+		return c.FormatNode(n)
+	}
+	return formatValue(n)
+}
+
+func (c *DelimitedCodec[N, W]) formatWeight(w W) string {
+	if c.FormatWeight != nil {
+		return c.FormatWeight(w)
+	}
+	return formatValue(w)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+func TestCSVCodec(t *testing.T) {
+	g := NewGraph[string, int](Directed)
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("b", "Smith, John", 2)
+	g.AddEdge("Smith, John", `say "hi"`, -3)
+
+	buffer := bytes.Buffer{}
+	codec := NewCSVCodec[string, int]()
+	codec.Header = true
+	assert.Nil(t, g.WriteEdges(&buffer, codec.WriteEdge))
+	// the count of bytes written is per edge
+	n, err := codec.WriteEdge(&bytes.Buffer{}, "x", "y", 10)
+	assert.Nil(t, err)
+	assert.Equal(t, len("x,y,10\n"), n)
+	t.Logf("edges:\n%s\n", buffer.String())
+	assert.True(t, strings.HasPrefix(buffer.String(), "from,to,weight\n"))
+	assert.Equal(t, 3, strings.Count(buffer.String(), "\n")-1)
+
+	gT := NewGraph[string, int](Directed)
+	codec = NewCSVCodec[string, int]()
+	codec.Header = true
+	assert.Nil(t, gT.ReadEdges(&buffer, codec.ReadEdge))
+	gT.Fprint(os.Stderr)
+	assert.Equal(t, g.EdgeCount(), gT.EdgeCount())
+	assert.Equal(t, g.NodeCount(), gT.NodeCount())
+	w, found := gT.GetEdgeWeight("Smith, John", `say "hi"`)
+	assert.True(t, found)
+	assert.Equal(t, -3, w)
+}
+
+func TestTSVCodec(t *testing.T) {
+	const edges = `# a comment
+1	2	0.5
+2	3
+3	1	-1e3
+`
+	g := NewGraph[int, float64](Directed)
+	codec := NewTSVCodec[int, float64]()
+	codec.DefaultWeight = 1
+	assert.Nil(t, g.ReadEdges(strings.NewReader(edges), codec.ReadEdge))
+	assert.Equal(t, 3, g.EdgeCount())
+	w, _ := g.GetEdgeWeight(1, 2)
+	assert.Equal(t, 0.5, w)
+	w, _ = g.GetEdgeWeight(2, 3)
+	assert.Equal(t, 1.0, w)
+	w, _ = g.GetEdgeWeight(3, 1)
+	assert.Equal(t, -1000.0, w)
+
+	buffer := bytes.Buffer{}
+	assert.Nil(t, g.WriteEdges(&buffer, NewTSVCodec[int, float64]().WriteEdge))
+	gT := NewGraph[int, float64](Directed)
+	assert.Nil(t, gT.ReadEdges(&buffer, NewTSVCodec[int, float64]().ReadEdge))
+	assert.Equal(t, g.EdgeCount(), gT.EdgeCount())
+}
+
+func TestDelimitedCodecErrors(t *testing.T) {
+	tests := []struct {
+		edges string
+		err   string
+	}{
+		{"1,2,3\n4,5,6,7\n", "line 2: expected 2 or 3 fields"},
+		{"1,2,3\n# comment\n1\n", "line 3: expected 2 or 3 fields"},
+		{"1,2,3\nx,2,3\n", `line 2: invalid node "x"`},
+		{"1,2,3\n1,,3\n", `line 2: invalid node ""`},
+		{"1,2,x\n", `line 1: invalid weight "x"`},
+		{"1,2,3\n1.5,2,3\n", `line 2: invalid node "1.5"`},
+		{"1,2,3\n12abc,2,3\n", `line 2: invalid node "12abc"`},
+		{"1,2,3\n\n1 2,2,3\n", `line 3: invalid node "1 2"`},
+		{"1,2,3\n1,2,1.5\n", `line 2: invalid weight "1.5"`},
+		{"1,2,12abc\n", `line 1: invalid weight "12abc"`},
+		{"1,2,1 2\n", `line 1: invalid weight "1 2"`},
+		{"1,2,3\n1,\"2,3\n", "line 2: "},
+	}
+	for _, test := range tests {
+		g := NewGraph[int, int](Directed)
+		err := g.ReadEdges(strings.NewReader(test.edges), NewCSVCodec[int, int]().ReadEdge)
+		t.Logf("%q: %v", test.edges, err)
+		assert.NotNil(t, err)
+		assert.True(t, strings.HasPrefix(err.Error(), test.err))
+	}
+}
+
+type label string
+
+func TestDelimitedCodecStringKind(t *testing.T) {
+	g := NewGraph[label, int](Directed)
+	assert.Nil(t, g.ReadEdges(strings.NewReader("Smith John,Jones Jane,1\n"), NewCSVCodec[label, int]().ReadEdge))
+	assert.True(t, g.HasEdge("Smith John", "Jones Jane"))
+}
+
+func BenchmarkCSVCodecWrite(b *testing.B) {
+	codec := NewCSVCodec[int, int]()
+	out := bytes.Buffer{}
+	for i := range b.N {
+		out.Reset()
+		codec.WriteEdge(&out, i, i+1, i%100)
+	}
+}
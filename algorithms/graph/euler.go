@@ -0,0 +1,129 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"errors"
+
+	"github.com/tommika/gorilla/util"
+)
+
+// ErrNoEulerianPath is returned when a graph has no Eulerian path (or
+// circuit.)
+var ErrNoEulerianPath = errors.New("graph has no Eulerian path")
+
+// EulerianPath finds an Eulerian path of the graph: a path that follows every
+// edge exactly once (visiting nodes as often as needed.) Such a path exists
+// if the edges are connected, and, for an undirected graph, if zero or two
+// nodes have an odd degree (in which case the path goes from one to the
+// other); for a directed graph, every node must have as many incoming edges as
+// outgoing edges, except possibly for the first node (with one extra outgoing
+// edge) and the last (with one extra incoming edge.) The path is found using
+// Hierholzer's algorithm, in O(E) time. Returns ErrNoEulerianPath if there is
+// no such path. If the graph has no edges, the path is empty.
+func (g *Graph[N, W]) EulerianPath() ([]N, error) {
+	return g.eulerian(false)
+}
+
+// EulerianCircuit finds an Eulerian circuit of the graph: an Eulerian path
+// (see EulerianPath) that ends where it starts. Such a circuit exists if the
+// edges are connected, and every node has an even degree (or, for a directed
+// graph, as many incoming edges as outgoing edges.) The first node of the
+// circuit is repeated at the end. Returns ErrNoEulerianPath if there is no
+// such circuit.
+func (g *Graph[N, W]) EulerianCircuit() ([]N, error) {
+	return g.eulerian(true)
+}
+
+// eulerian finds an Eulerian path, or circuit.
+func (g *Graph[N, W]) eulerian(circuit bool) ([]N, error) {
+	nodes := g.sortedNodes()
+	index := make(map[N]int, len(nodes))
+	for i, v := range nodes {
+		index[v] = i
+	}
+	// number the edges, so that each (undirected) edge is followed once
+	type arc struct{ edge, to int }
+	adj := make([][]arc, len(nodes))
+	balance := make([]int, len(nodes)) // out-degree less in-degree (or degree)
+	numEdges := 0
+	for u, from := range nodes {
+		for _, e := range g.nodes[from].outgoing {
+			v := index[e.node]
+			adj[u] = append(adj[u], arc{numEdges, v})
+			if g.directed {
+				balance[u]++
+				balance[v]--
+			} else {
+				if u != v {
+					adj[v] = append(adj[v], arc{numEdges, u})
+				}
+				balance[u]++
+				balance[v]++
+			}
+			numEdges++
+		}
+	}
+	if numEdges == 0 {
+		return []N{}, nil
+	}
+	isUnbalanced := func(b int) bool {
+		if g.directed {
+			return b != 0
+		}
+		return b%2 != 0
+	}
+	unbalanced := 0
+	for _, b := range balance {
+		if g.directed && (b > 1 || b < -1) {
+			return nil, ErrNoEulerianPath
+		}
+		if isUnbalanced(b) {
+			unbalanced++
+		}
+	}
+	if unbalanced > 2 || (circuit && unbalanced > 0) {
+		return nil, ErrNoEulerianPath
+	}
+	// Start at a node with an extra outgoing edge (or an odd degree), if any,
+	// and otherwise at the first node with edges.
+	start := noIndex
+	for i, b := range balance {
+		if isUnbalanced(b) && b > 0 {
+			start = i
+			break
+		}
+	}
+	for i := 0; start == noIndex; i++ {
+		if len(adj[i]) > 0 {
+			start = i
+		}
+	}
+	// Follow unused edges until getting stuck (which can only happen at the
+	// end of the path); then back up, adding nodes to the path, until
+	// reaching a node with unused edges, from which another tour is spliced
+	// into the path.
+	used := make([]bool, numEdges)
+	next := make([]int, len(nodes)) // next edge of each node to consider
+	stack := []int{start}
+	path := make([]N, 0, numEdges+1)
+	for len(stack) > 0 {
+		u := stack[len(stack)-1]
+		for next[u] < len(adj[u]) && used[adj[u][next[u]].edge] {
+			next[u]++
+		}
+		if next[u] == len(adj[u]) {
+			path = append(path, nodes[u])
+			stack = stack[:len(stack)-1]
+		} else {
+			a := adj[u][next[u]]
+			used[a.edge] = true
+			stack = append(stack, a.to)
+		}
+	}
+	if len(path) != numEdges+1 {
+		// the edges aren't connected
+		return nil, ErrNoEulerianPath
+	}
+	// the path was built from the end
+	return util.ReverseSlice(path), nil
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+// assertEulerian asserts that the path follows every edge of the graph exactly
+// once
+func assertEulerian[W Weight](t *testing.T, g *Graph[int, W], path []int) {
+	t.Helper()
+	arc := func(from, to int) Arc[int] {
+		if !g.directed && to < from {
+			from, to = to, from
+		}
+		return Arc[int]{from, to}
+	}
+	remaining := map[Arc[int]]int{}
+	g.visitEdges(func(from, to int, _ W) {
+		remaining[arc(from, to)]++
+	})
+	assert.Equal(t, g.EdgeCount()+1, len(path))
+	for i := 1; i < len(path); i++ {
+		a := arc(path[i-1], path[i])
+		assert.True(t, remaining[a] > 0)
+		remaining[a]--
+	}
+}
+
+func TestEulerianPath(t *testing.T) {
+	// a square, with a diagonal: the ends of the diagonal have an odd degree
+	g := NewGraph[int, int](Undirected)
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(2, 3, 1)
+	g.AddEdge(3, 0, 1)
+	g.AddEdge(0, 2, 1)
+	path, err := g.EulerianPath()
+	assert.Nil(t, err)
+	assertEulerian(t, g, path)
+	assert.Equal(t, 0, path[0])
+	assert.Equal(t, 2, path[len(path)-1])
+	_, err = g.EulerianCircuit()
+	assert.Equal(t, ErrNoEulerianPath, err)
+
+	// a path of a directed graph starts at the node with an extra outgoing edge
+	g = pathGraph(5, Directed)
+	g.AddEdge(2, 0, 1)
+	g.AddEdge(0, 2, 1)
+	path, err = g.EulerianPath()
+	assert.Nil(t, err)
+	assertEulerian(t, g, path)
+	assert.Equal(t, 0, path[0])
+	assert.Equal(t, 4, path[len(path)-1])
+
+	// too many odd nodes
+	_, err = starGraph(4, Undirected).EulerianPath()
+	assert.Equal(t, ErrNoEulerianPath, err)
+	_, err = starGraph(3, Directed).EulerianPath()
+	assert.Equal(t, ErrNoEulerianPath, err)
+
+	// no edges
+	path, err = NewGraph[int, int](Directed).EulerianPath()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(path))
+}
+
+func TestEulerianCircuit(t *testing.T) {
+	// two triangles sharing a node
+	g := NewGraph[int, int](Directed)
+	for _, c := range [][]int{{0, 1, 2}, {0, 3, 4}} {
+		g.AddEdge(c[0], c[1], 1)
+		g.AddEdge(c[1], c[2], 1)
+		g.AddEdge(c[2], c[0], 1)
+	}
+	g.AddNode(5)
+	circuit, err := g.EulerianCircuit()
+	assert.Nil(t, err)
+	assertEulerian(t, g, circuit)
+	assert.DeepEqual(t, []int{0, 1, 2, 0, 3, 4, 0}, circuit)
+
+	// parallel edges and self-loops
+	g = NewGraphWithPolicy[int, int](Undirected, EdgePolicy{SelfLoops: true})
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(1, 0, 1)
+	g.AddEdge(1, 1, 1)
+	circuit, err = g.EulerianCircuit()
+	assert.Nil(t, err)
+	assertEulerian(t, g, circuit)
+
+	// grids with an even number of columns have nodes of odd degree
+	g = Grid(4, 4, GeneratorOptions[int]{})
+	_, err = g.EulerianCircuit()
+	assert.Equal(t, ErrNoEulerianPath, err)
+
+	// every node of a ring lattice has an even degree
+	g = WattsStrogatz(50, 4, 0, GeneratorOptions[int]{})
+	circuit, err = g.EulerianCircuit()
+	assert.Nil(t, err)
+	assertEulerian(t, g, circuit)
+	assert.Equal(t, circuit[0], circuit[len(circuit)-1])
+
+	// the edges must be connected
+	g = NewGraph[int, int](Undirected)
+	for _, c := range [][]int{{0, 1, 2}, {3, 4, 5}} {
+		g.AddEdge(c[0], c[1], 1)
+		g.AddEdge(c[1], c[2], 1)
+		g.AddEdge(c[2], c[0], 1)
+	}
+	_, err = g.EulerianCircuit()
+	assert.Equal(t, ErrNoEulerianPath, err)
+}
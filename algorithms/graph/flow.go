@@ -0,0 +1,318 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"github.com/tommika/gorilla/algorithms/queue"
+)
+
+// Arc identifies a directed connection between two nodes.
+type Arc[N comparable] struct {
+	From N
+	To   N
+}
+
+// Flow is the result of a maximum flow computation. Edge weights are taken to
+// be capacities; edges with a non-positive weight carry no flow. Parallel
+// edges between the same pair of nodes are combined into a single arc whose
+// capacity is the sum of the edge weights. In an undirected graph each edge
+// has the given capacity in both directions.
+type Flow[N comparable, W Weight] struct {
+	source N
+	sink   N
+	value  W
+	flows  map[Arc[N]]W // net flow along each arc (only positive flows are kept)
+	cut    map[N]bool   // nodes on the source side of the minimum cut
+	nodes  []N          // all nodes of the graph
+}
+
+// Value returns the value of the maximum flow; i.e., the total flow leaving
+// the source.
+func (f *Flow[N, W]) Value() W {
+	return f.value
+}
+
+// EdgeFlow returns the flow from one node to another.
+func (f *Flow[N, W]) EdgeFlow(from, to N) W {
+	return f.flows[Arc[N]{from, to}]
+}
+
+// VisitEdgeFlows visits each arc that carries a positive flow.
+func (f *Flow[N, W]) VisitEdgeFlows(visit func(from, to N, flow W)) {
+	for a, w := range f.flows {
+		visit(a.From, a.To, w)
+	}
+}
+
+// MinCut returns the partition of the nodes induced by the minimum cut. The
+// source side includes all nodes reachable from the source in the residual
+// network; the sink side includes the remaining nodes of the graph.
+func (f *Flow[N, W]) MinCut() (sourceSide, sinkSide []N) {
+	for _, v := range f.nodes {
+		if f.cut[v] {
+			sourceSide = append(sourceSide, v)
+		} else {
+			sinkSide = append(sinkSide, v)
+		}
+	}
+	return
+}
+
+// CutEdges returns the saturated arcs that cross the minimum cut, from the
+// source side to the sink side. The sum of their capacities equals the value
+// of the flow.
+func (f *Flow[N, W]) CutEdges() (arcs []Arc[N]) {
+	for a := range f.flows {
+		if f.cut[a.From] && !f.cut[a.To] {
+			arcs = append(arcs, a)
+		}
+	}
+	return
+}
+
+// IsSourceSide determines if the given node is on the source side of the
+// minimum cut.
+func (f *Flow[N, W]) IsSourceSide(n N) bool {
+	return f.cut[n]
+}
+
+// capacities returns the capacity of each arc in the graph.
+func (g *Graph[N, W]) capacities() map[Arc[N]]W {
+	var zero W
+	caps := map[Arc[N]]W{}
+	for v, node := range g.nodes {
+		for _, e := range node.outgoing {
+			if e.weight <= zero {
+				continue
+			}
+			caps[Arc[N]{v, e.node}] += e.weight
+			if !g.directed {
+				caps[Arc[N]{e.node, v}] += e.weight
+			}
+		}
+	}
+	return caps
+}
+
+// MaxFlowEdmondsKarp computes the maximum flow from s to t using the
+// Edmonds-Karp algorithm: the flow is repeatedly augmented along the shortest
+// path in the residual network, as found by a breadth-first search.
+// Runs in O(VE^2) time.
+func (g *Graph[N, W]) MaxFlowEdmondsKarp(s, t N) (flow Flow[N, W]) {
+	var zero W
+	caps := g.capacities()
+	flows := map[Arc[N]]W{}
+	residual := func(u, v N) W {
+		return caps[Arc[N]{u, v}] - flows[Arc[N]{u, v}] + flows[Arc[N]{v, u}]
+	}
+	// The residual network includes an arc (u,v) whenever more flow can be
+	// pushed from u to v; either by using spare capacity of (u,v), or by
+	// cancelling flow along (v,u).
+	adjacent := func(u N) (edges []edge[N, W]) {
+		for _, e := range g.nodes[u].outgoing {
+			if r := residual(u, e.node); r > zero {
+				edges = append(edges, g.newEdge(e.node, r))
+			}
+		}
+		for _, e := range g.nodes[u].incoming {
+			if r := residual(u, e.node); r > zero {
+				edges = append(edges, g.newEdge(e.node, r))
+			}
+		}
+		return
+	}
+	flow.init(g, s, t)
+	if !g.HasNode(s) || !g.HasNode(t) || s == t {
+		return
+	}
+	for {
+		bft := g.bfs([]N{s}, 0, adjacent)
+		path, _ := bft.FindPath(t)
+		if path == nil {
+			// no augmenting path; the nodes reached by the search form
+			// the source side of the minimum cut.
+			bft.VisitNodes(func(v N) {
+				flow.cut[v] = true
+			})
+			break
+		}
+		// the bottleneck is the smallest residual capacity along the path,
+		// which the search recorded as the weight of each tree edge.
+		delta := bft.nodeMap[t].weight
+		for _, v := range path[1:] {
+			delta = min(delta, bft.nodeMap[v].weight)
+		}
+		for i := 1; i < len(path); i++ {
+			u, v := path[i-1], path[i]
+			// cancel any opposing flow before adding flow along the arc
+			back := min(delta, flows[Arc[N]{v, u}])
+			setFlow(flows, Arc[N]{v, u}, flows[Arc[N]{v, u}]-back)
+			setFlow(flows, Arc[N]{u, v}, flows[Arc[N]{u, v}]+delta-back)
+		}
+		flow.value += delta
+	}
+	flow.flows = flows
+	return
+}
+
+// MaxFlowDinic computes the maximum flow from s to t using Dinic's algorithm:
+// a breadth-first search partitions the residual network into levels, and a
+// blocking flow is then pushed along level-increasing paths using a
+// depth-first search. Runs in O(V^2E) time, and typically much faster than
+// Edmonds-Karp in practice.
+func (g *Graph[N, W]) MaxFlowDinic(s, t N) (flow Flow[N, W]) {
+	flow.init(g, s, t)
+	if !g.HasNode(s) || !g.HasNode(t) || s == t {
+		return
+	}
+	net := newFlowNetwork[N, W](g)
+	si, ti := net.index[s], net.index[t]
+	for net.levelize(si, ti) {
+		for i := range net.next {
+			net.next[i] = 0
+		}
+		for {
+			pushed := net.augment(si, ti, net.maxCap)
+			if pushed <= 0 {
+				break
+			}
+			flow.value += pushed
+		}
+	}
+	// nodes still reachable from the source form the source side of the cut
+	for i, l := range net.level {
+		if l >= 0 {
+			flow.cut[net.nodes[i]] = true
+		}
+	}
+	for u := range net.adj {
+		for _, ai := range net.adj[u] {
+			a := net.arcs[ai]
+			if a.original > a.cap {
+				arc := Arc[N]{net.nodes[u], net.nodes[a.to]}
+				flow.flows[arc] += a.original - a.cap
+			}
+		}
+	}
+	// Flow may have been pushed in both directions between two nodes.
+	// Cancel opposing flows so that only the net flow is reported.
+	for a, w := range flow.flows {
+		rev := Arc[N]{a.To, a.From}
+		if r, found := flow.flows[rev]; found && w >= r {
+			setFlow(flow.flows, a, w-r)
+			delete(flow.flows, rev)
+		}
+	}
+	return
+}
+
+func (f *Flow[N, W]) init(g *Graph[N, W], s, t N) {
+	f.source = s
+	f.sink = t
+	f.flows = map[Arc[N]]W{}
+	f.cut = map[N]bool{s: true}
+	f.nodes = make([]N, 0, len(g.nodes))
+	for v := range g.nodes {
+		f.nodes = append(f.nodes, v)
+	}
+}
+
+// setFlow updates the flow along an arc, removing arcs with no flow.
+func setFlow[N comparable, W Weight](flows map[Arc[N]]W, a Arc[N], w W) {
+	var zero W
+	if w > zero {
+		flows[a] = w
+	} else {
+		delete(flows, a)
+	}
+}
+
+// flowNetwork is an index-based residual network used by Dinic's algorithm.
+// Each arc is paired with a reverse arc (at index i^1) so that pushing flow
+// along one arc releases capacity on the other.
+type flowNetwork[N comparable, W Weight] struct {
+	nodes  []N
+	index  map[N]int
+	adj    [][]int // arc indices leaving each node
+	arcs   []flowArc[W]
+	level  []int
+	next   []int // next arc to try for each node, during a blocking flow
+	maxCap W     // largest capacity of any arc; an upper bound on any push
+}
+
+type flowArc[W Weight] struct {
+	to       int
+	cap      W // residual capacity
+	original W // capacity before any flow was pushed
+}
+
+func newFlowNetwork[N comparable, W Weight](g *Graph[N, W]) *flowNetwork[N, W] {
+	net := &flowNetwork[N, W]{
+		nodes: make([]N, 0, len(g.nodes)),
+		index: make(map[N]int, len(g.nodes)),
+	}
+	for v := range g.nodes {
+		net.index[v] = len(net.nodes)
+		net.nodes = append(net.nodes, v)
+	}
+	net.adj = make([][]int, len(net.nodes))
+	net.level = make([]int, len(net.nodes))
+	net.next = make([]int, len(net.nodes))
+	for a, c := range g.capacities() {
+		net.addArc(net.index[a.From], net.index[a.To], c)
+	}
+	return net
+}
+
+func (net *flowNetwork[N, W]) addArc(u, v int, c W) {
+	var zero W
+	net.adj[u] = append(net.adj[u], len(net.arcs))
+	net.arcs = append(net.arcs, flowArc[W]{to: v, cap: c, original: c})
+	net.adj[v] = append(net.adj[v], len(net.arcs))
+	net.arcs = append(net.arcs, flowArc[W]{to: u, cap: zero, original: zero})
+	net.maxCap = max(net.maxCap, c)
+}
+
+// levelize assigns each node its distance from the source in the residual
+// network, and determines if the sink is still reachable.
+func (net *flowNetwork[N, W]) levelize(s, t int) bool {
+	var zero W
+	for i := range net.level {
+		net.level[i] = -1
+	}
+	net.level[s] = 0
+	q := queue.DynamicCircularArrayQueue[int]{}
+	q.Enqueue(s)
+	for q.Size() != 0 {
+		u := q.MustDequeue()
+		for _, ai := range net.adj[u] {
+			a := net.arcs[ai]
+			if a.cap > zero && net.level[a.to] < 0 {
+				net.level[a.to] = net.level[u] + 1
+				q.Enqueue(a.to)
+			}
+		}
+	}
+	return net.level[t] >= 0
+}
+
+// augment pushes up to limit units of flow from u to t along arcs that
+// lead to the next level, and returns the amount pushed.
+func (net *flowNetwork[N, W]) augment(u, t int, limit W) W {
+	var zero W
+	if u == t {
+		return limit
+	}
+	for ; net.next[u] < len(net.adj[u]); net.next[u]++ {
+		ai := net.adj[u][net.next[u]]
+		a := &net.arcs[ai]
+		if a.cap <= zero || net.level[a.to] != net.level[u]+1 {
+			continue
+		}
+		if pushed := net.augment(a.to, t, min(limit, a.cap)); pushed > zero {
+			a.cap -= pushed
+			net.arcs[ai^1].cap += pushed
+			return pushed
+		}
+	}
+	return zero
+}
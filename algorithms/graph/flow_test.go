@@ -0,0 +1,143 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/tommika/gorilla/algorithms/matrix"
+	"github.com/tommika/gorilla/assert"
+)
+
+// The classic flow network from CLRS (figure 26.1), with a max flow of 23.
+const clrsFlowNetwork = `0 16 13  0  0  0
+	                     0  0 10 12  0  0
+	                     0  4  0  0 14  0
+	                     0  0  9  0  0 20
+	                     0  0  0  7  0  4
+	                     0  0  0  0  0  0`
+
+type maxFlowFunc func(g *Graph[int, int], s, t int) Flow[int, int]
+
+var maxFlowFuncs = map[string]maxFlowFunc{
+	"EdmondsKarp": (*Graph[int, int]).MaxFlowEdmondsKarp,
+	"Dinic":       (*Graph[int, int]).MaxFlowDinic,
+}
+
+func TestMaxFlow(t *testing.T) {
+	m := matrix.ParseMatrix(clrsFlowNetwork, strconv.Atoi)
+	g := GraphFromAdjacencyMatrix[int](m, Directed)
+	g.Fprint(os.Stderr)
+	for name, maxFlow := range maxFlowFuncs {
+		t.Logf("%s", name)
+		flow := maxFlow(g, 0, 5)
+		assert.Equal(t, 23, flow.Value())
+		checkFlow(t, g, &flow, 0, 5)
+
+		source, sink := flow.MinCut()
+		assert.Equal(t, g.NodeCount(), len(source)+len(sink))
+		assert.True(t, flow.IsSourceSide(0))
+		assert.False(t, flow.IsSourceSide(5))
+
+		// capacity of the cut equals the value of the flow
+		cutCap := 0
+		for _, a := range flow.CutEdges() {
+			w, found := g.GetEdgeWeight(a.From, a.To)
+			assert.True(t, found)
+			assert.Equal(t, w, flow.EdgeFlow(a.From, a.To))
+			cutCap += w
+		}
+		assert.Equal(t, flow.Value(), cutCap)
+	}
+}
+
+func TestMaxFlowUndirected(t *testing.T) {
+	g := NewGraph[string, int](Undirected)
+	g.AddEdge("s", "a", 3)
+	g.AddEdge("s", "b", 2)
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("b", "a", 1) // parallel edge
+	g.AddEdge("a", "t", 2)
+	g.AddEdge("t", "b", 3)
+	ek := g.MaxFlowEdmondsKarp("s", "t")
+	dinic := g.MaxFlowDinic("s", "t")
+	assert.Equal(t, 5, ek.Value())
+	assert.Equal(t, 5, dinic.Value())
+	// flow along a-b should be the net flow, in one direction only
+	assert.True(t, ek.EdgeFlow("a", "b") == 0 || ek.EdgeFlow("b", "a") == 0)
+	assert.True(t, dinic.EdgeFlow("a", "b") == 0 || dinic.EdgeFlow("b", "a") == 0)
+}
+
+func TestMaxFlowNoPath(t *testing.T) {
+	const ms = `0 5 0 0
+	            0 0 0 0
+	            0 0 0 3
+	            0 0 0 0`
+	m := matrix.ParseMatrix(ms, strconv.Atoi)
+	g := GraphFromAdjacencyMatrix[int](m, Directed)
+	for _, maxFlow := range maxFlowFuncs {
+		flow := maxFlow(g, 0, 3)
+		assert.Equal(t, 0, flow.Value())
+		assert.Equal(t, 0, len(flow.CutEdges()))
+		source, sink := flow.MinCut()
+		assert.Equal(t, 2, len(source))
+		assert.Equal(t, 2, len(sink))
+
+		flow = maxFlow(g, 0, 0)
+		assert.Equal(t, 0, flow.Value())
+		flow = maxFlow(g, 0, 42)
+		assert.Equal(t, 0, flow.Value())
+	}
+}
+
+func TestMaxFlowUnsigned(t *testing.T) {
+	g := NewGraph[int, uint8](Directed)
+	g.AddEdge(0, 1, 10)
+	g.AddEdge(0, 2, 10)
+	g.AddEdge(1, 2, 2)
+	g.AddEdge(2, 1, 6)
+	g.AddEdge(1, 3, 4)
+	g.AddEdge(2, 3, 10)
+	g.AddEdge(1, 4, 8)
+	g.AddEdge(3, 4, 10)
+	ek := g.MaxFlowEdmondsKarp(0, 4)
+	dinic := g.MaxFlowDinic(0, 4)
+	assert.Equal(t, uint8(18), ek.Value())
+	assert.Equal(t, uint8(18), dinic.Value())
+}
+
+// checkFlow verifies the capacity and conservation constraints of a flow.
+func checkFlow(t *testing.T, g *Graph[int, int], flow *Flow[int, int], s, sink int) {
+	t.Helper()
+	net := map[int]int{}
+	flow.VisitEdgeFlows(func(from, to int, f int) {
+		w, found := g.GetEdgeWeight(from, to)
+		assert.True(t, found)
+		assert.True(t, f > 0 && f <= w)
+		net[from] -= f
+		net[to] += f
+	})
+	for v, f := range net {
+		switch v {
+		case s:
+			assert.Equal(t, -flow.Value(), f)
+		case sink:
+			assert.Equal(t, flow.Value(), f)
+		default:
+			assert.Equal(t, 0, f)
+		}
+	}
+}
+
+func TestMaxFlowFloat(t *testing.T) {
+	m := matrix.ParseMatrix(clrsFlowNetwork, func(s string) (float64, error) {
+		v, err := strconv.ParseFloat(s, 64)
+		return v / 4, err
+	})
+	g := GraphFromAdjacencyMatrix(m, Directed)
+	ek := g.MaxFlowEdmondsKarp(0, 5)
+	dinic := g.MaxFlowDinic(0, 5)
+	assert.EqualEpsilon(t, 23.0/4, ek.Value(), 1e-9)
+	assert.EqualEpsilon(t, 23.0/4, dinic.Value(), 1e-9)
+}
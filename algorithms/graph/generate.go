@@ -0,0 +1,222 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+// Generators of random (and regular) graphs, for testing and benchmarking.
+// Nodes are numbered 0..n-1. Generators are seeded, so that a given seed
+// always generates the same graph.
+
+import (
+	"math"
+	"math/rand"
+)
+
+// GeneratorOptions control how graphs are generated.
+type GeneratorOptions[W Weight] struct {
+	// Directed indicates that a directed graph should be generated.
+	Directed GraphType
+	// Seed for the random number generator
+	Seed int64
+	// Weight returns the weight of each generated edge. Defaults to a
+	// weight of one.
+	Weight func(rnd *rand.Rand) W
+}
+
+// generator holds the state used while generating a graph.
+type generator[W Weight] struct {
+	g    *Graph[int, W]
+	rnd  *rand.Rand
+	opts GeneratorOptions[W]
+}
+
+func newGenerator[W Weight](n int, directed GraphType, opts GeneratorOptions[W]) *generator[W] {
+	gen := &generator[W]{
+		g:    NewGraph[int, W](directed),
+		rnd:  rand.New(rand.NewSource(opts.Seed)),
+		opts: opts,
+	}
+	for v := range n {
+		gen.g.AddNode(v)
+	}
+	return gen
+}
+
+func (gen *generator[W]) addEdge(from, to int) {
+	var w W = 1
+	if gen.opts.Weight != nil {
+		w = gen.opts.Weight(gen.rnd)
+	}
+	gen.g.AddEdge(from, to, w)
+}
+
+// ErdosRenyi generates a G(n,p) random graph, in which each possible edge
+// (between distinct nodes) is included with probability p, independent of
+// the other edges. The expected number of edges is p*n*(n-1), halved if the
+// graph is undirected. Runs in time proportional to the number of nodes and
+// edges, so is suitable for large sparse graphs.
+func ErdosRenyi[W Weight](n int, p float64, opts GeneratorOptions[W]) *Graph[int, W] {
+	gen := newGenerator(n, opts.Directed, opts)
+	if p <= 0 || n < 2 {
+		return gen.g
+	}
+	// The possible edges are numbered; rather than testing each one, the gap
+	// to the next included edge is drawn from the geometric distribution. See:
+	// Batagelj and Brandes, "Efficient generation of large random networks"
+	pairs := n * (n - 1)
+	if !opts.Directed {
+		pairs /= 2
+	}
+	logQ := math.Log(1 - p)
+	for i := -1; ; {
+		if p >= 1 {
+			i++
+		} else {
+			i += 1 + int(math.Log(1-gen.rnd.Float64())/logQ)
+		}
+		if i >= pairs || i < 0 {
+			break
+		}
+		if opts.Directed {
+			// edge i is from u to the (i mod n-1)th of the other nodes
+			u, v := i/(n-1), i%(n-1)
+			if v >= u {
+				v++
+			}
+			gen.addEdge(u, v)
+		} else {
+			// edge i is between v and w < v, where i = v(v-1)/2 + w
+			v := int((1 + math.Sqrt(1+8*float64(i))) / 2)
+			for v*(v-1)/2 > i {
+				v--
+			}
+			for (v+1)*v/2 <= i {
+				v++
+			}
+			gen.addEdge(v, i-v*(v-1)/2)
+		}
+	}
+	return gen.g
+}
+
+// BarabasiAlbert generates a scale-free random graph using the
+// Barabási-Albert preferential attachment model. Starting with m nodes
+// (without edges), each new node is connected to m distinct existing nodes,
+// chosen with probability proportional to their degree. The resulting degree
+// distribution follows a power law, as in many real networks. If the graph is
+// directed, edges are from new nodes to existing nodes.
+func BarabasiAlbert[W Weight](n, m int, opts GeneratorOptions[W]) *Graph[int, W] {
+	gen := newGenerator(n, opts.Directed, opts)
+	if m < 1 || n <= m {
+		return gen.g
+	}
+	// Each node appears in this list once for each of its edges, so a node
+	// chosen uniformly from the list is chosen in proportion to its degree.
+	ends := make([]int, 0, 2*m*(n-m))
+	targets := make([]int, m)
+	chosen := make(map[int]bool, m)
+	for v := range m {
+		// the first new node is connected to all of the initial nodes
+		targets[v] = v
+	}
+	for v := m; v < n; v++ {
+		for _, u := range targets {
+			gen.addEdge(v, u)
+			ends = append(ends, v, u)
+		}
+		clear(chosen)
+		for i := range m {
+			u := ends[gen.rnd.Intn(len(ends))]
+			for chosen[u] {
+				u = ends[gen.rnd.Intn(len(ends))]
+			}
+			chosen[u] = true
+			targets[i] = u
+		}
+	}
+	return gen.g
+}
+
+// WattsStrogatz generates a small-world random graph using the Watts-Strogatz
+// model. Nodes are placed in a ring, and each is connected to its k nearest
+// neighbors (k/2 on each side; k should be even.) Then each edge is rewired
+// with probability beta, by replacing its far end with a node chosen at
+// random (avoiding self-loops and parallel edges.) A small beta results in
+// high clustering along with short paths between nodes.
+func WattsStrogatz[W Weight](n, k int, beta float64, opts GeneratorOptions[W]) *Graph[int, W] {
+	gen := newGenerator(n, opts.Directed, opts)
+	if k < 2 || n <= k {
+		return gen.g
+	}
+	// Determine the edges first, so that rewiring can avoid creating
+	// parallel edges.
+	type pair struct{ u, v int }
+	adjacent := map[pair]bool{}
+	degree := make([]int, n)
+	connect := func(u, v int) {
+		adjacent[pair{u, v}], adjacent[pair{v, u}] = true, true
+		degree[u]++
+		degree[v]++
+	}
+	edges := []pair{}
+	for u := range n {
+		for j := 1; j <= k/2; j++ {
+			v := (u + j) % n
+			edges = append(edges, pair{u, v})
+			connect(u, v)
+		}
+	}
+	for i, e := range edges {
+		if gen.rnd.Float64() >= beta || degree[e.u] == n-1 {
+			// not rewired (or can't be, as u is adjacent to every node)
+			continue
+		}
+		w := gen.rnd.Intn(n)
+		for w == e.u || adjacent[pair{e.u, w}] {
+			w = gen.rnd.Intn(n)
+		}
+		delete(adjacent, pair{e.u, e.v})
+		delete(adjacent, pair{e.v, e.u})
+		degree[e.u]--
+		degree[e.v]--
+		connect(e.u, w)
+		edges[i].v = w
+	}
+	for _, e := range edges {
+		gen.addEdge(e.u, e.v)
+	}
+	return gen.g
+}
+
+// Grid generates a 2D grid graph, with the given number of rows and columns.
+// The node in row r and column c is r*cols+c, and is connected to the nodes
+// to its right and below it (if any.) If the graph is directed, edges are to
+// the right and down.
+func Grid[W Weight](rows, cols int, opts GeneratorOptions[W]) *Graph[int, W] {
+	gen := newGenerator(rows*cols, opts.Directed, opts)
+	for r := range rows {
+		for c := range cols {
+			v := r*cols + c
+			if c+1 < cols {
+				gen.addEdge(v, v+1)
+			}
+			if r+1 < rows {
+				gen.addEdge(v, v+cols)
+			}
+		}
+	}
+	return gen.g
+}
+
+// RandomDAG generates a random directed acyclic graph, in which there is an
+// edge from node u to node v, for each u < v, with probability p. The nodes'
+// numeric order is a topological order. The graph is always directed.
+func RandomDAG[W Weight](n int, p float64, opts GeneratorOptions[W]) *Graph[int, W] {
+	gen := newGenerator(n, Directed, opts)
+	for u := range n {
+		for v := u + 1; v < n; v++ {
+			if gen.rnd.Float64() < p {
+				gen.addEdge(u, v)
+			}
+		}
+	}
+	return gen.g
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+// assertSimple asserts that the graph has no self-loops or parallel edges
+func assertSimple[W Weight](t *testing.T, g *Graph[int, W]) {
+	t.Helper()
+	seen := map[Arc[int]]bool{}
+	g.visitEdges(func(from, to int, _ W) {
+		assert.NotEqual(t, from, to)
+		if !g.directed && to < from {
+			from, to = to, from
+		}
+		assert.False(t, seen[Arc[int]{from, to}])
+		seen[Arc[int]{from, to}] = true
+	})
+}
+
+func TestErdosRenyi(t *testing.T) {
+	for _, directed := range []GraphType{Directed, Undirected} {
+		opts := GeneratorOptions[int]{Directed: directed, Seed: 1}
+		g := ErdosRenyi(1000, 0.01, opts)
+		assert.Equal(t, directed, g.Type())
+		assert.Equal(t, 1000, g.NodeCount())
+		expected := 0.01 * 1000 * 999
+		if !directed {
+			expected /= 2
+		}
+		assert.EqualEpsilon(t, expected, float64(g.EdgeCount()), expected*0.05)
+		assertSimple(t, g)
+		// same seed, same graph
+		assert.DeepEqual(t, g, ErdosRenyi(1000, 0.01, opts))
+		opts.Seed = 2
+		assert.NotEqual(t, g.EdgeCount(), ErdosRenyi(1000, 0.01, opts).EdgeCount())
+		// complete and empty graphs
+		assert.Equal(t, int(expected/0.01), ErdosRenyi(1000, 1, opts).EdgeCount())
+		assert.Equal(t, 0, ErdosRenyi(1000, 0, opts).EdgeCount())
+	}
+	assert.Equal(t, 0, ErdosRenyi(0, 0.5, GeneratorOptions[int]{}).NodeCount())
+}
+
+func TestBarabasiAlbert(t *testing.T) {
+	opts := GeneratorOptions[int]{Seed: 1}
+	g := BarabasiAlbert(2000, 3, opts)
+	assert.Equal(t, 2000, g.NodeCount())
+	assert.Equal(t, 3*(2000-3), g.EdgeCount())
+	assertSimple(t, g)
+	assert.DeepEqual(t, g, BarabasiAlbert(2000, 3, opts))
+	// preferential attachment results in hubs, with much greater degree than
+	// the average
+	maxDegree := 0
+	for _, d := range g.OutDegree() {
+		maxDegree = max(maxDegree, d)
+	}
+	assert.True(t, maxDegree > 10*6)
+	// every node is reachable from the first
+	bft := g.BFS(0, 0)
+	assert.Equal(t, 2000, bft.NodeCount())
+	assert.Equal(t, 0, BarabasiAlbert(3, 3, opts).EdgeCount())
+}
+
+func TestWattsStrogatz(t *testing.T) {
+	opts := GeneratorOptions[int]{Seed: 1}
+	// without rewiring, a ring lattice
+	g := WattsStrogatz(100, 4, 0, opts)
+	assert.Equal(t, 200, g.EdgeCount())
+	for v, d := range g.OutDegree() {
+		assert.Equal(t, 4, d)
+		assert.True(t, g.HasEdge(v, (v+1)%100))
+		assert.True(t, g.HasEdge(v, (v+2)%100))
+	}
+	// rewiring preserves the number of edges, and shortens paths
+	g = WattsStrogatz(1000, 6, 0.1, opts)
+	assert.Equal(t, 3000, g.EdgeCount())
+	assertSimple(t, g)
+	assert.DeepEqual(t, g, WattsStrogatz(1000, 6, 0.1, opts))
+	_, lattice := WattsStrogatz(1000, 6, 0, opts).FindPath(0, 500)
+	_, rewired := g.FindPath(0, 500)
+	assert.Equal(t, 167, lattice)
+	assert.True(t, rewired < lattice/4)
+	// a complete graph can't be rewired
+	assert.Equal(t, 10, WattsStrogatz(5, 4, 1, opts).EdgeCount())
+}
+
+func TestGrid(t *testing.T) {
+	g := Grid(3, 4, GeneratorOptions[int]{})
+	assert.Equal(t, 12, g.NodeCount())
+	assert.Equal(t, 3*3+2*4, g.EdgeCount())
+	path, weight := g.FindPath(0, 11)
+	assert.Equal(t, 5, weight)
+	assert.Equal(t, 6, len(path))
+	assert.True(t, g.HasEdge(5, 1))
+	g = Grid(3, 4, GeneratorOptions[int]{Directed: true})
+	assert.False(t, g.HasEdge(5, 1))
+	_, weight = g.FindPath(11, 0)
+	assert.Equal(t, 0, weight)
+}
+
+func TestRandomDAG(t *testing.T) {
+	opts := GeneratorOptions[float64]{
+		Directed: Undirected, // ignored
+		Seed:     1,
+		Weight: func(rnd *rand.Rand) float64 {
+			return rnd.Float64()
+		},
+	}
+	g := RandomDAG(200, 0.1, opts)
+	assert.Equal(t, Directed, g.Type())
+	assert.EqualEpsilon(t, 0.1*200*199/2, float64(g.EdgeCount()), 100)
+	g.visitEdges(func(from, to int, weight float64) {
+		assert.True(t, from < to)
+		assert.True(t, weight >= 0 && weight < 1)
+	})
+	assertSimple(t, g)
+	assert.DeepEqual(t, g, RandomDAG(200, 0.1, opts))
+}
+
+func BenchmarkBFSBarabasiAlbert(b *testing.B) {
+	g := BarabasiAlbert(100000, 5, GeneratorOptions[int]{Seed: 1})
+	b.ResetTimer()
+	for range b.N {
+		g.BFS(0, 0)
+	}
+}
+
+func BenchmarkBFSGrid(b *testing.B) {
+	g := Grid(300, 300, GeneratorOptions[int]{})
+	b.ResetTimer()
+	for range b.N {
+		g.BFS(0, 0)
+	}
+}
@@ -3,8 +3,11 @@
 package graph
 
 import (
+	"cmp"
 	"fmt"
 	"io"
+	"reflect"
+	"slices"
 
 	"github.com/tommika/gorilla/algorithms/matrix"
 	"github.com/tommika/gorilla/util"
@@ -20,48 +23,155 @@ const (
 	Directed GraphType = true
 )
 
+// Weight is the type of the weight associated with each edge of a graph.
+// Floating-point weights are supported, but NaN is not a valid weight.
 type Weight interface {
-	int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64
+	int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64 | float32 | float64
+}
+
+// EdgePolicy determines how a graph handles parallel edges and self-loops.
+// The zero value is the default policy: a multigraph, without self-loops.
+type EdgePolicy struct {
+	// Simple indicates that there is at most one edge from one node to
+	// another. Adding an edge that already exists replaces its weight.
+	// Otherwise the graph is a multigraph, and parallel edges are added.
+	Simple bool
+	// SelfLoops indicates that edges from a node to itself are allowed.
+	// Otherwise such edges are ignored.
+	SelfLoops bool
+}
+
+// EdgeResult indicates what happened when adding an edge to a graph.
+type EdgeResult int
+
+const (
+	// EdgeAdded indicates that a new edge was added
+	EdgeAdded EdgeResult = iota
+	// EdgeReplaced indicates that the weight of an existing edge was replaced
+	// (simple graphs only)
+	EdgeReplaced
+	// EdgeIgnoredSelfLoop indicates that the edge was ignored because it's a
+	// self-loop, and the graph does not allow self-loops
+	EdgeIgnoredSelfLoop
+	// EdgeIgnoredNaN indicates that the edge was ignored because its weight is NaN
+	EdgeIgnoredNaN
+)
+
+func (r EdgeResult) String() string {
+	switch r {
+	case EdgeAdded:
+		return "added"
+	case EdgeReplaced:
+		return "replaced"
+	case EdgeIgnoredSelfLoop:
+		return "ignored (self-loop)"
+	case EdgeIgnoredNaN:
+		return "ignored (NaN weight)"
+	}
+	return fmt.Sprintf("EdgeResult(%d)", int(r))
 }
 
 type Graph[N comparable, W Weight] struct {
 	directed GraphType
+	policy   EdgePolicy
 	numEdges int
 	nodes    map[N]*node[N, W]
 }
 
 // NewGraph creates an empty graph of the given type (Directed or Undirected)
 func NewGraph[N comparable, W Weight](directed GraphType) *Graph[N, W] {
+	return NewGraphWithPolicy[N, W](directed, EdgePolicy{})
+}
+
+// NewGraphWithPolicy creates an empty graph of the given type (Directed or
+// Undirected) that handles parallel edges and self-loops according to the
+// given policy.
+func NewGraphWithPolicy[N comparable, W Weight](directed GraphType, policy EdgePolicy) *Graph[N, W] {
 	g := &Graph[N, W]{
 		numEdges: 0,
 		nodes:    make(map[N]*node[N, W], 0),
 		directed: directed,
+		policy:   policy,
 	}
 	return g
 }
 
-// GraphFromAdjacencyMatrix constructs a graph from the given adjacency matrix. If the
-// graph is undirected, the only cells above the diagonal are considered.
-func GraphFromAdjacencyMatrix[W Weight](m matrix.Matrix[W], directed GraphType) *Graph[int, W] {
+// GraphFromAdjacencyMatrix constructs a graph from the given adjacency matrix,
+// which may be dense (matrix.Matrix) or sparse (e.g., matrix.CSR). Cells that
+// are zero (or NaN) indicate the absence of an edge, and cells on the diagonal
+// are ignored. If the graph is undirected, an edge may be given by either of
+// its two cells; if both are set (e.g., the matrix is symmetric), only the
+// first one enumerated (i.e., the one above the diagonal) adds an edge.
+func GraphFromAdjacencyMatrix[W Weight](m matrix.Enumerable[W], directed GraphType) *Graph[int, W] {
 	g := NewGraph[int, W](directed)
-	for r := range m.NumRows() {
-		for c := range m.NumCols() {
-			w := m.Get(r, c)
-			if r != c && w != util.Zero[W]() {
-				g.AddEdge(r, c, w)
-			}
+	for e := range m.Entries() {
+		if e.Row == e.Col || isZeroWeight(e.Value) {
+			continue
 		}
+		if !directed && g.HasEdge(e.Row, e.Col) {
+			// the edge was given by the other cell
+			continue
+		}
+		g.AddEdge(e.Row, e.Col, e.Value)
 	}
 	return g
 }
 
-// Fprintf outputs the graph to the given writer
+// ToAdjacencyMatrix constructs the adjacency matrix of the graph, from which
+// GraphFromAdjacencyMatrix constructs the graph again. The returned nodes give the node
+// represented by each row (and column) of the matrix. Nodes are ordered by
+// value when the node type is ordered (e.g., integers or strings), so a graph
+// of int nodes 0..n-1 maps node i to row i. The weights of parallel edges are
+// summed. If the graph is undirected, only the cells above the diagonal are
+// set.
+func (g *Graph[N, W]) ToAdjacencyMatrix() (m matrix.Matrix[W], nodes []N) {
+	nodes = g.sortedNodes()
+	index := make(map[N]int, len(nodes))
+	for i, v := range nodes {
+		index[v] = i
+	}
+	m = matrix.NewMatrix(len(nodes), len(nodes), util.Zero[W]())
+	for v, node := range g.nodes {
+		for _, e := range node.outgoing {
+			r, c := index[v], index[e.node]
+			if !g.directed && r > c {
+				r, c = c, r
+			}
+			m.Set(r, c, m.Get(r, c)+e.weight)
+		}
+	}
+	return
+}
+
+// ToSparseAdjacencyMatrix constructs the adjacency matrix of the graph in CSR
+// format, which is suited to large graphs; it's otherwise the same as
+// ToAdjacencyMatrix.
+func (g *Graph[N, W]) ToSparseAdjacencyMatrix() (m *matrix.CSR[W], nodes []N) {
+	nodes = g.sortedNodes()
+	index := make(map[N]int, len(nodes))
+	for i, v := range nodes {
+		index[v] = i
+	}
+	coo := matrix.NewCOO[W](len(nodes), len(nodes))
+	for v, node := range g.nodes {
+		for _, e := range node.outgoing {
+			r, c := index[v], index[e.node]
+			if !g.directed && r > c {
+				r, c = c, r
+			}
+			coo.Add(r, c, e.weight)
+		}
+	}
+	return coo.ToCSR(), nodes
+}
+
+// Fprint outputs the graph to the given writer
 func (g *Graph[N, W]) Fprint(out io.Writer) {
-	var dir rune
+	dir := '-'
 	if g.directed {
 		dir = '>'
 	}
-	fmt.Printf("#nodes=%d, #edges=%d, directed=%t\n", g.NodeCount(), g.EdgeCount(), g.directed)
+	fmt.Fprintf(out, "#nodes=%d, #edges=%d, directed=%t\n", g.NodeCount(), g.EdgeCount(), g.directed)
 	for l, v := range g.nodes {
 		for _, e := range v.outgoing {
 			fmt.Fprintf(out, "[%v] --(%v)--%c [%v]\n", l, e.weight, dir, e.node)
@@ -72,6 +182,11 @@ func (g *Graph[N, W]) Type() GraphType {
 	return g.directed
 }
 
+// Policy returns the policy used to handle parallel edges and self-loops.
+func (g *Graph[N, W]) Policy() EdgePolicy {
+	return g.policy
+}
+
 func (g *Graph[N, W]) NodeCount() int {
 	return len(g.nodes)
 }
@@ -80,21 +195,33 @@ func (g *Graph[N, W]) EdgeCount() int {
 	return g.numEdges
 }
 
-func (g *Graph[N, W]) AddEdge(from, to N, weight W) {
-	if from == to {
-		// REVIEW: may want to return some indication of this edge being ignored
-		return
+// AddNode adds an (isolated) node to the graph. Returns false if the node
+// already exists.
+func (g *Graph[N, W]) AddNode(n N) (added bool) {
+	if g.HasNode(n) {
+		return false
 	}
-	nFrom, found := g.nodes[from]
-	if !found {
-		nFrom = g.newNode(from)
-		g.nodes[from] = nFrom
+	g.nodes[n] = g.newNode(n)
+	return true
+}
+
+// AddEdge adds an edge from one node to another, adding the nodes as needed.
+// Edges with a NaN weight are ignored, as are self-loops, unless allowed by
+// the graph's policy. If the graph is simple, and the edge already exists,
+// then its weight is replaced.
+func (g *Graph[N, W]) AddEdge(from, to N, weight W) EdgeResult {
+	if isNaN(weight) {
+		return EdgeIgnoredNaN
 	}
-	nTo, found := g.nodes[to]
-	if !found {
-		nTo = g.newNode(to)
-		g.nodes[to] = nTo
+	if from == to && !g.policy.SelfLoops {
+		return EdgeIgnoredSelfLoop
 	}
+	if g.policy.Simple && g.SetEdgeWeight(from, to, weight) {
+		return EdgeReplaced
+	}
+	g.AddNode(from)
+	g.AddNode(to)
+	nFrom, nTo := g.nodes[from], g.nodes[to]
 
 	// When adding an edge, we do not distinguish between directed and undirected.
 	// Rather, the edge is added as if it were directed, by adding it to the
@@ -107,6 +234,96 @@ func (g *Graph[N, W]) AddEdge(from, to N, weight W) {
 	nTo.incoming = append(nTo.incoming, g.newEdge(from, weight))
 
 	g.numEdges++
+	return EdgeAdded
+}
+
+// SetEdgeWeight sets the weight of the edge from one node to another. If the
+// graph is a multigraph, the weight of all parallel edges is set. Returns false
+// if there is no such edge.
+func (g *Graph[N, W]) SetEdgeWeight(from, to N, weight W) (found bool) {
+	if !g.HasNode(from) || !g.HasNode(to) || isNaN(weight) {
+		return false
+	}
+	// Each edge is recorded twice: once as an outgoing edge of the source
+	// node, and once as an incoming edge of the target node; both need to be
+	// updated.
+	set := func(edges []edge[N, W], n N) {
+		for i := range edges {
+			if edges[i].node == n {
+				edges[i].weight = weight
+				found = true
+			}
+		}
+	}
+	set(g.nodes[from].outgoing, to)
+	set(g.nodes[to].incoming, from)
+	if !g.directed {
+		set(g.nodes[to].outgoing, from)
+		set(g.nodes[from].incoming, to)
+	}
+	return
+}
+
+// RemoveEdge removes the edge from one node to another, and returns the
+// number of edges removed. If the graph is a multigraph, all parallel edges
+// are removed. If the graph is undirected, edges in both directions are
+// removed. Nodes are never removed, even if they become isolated.
+func (g *Graph[N, W]) RemoveEdge(from, to N) (removed int) {
+	if !g.HasNode(from) || !g.HasNode(to) {
+		return 0
+	}
+	removed = g.removeDirectedEdge(from, to)
+	if !g.directed && from != to {
+		removed += g.removeDirectedEdge(to, from)
+	}
+	g.numEdges -= removed
+	return
+}
+
+// removeDirectedEdge removes the edges recorded in the outgoing list of the
+// source node (and the incoming list of the target node.)
+func (g *Graph[N, W]) removeDirectedEdge(from, to N) (removed int) {
+	nFrom, nTo := g.nodes[from], g.nodes[to]
+	n := len(nFrom.outgoing)
+	nFrom.outgoing = removeEdgesTo(nFrom.outgoing, to)
+	nTo.incoming = removeEdgesTo(nTo.incoming, from)
+	return n - len(nFrom.outgoing)
+}
+
+// RemoveNode removes a node, and all of its edges, from the graph. Returns
+// false if the node does not exist.
+func (g *Graph[N, W]) RemoveNode(n N) (removed bool) {
+	node, found := g.nodes[n]
+	if !found {
+		return false
+	}
+	selfLoops := 0
+	for _, e := range node.outgoing {
+		if e.node == n {
+			selfLoops++
+			continue
+		}
+		nTo := g.nodes[e.node]
+		nTo.incoming = removeEdgesTo(nTo.incoming, n)
+	}
+	for _, e := range node.incoming {
+		if e.node == n {
+			continue
+		}
+		nFrom := g.nodes[e.node]
+		nFrom.outgoing = removeEdgesTo(nFrom.outgoing, n)
+	}
+	// self-loops appear in both the outgoing and incoming lists
+	g.numEdges -= len(node.outgoing) + len(node.incoming) - selfLoops
+	delete(g.nodes, n)
+	return true
+}
+
+// removeEdgesTo removes all edges to the given node from the slice.
+func removeEdgesTo[N comparable, W Weight](edges []edge[N, W], n N) []edge[N, W] {
+	return slices.DeleteFunc(edges, func(e edge[N, W]) bool {
+		return e.node == n
+	})
 }
 
 // HasNode determines if the graph includes the given node.
@@ -200,3 +417,43 @@ func (g *Graph[N, W]) newEdge(n N, weight W) edge[N, W] {
 		weight: weight,
 	}
 }
+
+// sortedNodes returns the nodes of the graph, sorted by value if the node
+// type is ordered. Otherwise the nodes are returned in no particular order.
+func (g *Graph[N, W]) sortedNodes() []N {
+	nodes := make([]N, 0, len(g.nodes))
+	for v := range g.nodes {
+		nodes = append(nodes, v)
+	}
+	slices.SortStableFunc(nodes, compareNodes[N])
+	return nodes
+}
+
+// compareNodes compares nodes whose underlying type is ordered. Nodes of any
+// other type compare as equal.
+func compareNodes[N comparable](a, b N) int {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(va.Int(), vb.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(va.Uint(), vb.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(va.Float(), vb.Float())
+	case reflect.String:
+		return cmp.Compare(va.String(), vb.String())
+	}
+	return 0
+}
+
+// isNaN determines if the given weight is a floating-point NaN; i.e., the only
+// value that is not equal to itself.
+func isNaN[W Weight](w W) bool {
+	return w != w
+}
+
+// isZeroWeight determines if the given weight is zero, or is NaN. Either
+// indicates the absence of an edge in an adjacency matrix.
+func isZeroWeight[W Weight](w W) bool {
+	return w == util.Zero[W]() || isNaN(w)
+}
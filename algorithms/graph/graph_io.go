@@ -3,7 +3,12 @@ package graph
 
 import (
 	"errors"
+	"fmt"
 	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
 )
 
 type EdgeWriter[N, W any] func(out io.Writer, from, to N, weight W) (int, error)
@@ -32,3 +37,193 @@ func (g *Graph[N, W]) ReadEdges(in io.Reader, readEdge EdgeReader[N, W]) error {
 		}
 	}
 }
+
+// ExportOptions customize how a graph is written in a standard graph format
+// (DOT or GraphML.)
+type ExportOptions[N comparable, W Weight] struct {
+	// Name of the graph (optional)
+	Name string
+	// NodeId returns the identifier used for a node. Defaults to the node's
+	// default string format (i.e., fmt.Sprint.)
+	NodeId func(n N) string
+	// NodeAttrs returns additional attributes of a node; e.g., a label or
+	// color (optional.)
+	NodeAttrs func(n N) map[string]string
+	// EdgeAttrs returns additional attributes of an edge (optional.) The
+	// weight of an edge is always written as an attribute: "weight" in
+	// GraphML, and "edge_weight" in DOT (as Graphviz takes "weight" to be a
+	// non-negative integer layout hint.)
+	EdgeAttrs func(from, to N, weight W) map[string]string
+}
+
+// ImportOptions customize how a graph is read from a standard graph format
+// (DOT or GraphML.)
+type ImportOptions[N comparable, W Weight] struct {
+	// ParseNode converts a node identifier to a node. Defaults to parsing
+	// the whole identifier as a number (or using the identifier itself, for
+	// string nodes.)
+	ParseNode func(id string) (N, error)
+	// ParseWeight converts the weight attribute of an edge (see
+	// ExportOptions.EdgeAttrs) to a weight; for DOT, the "weight" attribute
+	// is used if there's no "edge_weight" attribute. Defaults to parsing the
+	// whole attribute as a number. Edges without a weight attribute have a
+	// zero weight.
+	ParseWeight func(s string) (W, error)
+	// NodeAttrs is called with the attributes of each node (optional.)
+	NodeAttrs func(n N, attrs map[string]string)
+	// Policy is the edge policy of the constructed graph.
+	Policy EdgePolicy
+}
+
+const (
+	// weightAttr is the name of the attribute used to hold the weight of an
+	// edge
+	weightAttr = "weight"
+	// dotWeightAttr is the name of the attribute used to hold the weight of
+	// an edge in DOT, where "weight" is a layout hint
+	dotWeightAttr = "edge_weight"
+)
+
+func (opts *ExportOptions[N, W]) nodeId(n N) string {
+	if opts.NodeId != nil {
+		return opts.NodeId(n)
+	}
+	return formatValue(n)
+}
+
+func (opts *ExportOptions[N, W]) nodeAttrs(n N) map[string]string {
+	if opts.NodeAttrs != nil {
+		return opts.NodeAttrs(n)
+	}
+	return nil
+}
+
+func (opts *ExportOptions[N, W]) edgeAttrs(from, to N, weight W, weightAttr string) map[string]string {
+	attrs := map[string]string{}
+	if opts.EdgeAttrs != nil {
+		maps.Copy(attrs, opts.EdgeAttrs(from, to, weight))
+	}
+	attrs[weightAttr] = formatValue(weight)
+	return attrs
+}
+
+func (opts *ImportOptions[N, W]) parseNode(id string) (n N, err error) {
+	if opts.ParseNode != nil {
+		return opts.ParseNode(id)
+	}
+	return parseValue[N](id)
+}
+
+// parseWeight parses the first of the given weight attributes found.
+func (opts *ImportOptions[N, W]) parseWeight(attrs map[string]string, weightAttrs []string) (w W, err error) {
+	var s string
+	found := false
+	for _, name := range weightAttrs {
+		if s, found = attrs[name]; found {
+			break
+		}
+	}
+	if !found {
+		return
+	}
+	if opts.ParseWeight != nil {
+		return opts.ParseWeight(s)
+	}
+	return parseValue[W](s)
+}
+
+// parseValue is the default parser for nodes and weights. Values of string
+// kind are taken as-is; values of integer, floating-point and boolean kinds are
+// parsed using strconv, and the whole string must be a valid value of the
+// type. Other types require a parser.
+func parseValue[T any](s string) (v T, err error) {
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		if i, err = strconv.ParseInt(s, 10, rv.Type().Bits()); err == nil {
+			rv.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		if u, err = strconv.ParseUint(s, 10, rv.Type().Bits()); err == nil {
+			rv.SetUint(u)
+		}
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		if f, err = strconv.ParseFloat(s, rv.Type().Bits()); err == nil {
+			rv.SetFloat(f)
+		}
+	case reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(s); err == nil {
+			rv.SetBool(b)
+		}
+	default:
+		err = fmt.Errorf("no default parser for %v", rv.Type())
+	}
+	return
+}
+
+// formatValue is the default formatter for nodes and weights.
+func formatValue[T any](v T) string {
+	return fmt.Sprint(v)
+}
+
+// builder constructs a graph while it's being read, tracking the nodes
+// that have been seen by identifier.
+type builder[N comparable, W Weight] struct {
+	opts        *ImportOptions[N, W]
+	weightAttrs []string // attributes that may hold the weight of an edge
+	g           *Graph[N, W]
+	nodes       map[string]N
+}
+
+func newBuilder[N comparable, W Weight](opts *ImportOptions[N, W], directed GraphType, weightAttrs ...string) *builder[N, W] {
+	return &builder[N, W]{
+		opts:        opts,
+		weightAttrs: weightAttrs,
+		g:           NewGraphWithPolicy[N, W](directed, opts.Policy),
+		nodes:       map[string]N{},
+	}
+}
+
+func (b *builder[N, W]) node(id string, attrs map[string]string) (n N, err error) {
+	n, found := b.nodes[id]
+	if !found {
+		if n, err = b.opts.parseNode(id); err != nil {
+			return n, fmt.Errorf("invalid node %q: %w", id, err)
+		}
+		b.nodes[id] = n
+		b.g.AddNode(n)
+	}
+	if attrs != nil && b.opts.NodeAttrs != nil {
+		b.opts.NodeAttrs(n, attrs)
+	}
+	return
+}
+
+func (b *builder[N, W]) edge(fromId, toId string, attrs map[string]string) error {
+	from, err := b.node(fromId, nil)
+	if err != nil {
+		return err
+	}
+	to, err := b.node(toId, nil)
+	if err != nil {
+		return err
+	}
+	w, err := b.opts.parseWeight(attrs, b.weightAttrs)
+	if err != nil {
+		return fmt.Errorf("invalid weight for edge %q to %q: %w", fromId, toId, err)
+	}
+	b.g.AddEdge(from, to, w)
+	return nil
+}
+
+// sortedAttrNames returns the names of the given attributes in sorted order, so
+// that output is deterministic.
+func sortedAttrNames(attrs map[string]string) []string {
+	return slices.Sorted(maps.Keys(attrs))
+}
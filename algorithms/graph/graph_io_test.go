@@ -59,3 +59,27 @@ func TestGraphIO(t *testing.T) {
 	testGraphIO(t, g2)
 
 }
+
+func TestGraphIOFloat(t *testing.T) {
+	g := NewGraph[string, float64](Directed)
+	g.AddEdge("a", "b", 0.25)
+	g.AddEdge("b", "c", 1e-3)
+	g.AddEdge("c", "a", -12.5)
+	buffer := bytes.Buffer{}
+	err := g.WriteEdges(&buffer, func(out io.Writer, from, to string, weight float64) (int, error) {
+		return fmt.Fprintf(out, "%s %s %g\n", from, to, weight)
+	})
+	assert.Nil(t, err)
+	gT := NewGraph[string, float64](Directed)
+	err = gT.ReadEdges(&buffer, func(in io.Reader) (from, to string, weight float64, err error) {
+		_, err = fmt.Fscanf(in, "%s %s %g\n", &from, &to, &weight)
+		return
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, gT.EdgeCount())
+	w, found := gT.GetEdgeWeight("c", "a")
+	assert.True(t, found)
+	assert.Equal(t, -12.5, w)
+	w, _ = gT.GetEdgeWeight("b", "c")
+	assert.Equal(t, 1e-3, w)
+}
@@ -2,21 +2,160 @@
 package graph
 
 import (
+	"math"
 	"os"
 	"strconv"
 	"testing"
 
 	"github.com/tommika/gorilla/algorithms/matrix"
 	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/geo"
+	"github.com/tommika/gorilla/must"
 	"github.com/tommika/gorilla/util"
 )
 
 func TestSelfEdge(t *testing.T) {
 	g := NewGraph[string, int](Directed)
-	g.AddEdge("A", "A", 0)
+	assert.Equal(t, EdgeIgnoredSelfLoop, g.AddEdge("A", "A", 0))
 	g.Fprint(os.Stderr)
-	assert.Equal(t, 0, 0)
-	assert.Equal(t, 0, 0)
+	assert.Equal(t, 0, g.NodeCount())
+	assert.Equal(t, 0, g.EdgeCount())
+
+	for _, directed := range []GraphType{Directed, Undirected} {
+		g = NewGraphWithPolicy[string, int](directed, EdgePolicy{SelfLoops: true})
+		assert.True(t, g.Policy().SelfLoops)
+		assert.Equal(t, EdgeAdded, g.AddEdge("A", "A", 1))
+		assert.Equal(t, EdgeAdded, g.AddEdge("A", "B", 1))
+		assert.Equal(t, 2, g.NodeCount())
+		assert.Equal(t, 2, g.EdgeCount())
+		assert.True(t, g.HasEdge("A", "A"))
+		assert.True(t, g.SetEdgeWeight("A", "A", 5))
+		assert.Equal(t, 5, must.BeOk(g.GetEdgeWeight("A", "A")))
+		assert.Equal(t, 1, g.RemoveEdge("A", "A"))
+		assert.Equal(t, 1, g.EdgeCount())
+		assert.Equal(t, EdgeAdded, g.AddEdge("A", "A", 1))
+		assert.True(t, g.RemoveNode("A"))
+		assert.Equal(t, 0, g.EdgeCount())
+		assert.Equal(t, 1, g.NodeCount())
+	}
+}
+
+func TestAddNode(t *testing.T) {
+	g := NewGraph[string, int](Undirected)
+	assert.True(t, g.AddNode("A"))
+	assert.False(t, g.AddNode("A"))
+	assert.True(t, g.HasNode("A"))
+	assert.Equal(t, 1, g.NodeCount())
+	assert.Equal(t, 0, g.EdgeCount())
+	bft := g.BFS("A", 0)
+	assert.Equal(t, 1, bft.NodeCount())
+	assert.Equal(t, EdgeAdded, g.AddEdge("A", "B", 1))
+	assert.Equal(t, 2, g.NodeCount())
+	assert.Equal(t, EdgeIgnoredNaN, EdgeResult(3))
+	assert.Equal(t, "added", EdgeAdded.String())
+	assert.Equal(t, "EdgeResult(42)", EdgeResult(42).String())
+}
+
+func TestSimpleGraph(t *testing.T) {
+	for _, directed := range []GraphType{Directed, Undirected} {
+		g := NewGraphWithPolicy[string, int](directed, EdgePolicy{Simple: true})
+		assert.Equal(t, EdgeAdded, g.AddEdge("A", "B", 1))
+		assert.Equal(t, EdgeReplaced, g.AddEdge("A", "B", 2))
+		assert.Equal(t, 1, g.EdgeCount())
+		assert.Equal(t, 2, must.BeOk(g.GetEdgeWeight("A", "B")))
+		if directed {
+			assert.Equal(t, EdgeAdded, g.AddEdge("B", "A", 3))
+			assert.Equal(t, 2, g.EdgeCount())
+		} else {
+			assert.Equal(t, EdgeReplaced, g.AddEdge("B", "A", 3))
+			assert.Equal(t, 1, g.EdgeCount())
+			assert.Equal(t, 3, must.BeOk(g.GetEdgeWeight("A", "B")))
+		}
+		// both copies of the edge should have been updated
+		w := must.BeOk(g.GetEdgeWeight("A", "B"))
+		assert.Equal(t, 1, len(g.nodes["B"].incoming))
+		assert.Equal(t, w, g.nodes["B"].incoming[0].weight)
+	}
+}
+
+func TestMultigraph(t *testing.T) {
+	g := NewGraph[string, int](Directed)
+	assert.Equal(t, EdgeAdded, g.AddEdge("A", "B", 1))
+	assert.Equal(t, EdgeAdded, g.AddEdge("A", "B", 2))
+	assert.Equal(t, EdgeAdded, g.AddEdge("B", "A", 3))
+	assert.Equal(t, 3, g.EdgeCount())
+	assert.True(t, g.SetEdgeWeight("A", "B", 7))
+	for _, e := range g.nodes["B"].incoming {
+		assert.Equal(t, 7, e.weight)
+	}
+	assert.False(t, g.SetEdgeWeight("A", "C", 7))
+	assert.False(t, g.SetEdgeWeight("C", "A", 7))
+	assert.Equal(t, 2, g.RemoveEdge("A", "B"))
+	assert.Equal(t, 1, g.EdgeCount())
+	assert.False(t, g.HasEdge("A", "B"))
+	assert.True(t, g.HasEdge("B", "A"))
+	assert.Equal(t, 0, g.RemoveEdge("A", "B"))
+	assert.Equal(t, 0, g.RemoveEdge("A", "C"))
+	assert.Equal(t, 2, g.NodeCount())
+}
+
+func TestRemoveEdgeUndirected(t *testing.T) {
+	g := NewGraph[string, int](Undirected)
+	g.AddEdge("A", "B", 1)
+	g.AddEdge("B", "A", 2)
+	g.AddEdge("B", "C", 3)
+	assert.Equal(t, 3, g.EdgeCount())
+	assert.True(t, g.SetEdgeWeight("B", "A", 4))
+	for _, e := range g.outgoing("A") {
+		assert.Equal(t, 4, e.weight)
+	}
+	assert.Equal(t, 2, g.RemoveEdge("A", "B"))
+	assert.Equal(t, 1, g.EdgeCount())
+	assert.False(t, g.HasEdge("A", "B"))
+	assert.False(t, g.HasEdge("B", "A"))
+	assert.True(t, g.HasEdge("C", "B"))
+	assert.Equal(t, 0, len(g.outgoing("A")))
+}
+
+func TestRemoveNode(t *testing.T) {
+	const ams = `0 1 1 0
+	             0 0 1 0
+	             1 0 0 1
+	             1 0 0 0`
+	am := matrix.ParseMatrix(ams, strconv.Atoi)
+	for _, directed := range []GraphType{Directed, Undirected} {
+		g := GraphFromAdjacencyMatrix(am, directed)
+		if directed {
+			assert.Equal(t, 6, g.EdgeCount())
+		} else {
+			// 0-2 and 2-0 are the same edge
+			assert.Equal(t, 5, g.EdgeCount())
+		}
+		assert.False(t, g.RemoveNode(42))
+		assert.True(t, g.RemoveNode(2))
+		assert.False(t, g.HasNode(2))
+		assert.Equal(t, 3, g.NodeCount())
+		assert.Equal(t, 2, g.EdgeCount())
+		assert.False(t, g.HasEdge(0, 2))
+		assert.False(t, g.HasEdge(2, 3))
+		assert.True(t, g.HasEdge(0, 1))
+		assert.True(t, g.HasEdge(3, 0))
+		// the remaining edges should be consistent
+		edges := 0
+		for v := range g.nodes {
+			edges += len(g.nodes[v].outgoing)
+			for _, e := range g.nodes[v].incoming {
+				assert.True(t, g.HasNode(e.node))
+			}
+		}
+		assert.Equal(t, g.EdgeCount(), edges)
+		path, _ := g.FindPath(0, 3)
+		if directed {
+			assert.Nil(t, path)
+		} else {
+			assert.DeepEqual(t, []int{0, 3}, path)
+		}
+	}
 }
 
 func TestGraphFromAdjacencyMatrix(t *testing.T) {
@@ -40,6 +179,49 @@ func TestGraphFromAdjacencyMatrix(t *testing.T) {
 	assert.Equal(t, 3, g2.EdgeCount())
 	assert.Equal(t, 2, len(g2.incoming(0)))
 	assert.Equal(t, 2, len(g2.outgoing(0)))
+
+	// a symmetric matrix adds each undirected edge once
+	const sms = `0 1 0 3
+	             1 0 2 0
+	             0 2 0 0
+	             3 0 0 0`
+	m = matrix.ParseMatrix(sms, strconv.Atoi)
+	g3 := GraphFromAdjacencyMatrix[int](m, Undirected)
+	assert.Equal(t, 3, g3.EdgeCount())
+	assert.Equal(t, 1, len(g3.outgoing(2)))
+	g4 := GraphFromAdjacencyMatrix[int](matrix.CSRFromDense(m), Undirected)
+	assert.Equal(t, 3, g4.EdgeCount())
+}
+
+func TestSparseAdjacencyMatrix(t *testing.T) {
+	const ms = `0 1 0 0
+	            0 0 2 0
+	            3 0 0 0
+	            0 0 0 0`
+	m := matrix.ParseMatrix(ms, strconv.Atoi)
+	for _, directed := range []GraphType{Directed, Undirected} {
+		g := GraphFromAdjacencyMatrix(matrix.CSRFromDense(m), directed)
+		assert.Equal(t, 3, g.NodeCount())
+		assert.Equal(t, 3, g.EdgeCount())
+		assert.True(t, g.HasEdge(2, 0))
+		assert.Equal(t, !directed, g.HasEdge(1, 0))
+
+		s, nodes := g.ToSparseAdjacencyMatrix()
+		dense, denseNodes := g.ToAdjacencyMatrix()
+		assert.DeepEqual(t, denseNodes, nodes)
+		assert.DeepEqual(t, dense, s.ToDense())
+		assert.Equal(t, 3, s.Len())
+	}
+
+	// a large, sparse graph
+	g := BarabasiAlbert(5000, 3, GeneratorOptions[int]{Seed: 1})
+	s, nodes := g.ToSparseAdjacencyMatrix()
+	assert.Equal(t, g.EdgeCount(), s.Len())
+	g2 := GraphFromAdjacencyMatrix(s, Undirected)
+	assert.Equal(t, g.EdgeCount(), g2.EdgeCount())
+	for _, v := range nodes {
+		assert.Equal(t, len(g.outgoing(v)), len(g2.outgoing(v)))
+	}
 }
 
 func TestDirected(t *testing.T) {
@@ -97,3 +279,61 @@ func TestUndirected(t *testing.T) {
 	assert.False(t, g.HasEdge(3, 1))
 
 }
+
+func TestFloatWeights(t *testing.T) {
+	const ms = `0   1.5 NaN 0
+	            0   0   2.25 0
+	            0.5 0   0   -0
+	            0   0   0   0`
+	m := matrix.ParseMatrix(ms, func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	})
+	m.Fprintf(os.Stdout)
+	g := GraphFromAdjacencyMatrix(m, Directed)
+	g.Fprint(os.Stderr)
+	// NaN and (negative) zero cells are not edges
+	assert.Equal(t, 3, g.NodeCount())
+	assert.Equal(t, 3, g.EdgeCount())
+	assert.False(t, g.HasEdge(0, 2))
+	assert.False(t, g.HasEdge(2, 3))
+	w, found := g.GetEdgeWeight(1, 2)
+	assert.True(t, found)
+	assert.Equal(t, 2.25, w)
+
+	path, weight := g.FindPath(0, 2)
+	assert.DeepEqual(t, []int{0, 1, 2}, path)
+	assert.EqualEpsilon(t, 3.75, weight, 1e-9)
+
+	// edges with a NaN weight are ignored
+	g.AddEdge(0, 3, math.NaN())
+	assert.False(t, g.HasEdge(0, 3))
+	assert.Equal(t, 3, g.EdgeCount())
+
+	mT, _ := g.ToAdjacencyMatrix()
+	assert.Equal(t, 1.5, mT.Get(0, 1))
+	assert.Equal(t, 0.0, mT.Get(0, 2))
+}
+
+func TestGeoDistanceWeights(t *testing.T) {
+	// a small trail network, with edges weighted by distance in meters
+	pts := map[string]geo.Point{
+		"Beacon":     {Lat: 41.5048, Lon: -73.9696},
+		"Breakneck":  {Lat: 41.4437, Lon: -73.9782},
+		"ColdSpring": {Lat: 41.4201, Lon: -73.9548},
+		"BullHill":   {Lat: 41.4354, Lon: -73.9483},
+	}
+	g := NewGraph[string, float32](Undirected)
+	link := func(a, b string) {
+		d := geo.HaversineDistance(pts[a].Lat, pts[a].Lon, pts[b].Lat, pts[b].Lon)
+		g.AddEdge(a, b, float32(d))
+	}
+	link("Beacon", "Breakneck")
+	link("Breakneck", "BullHill")
+	link("Breakneck", "ColdSpring")
+	link("BullHill", "ColdSpring")
+	sp, err := g.BellmanFord("Beacon")
+	assert.Nil(t, err)
+	path, weight := sp.FindPath("ColdSpring")
+	assert.DeepEqual(t, []string{"Beacon", "Breakneck", "ColdSpring"}, path)
+	assert.EqualEpsilon(t, 10101.6, float64(weight), 1)
+}
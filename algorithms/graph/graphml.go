@@ -0,0 +1,192 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+// Reading and writing graphs in the GraphML format.
+// See: http://graphml.graphdrawing.org/
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/tommika/gorilla/util"
+	"github.com/tommika/gorilla/xxml"
+)
+
+const (
+	GRAPHML_NS = "http://graphml.graphdrawing.org/xmlns"
+)
+
+var (
+	GRAPHML_NAME = xxml.XmlName(GRAPHML_NS, "graphml")
+)
+
+// WriteGraphML writes the graph in the GraphML format. Node and edge
+// attributes are written as data elements, with a key declared for each
+// distinct attribute name.
+func (g *Graph[N, W]) WriteGraphML(out io.Writer, opts ExportOptions[N, W]) error {
+	w := bufio.NewWriter(out)
+	nodes := g.sortedNodes()
+	// Collect the attributes up-front, since GraphML requires that all keys be
+	// declared before the graph.
+	nodeAttrs := make([]map[string]string, len(nodes))
+	nodeKeys := map[string]string{}
+	for i, v := range nodes {
+		nodeAttrs[i] = opts.nodeAttrs(v)
+		for name := range nodeAttrs[i] {
+			nodeKeys[name] = "string"
+		}
+	}
+	type edgeT struct {
+		from, to N
+		attrs    map[string]string
+	}
+	edges := []edgeT{}
+	edgeKeys := map[string]string{}
+	for _, v := range nodes {
+		for _, e := range g.nodes[v].outgoing {
+			attrs := opts.edgeAttrs(v, e.node, e.weight, weightAttr)
+			edges = append(edges, edgeT{v, e.node, attrs})
+			for name := range attrs {
+				edgeKeys[name] = "string"
+			}
+		}
+	}
+	edgeKeys[weightAttr] = graphmlType[W]()
+
+	fmt.Fprintf(w, "%s", xml.Header)
+	fmt.Fprintf(w, "<graphml xmlns=%q>\n", GRAPHML_NS)
+	writeKeys := func(kind, prefix string, keys map[string]string) {
+		for _, name := range sortedAttrNames(keys) {
+			fmt.Fprintf(w, "  <key id=\"%s\" for=\"%s\" attr.name=\"%s\" attr.type=\"%s\"/>\n",
+				xmlEscape(prefix+name), kind, xmlEscape(name), keys[name])
+		}
+	}
+	writeKeys("node", "n.", nodeKeys)
+	writeKeys("edge", "e.", edgeKeys)
+	edgeDefault := "undirected"
+	if g.directed {
+		edgeDefault = "directed"
+	}
+	fmt.Fprintf(w, "  <graph id=\"%s\" edgedefault=\"%s\">\n", xmlEscape(util.OptionalString(opts.Name, "G")), edgeDefault)
+	writeData := func(prefix string, attrs map[string]string) {
+		for _, name := range sortedAttrNames(attrs) {
+			fmt.Fprintf(w, "      <data key=\"%s\">%s</data>\n", xmlEscape(prefix+name), xmlEscape(attrs[name]))
+		}
+	}
+	for i, v := range nodes {
+		fmt.Fprintf(w, "    <node id=\"%s\">\n", xmlEscape(opts.nodeId(v)))
+		writeData("n.", nodeAttrs[i])
+		fmt.Fprintf(w, "    </node>\n")
+	}
+	for _, e := range edges {
+		fmt.Fprintf(w, "    <edge source=\"%s\" target=\"%s\">\n", xmlEscape(opts.nodeId(e.from)), xmlEscape(opts.nodeId(e.to)))
+		writeData("e.", e.attrs)
+		fmt.Fprintf(w, "    </edge>\n")
+	}
+	fmt.Fprintf(w, "  </graph>\n")
+	fmt.Fprintf(w, "</graphml>\n")
+	return w.Flush()
+}
+
+// graphmlType returns the GraphML attribute type for weights of type W.
+// Unsigned 64-bit weights may not fit a long, so are declared as strings.
+func graphmlType[W Weight]() string {
+	switch any(util.Zero[W]()).(type) {
+	case float32, float64:
+		return "double"
+	case uint, uint64, uintptr:
+		return "string"
+	}
+	return "long"
+}
+
+func xmlEscape(s string) string {
+	sb := strings.Builder{}
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+// Model of a GraphML document, for reading.
+
+type graphmlDoc struct {
+	Key   []graphmlKey
+	Graph func(*graphmlGraph) `x:"graph"`
+}
+
+type graphmlKey struct {
+	Id   string `x:"id,attr"`
+	For  string `x:"for,attr"`
+	Name string `x:"attr.name,attr"`
+}
+
+type graphmlGraph struct {
+	EdgeDefault string           `x:"edgedefault,attr"`
+	Node        []graphmlElement `x:"node"`
+	Edge        []graphmlElement `x:"edge"`
+}
+
+type graphmlElement struct {
+	Id     string        `x:"id,attr"`
+	Source string        `x:"source,attr"`
+	Target string        `x:"target,attr"`
+	Data   []graphmlData `x:"data"`
+}
+
+type graphmlData struct {
+	Key   string `x:"key,attr"`
+	Value string `x:",cdata"`
+}
+
+// ReadGraphML reads a graph in the GraphML format. Only the first graph in
+// the document is read. Data elements are mapped to attributes using the
+// names given by the declared keys. Hyperedges and nested graphs are not
+// supported.
+func ReadGraphML[N comparable, W Weight](in io.Reader, opts ImportOptions[N, W]) (*Graph[N, W], error) {
+	var b *builder[N, W]
+	var err error
+	doc := graphmlDoc{}
+	// map data keys to attribute names
+	attrs := func(data []graphmlData) map[string]string {
+		attrs := map[string]string{}
+		for _, d := range data {
+			name := d.Key
+			if i := slices.IndexFunc(doc.Key, func(k graphmlKey) bool { return k.Id == d.Key }); i >= 0 {
+				name = doc.Key[i].Name
+			}
+			attrs[name] = d.Value
+		}
+		return attrs
+	}
+	doc.Graph = func(graph *graphmlGraph) {
+		if b != nil {
+			// only the first graph is read
+			return
+		}
+		b = newBuilder(&opts, graph.EdgeDefault != "undirected", weightAttr)
+		for _, n := range graph.Node {
+			if err == nil {
+				_, err = b.node(n.Id, attrs(n.Data))
+			}
+		}
+		for _, e := range graph.Edge {
+			if err == nil {
+				err = b.edge(e.Source, e.Target, attrs(e.Data))
+			}
+		}
+	}
+	if errT := xxml.ReadXmlWithRootName(in, &doc, GRAPHML_NAME); errT != nil {
+		return nil, errT
+	}
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		// no graph
+		b = newBuilder(&opts, Directed, weightAttr)
+	}
+	return b.g, nil
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/must"
+)
+
+func TestWriteReadGraphML(t *testing.T) {
+	for _, directed := range []GraphType{Directed, Undirected} {
+		g := NewGraph[string, float64](directed)
+		g.AddEdge("a", "b", 1.5)
+		g.AddEdge("b", "c", -2)
+		g.AddEdge("c", "a&b", 0.25)
+		g.AddNode("lonely")
+		buffer := bytes.Buffer{}
+		err := g.WriteGraphML(&buffer, ExportOptions[string, float64]{
+			NodeAttrs: func(n string) map[string]string {
+				return map[string]string{"label": strings.ToUpper(n)}
+			},
+			EdgeAttrs: func(from, to string, w float64) map[string]string {
+				return map[string]string{"label": from + "<" + to}
+			},
+		})
+		assert.Nil(t, err)
+		t.Logf("\n%s", buffer.String())
+
+		labels := map[string]string{}
+		gT, err := ReadGraphML(&buffer, ImportOptions[string, float64]{
+			NodeAttrs: func(n string, attrs map[string]string) {
+				labels[n] = attrs["label"]
+			},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, directed, gT.Type())
+		assert.Equal(t, g.NodeCount(), gT.NodeCount())
+		assert.Equal(t, g.EdgeCount(), gT.EdgeCount())
+		assert.Equal(t, -2.0, must.BeOk(gT.GetEdgeWeight("b", "c")))
+		assert.Equal(t, 0.25, must.BeOk(gT.GetEdgeWeight("c", "a&b")))
+		assert.Equal(t, "A&B", labels["a&b"])
+		assert.Equal(t, "LONELY", labels["lonely"])
+	}
+}
+
+func TestReadGraphML(t *testing.T) {
+	const doc = `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="d0" for="node" attr.name="color" attr.type="string"/>
+  <key id="d1" for="edge" attr.name="weight" attr.type="int"/>
+  <graph id="G" edgedefault="undirected">
+    <node id="1"><data key="d0">green</data></node>
+    <node id="2"/>
+    <edge source="1" target="2"><data key="d1">7</data></edge>
+    <edge source="2" target="3"/>
+  </graph>
+</graphml>`
+	colors := map[int]string{}
+	g, err := ReadGraphML(strings.NewReader(doc), ImportOptions[int, int]{
+		NodeAttrs: func(n int, attrs map[string]string) {
+			colors[n] = attrs["color"]
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, Undirected, g.Type())
+	assert.Equal(t, 3, g.NodeCount())
+	assert.Equal(t, 2, g.EdgeCount())
+	assert.Equal(t, 7, must.BeOk(g.GetEdgeWeight(2, 1)))
+	assert.Equal(t, 0, must.BeOk(g.GetEdgeWeight(3, 2)))
+	assert.Equal(t, "green", colors[1])
+
+	_, err = ReadGraphML(strings.NewReader(doc), ImportOptions[[2]int, int]{})
+	assert.NotNil(t, err)
+	for _, bad := range []string{">seven<", ">3x<", ">7.5<", ">1 2<"} {
+		_, err = ReadGraphML(strings.NewReader(strings.Replace(doc, ">7<", bad, 1)), ImportOptions[int, int]{})
+		assert.NotNil(t, err)
+	}
+	_, err = ReadGraphML(strings.NewReader(strings.Replace(doc, `"2"/>`, `"7.9"/>`, 1)), ImportOptions[int, int]{})
+	assert.NotNil(t, err)
+	_, err = ReadGraphML(strings.NewReader("<graph/>"), ImportOptions[int, int]{})
+	assert.NotNil(t, err)
+
+	g, err = ReadGraphML(strings.NewReader(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns"/>`), ImportOptions[int, int]{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, g.NodeCount())
+
+	// only the first graph is read
+	second := `<graph id="H" edgedefault="directed"><node id="9"/></graph>`
+	g, err = ReadGraphML(strings.NewReader(strings.Replace(doc, "</graphml>", second+"</graphml>", 1)), ImportOptions[int, int]{})
+	assert.Nil(t, err)
+	assert.Equal(t, Undirected, g.Type())
+	assert.Equal(t, 3, g.NodeCount())
+	assert.False(t, g.HasNode(9))
+}
+
+func TestGraphMLUnsignedWeight(t *testing.T) {
+	g := NewGraph[int, uint64](Directed)
+	g.AddEdge(1, 2, math.MaxUint64)
+	buffer := bytes.Buffer{}
+	assert.Nil(t, g.WriteGraphML(&buffer, ExportOptions[int, uint64]{}))
+	assert.True(t, strings.Contains(buffer.String(), `attr.name="weight" attr.type="string"`))
+	gT, err := ReadGraphML(&buffer, ImportOptions[int, uint64]{})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), must.BeOk(gT.GetEdgeWeight(1, 2)))
+}
+
+func TestBFTreeGraphML(t *testing.T) {
+	g := NewGraph[int, int](Directed)
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(2, 3, 1)
+	bft := g.BFS(2, 0)
+	buffer := bytes.Buffer{}
+	err := bft.WriteGraphML(&buffer, ExportOptions[int, int]{})
+	assert.Nil(t, err)
+	t.Logf("\n%s", buffer.String())
+	assert.True(t, strings.Contains(buffer.String(), `<node id="2">`))
+	assert.True(t, strings.Contains(buffer.String(), `<data key="n.root">true</data>`))
+	assert.False(t, strings.Contains(buffer.String(), `<node id="1">`))
+}
@@ -0,0 +1,175 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+)
+
+// ErrNoHamiltonianPath is returned when a graph has no Hamiltonian path.
+var ErrNoHamiltonianPath = errors.New("graph has no Hamiltonian path")
+
+// HamiltonianPath finds a Hamiltonian path of the graph: a path that visits
+// every node exactly once. Returns ErrNoHamiltonianPath if there is no such
+// path. The search is exact, so takes exponential time in the worst case, and
+// is only suitable for small graphs (of up to a few dozen nodes.) The search
+// backtracks as soon as the unvisited nodes can't all be reached from the end
+// of the path (or, if the graph is undirected, when more than one of them has
+// a single remaining neighbor, as each such node must end the path), and
+// tries the neighbors with the fewest onward neighbors first (Warnsdorff's
+// rule.)
+func (g *Graph[N, W]) HamiltonianPath() ([]N, error) {
+	h := newHamiltonian(g)
+	n := len(h.nodes)
+	if n == 0 {
+		return []N{}, nil
+	}
+	// nodes with fewer neighbors are more likely to be at an end
+	starts := identity(n)
+	slices.SortStableFunc(starts, func(i, j int) int {
+		return cmp.Compare(len(h.adj[i]), len(h.adj[j]))
+	})
+	for _, s := range starts {
+		h.visit(s)
+		if h.extend(s) {
+			path := make([]N, n)
+			for i, v := range h.path {
+				path[i] = h.nodes[v]
+			}
+			return path, nil
+		}
+		h.unvisit(s)
+	}
+	return nil, ErrNoHamiltonianPath
+}
+
+// hamiltonian holds the state of a search for a Hamiltonian path.
+type hamiltonian[N comparable] struct {
+	directed GraphType
+	nodes    []N
+	adj      [][]int // distinct neighbors (successors, if directed)
+	visited  []bool
+	path     []int
+	reached  []bool // used to check reachability
+	stack    []int
+}
+
+func newHamiltonian[N comparable, W Weight](g *Graph[N, W]) *hamiltonian[N] {
+	h := &hamiltonian[N]{directed: g.directed}
+	if g.directed {
+		h.nodes = g.sortedNodes()
+		index := make(map[N]int, len(h.nodes))
+		for i, v := range h.nodes {
+			index[v] = i
+		}
+		h.adj = make([][]int, len(h.nodes))
+		for i, v := range h.nodes {
+			for _, e := range g.nodes[v].outgoing {
+				if j := index[e.node]; j != i {
+					h.adj[i] = append(h.adj[i], j)
+				}
+			}
+			slices.Sort(h.adj[i])
+			h.adj[i] = slices.Compact(h.adj[i])
+		}
+	} else {
+		h.nodes, h.adj = g.neighborIndices()
+	}
+	h.visited = make([]bool, len(h.nodes))
+	h.reached = make([]bool, len(h.nodes))
+	return h
+}
+
+func (h *hamiltonian[N]) visit(v int) {
+	h.visited[v] = true
+	h.path = append(h.path, v)
+}
+
+func (h *hamiltonian[N]) unvisit(v int) {
+	h.visited[v] = false
+	h.path = h.path[:len(h.path)-1]
+}
+
+// extend attempts to extend the path from u (its last node) to the unvisited
+// nodes.
+func (h *hamiltonian[N]) extend(u int) bool {
+	if len(h.path) == len(h.nodes) {
+		return true
+	}
+	if !h.feasible(u) {
+		return false
+	}
+	next := []int{}
+	for _, v := range h.adj[u] {
+		if !h.visited[v] {
+			next = append(next, v)
+		}
+	}
+	slices.SortStableFunc(next, func(a, b int) int {
+		return cmp.Compare(h.onward(a), h.onward(b))
+	})
+	for _, v := range next {
+		h.visit(v)
+		if h.extend(v) {
+			return true
+		}
+		h.unvisit(v)
+	}
+	return false
+}
+
+// onward returns the number of unvisited neighbors of v.
+func (h *hamiltonian[N]) onward(v int) (count int) {
+	for _, w := range h.adj[v] {
+		if !h.visited[w] {
+			count++
+		}
+	}
+	return
+}
+
+// feasible determines if the path might be extended from u to the unvisited
+// nodes: they must all be reachable from u (via unvisited nodes.)
+func (h *hamiltonian[N]) feasible(u int) bool {
+	unvisited := len(h.nodes) - len(h.path)
+	if !h.directed && unvisited > 1 {
+		// an unvisited node with only one neighbor that is unvisited (or u)
+		// can only be at the end of the path
+		ends := 0
+		for v, visited := range h.visited {
+			if visited {
+				continue
+			}
+			available := 0
+			for _, w := range h.adj[v] {
+				if !h.visited[w] || w == u {
+					available++
+				}
+			}
+			if available == 0 {
+				return false
+			}
+			if available == 1 {
+				if ends++; ends > 1 {
+					return false
+				}
+			}
+		}
+	}
+	clear(h.reached)
+	h.stack = append(h.stack[:0], u)
+	reached := 0
+	for len(h.stack) > 0 {
+		v := h.stack[len(h.stack)-1]
+		h.stack = h.stack[:len(h.stack)-1]
+		for _, w := range h.adj[v] {
+			if !h.visited[w] && !h.reached[w] {
+				h.reached[w] = true
+				reached++
+				h.stack = append(h.stack, w)
+			}
+		}
+	}
+	return reached == unvisited
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+// assertHamiltonian asserts that the path visits every node of the graph
+// exactly once, following edges of the graph
+func assertHamiltonian[W Weight](t *testing.T, g *Graph[int, W], path []int) {
+	t.Helper()
+	assert.Equal(t, g.NodeCount(), len(path))
+	seen := map[int]bool{}
+	for i, v := range path {
+		assert.False(t, seen[v])
+		seen[v] = true
+		if i > 0 {
+			assert.True(t, g.HasEdge(path[i-1], v))
+		}
+	}
+}
+
+// petersenGraph creates the Petersen graph, which has a Hamiltonian path, but
+// no Hamiltonian cycle
+func petersenGraph() *Graph[int, int] {
+	g := NewGraph[int, int](Undirected)
+	for i := range 5 {
+		g.AddEdge(i, (i+1)%5, 1)
+		g.AddEdge(i, i+5, 1)
+		g.AddEdge(i+5, (i+2)%5+5, 1)
+	}
+	return g
+}
+
+func TestHamiltonianPath(t *testing.T) {
+	for _, g := range []*Graph[int, int]{
+		petersenGraph(),
+		Grid(6, 6, GeneratorOptions[int]{}),
+		cliques(3, 4),
+		pathGraph(8, Directed),
+		WattsStrogatz(40, 4, 0.2, GeneratorOptions[int]{Seed: 1}),
+	} {
+		path, err := g.HamiltonianPath()
+		assert.Nil(t, err)
+		assertHamiltonian(t, g, path)
+	}
+	path, _ := pathGraph(8, Directed).HamiltonianPath()
+	assert.DeepEqual(t, []int{0, 1, 2, 3, 4, 5, 6, 7}, path)
+
+	// complete bipartite graph with unequal sides
+	g := NewGraph[int, int](Undirected)
+	for i := range 3 {
+		for j := 3; j < 9; j++ {
+			g.AddEdge(i, j, 1)
+		}
+	}
+	_, err := g.HamiltonianPath()
+	assert.Equal(t, ErrNoHamiltonianPath, err)
+
+	for _, g := range []*Graph[int, int]{
+		starGraph(4, Undirected),
+		starGraph(3, Directed),
+	} {
+		_, err = g.HamiltonianPath()
+		assert.Equal(t, ErrNoHamiltonianPath, err)
+	}
+	g = cliques(1, 3)
+	g.AddNode(3)
+	_, err = g.HamiltonianPath()
+	assert.Equal(t, ErrNoHamiltonianPath, err)
+
+	path, err = NewGraph[int, int](Directed).HamiltonianPath()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(path))
+}
@@ -0,0 +1,151 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/tommika/gorilla/util"
+)
+
+// ShortestSimplePaths returns an iterator over the simple (loopless) paths
+// from s to t, in order of increasing weight (and then length), along with the
+// weight of each path. Paths are found lazily using Yen's K shortest paths
+// algorithm, so the first K paths can be found by stopping after K
+// iterations. Each path requires up to |V| runs of Dijkstra's algorithm, so
+// edge weights must not be negative. Paths are sequences of nodes; when there
+// are parallel edges, the lightest is used.
+func (g *Graph[N, W]) ShortestSimplePaths(s, t N) iter.Seq2[[]N, W] {
+	type candidate struct {
+		path   []N
+		weight W
+	}
+	return func(yield func([]N, W) bool) {
+		if !g.HasNode(s) || !g.HasNode(t) {
+			return
+		}
+		path, weight := g.shortestPathAvoiding(s, t, nil, nil)
+		if path == nil {
+			return
+		}
+		found := []candidate{{path, weight}}
+		candidates := []candidate{}
+		isKnown := func(path []N) bool {
+			match := func(c candidate) bool { return slices.Equal(c.path, path) }
+			return slices.ContainsFunc(found, match) || slices.ContainsFunc(candidates, match)
+		}
+		for yield(slices.Clone(path), weight) {
+			// Each node of the previous path (other than t) is a spur node:
+			// find the shortest path from the spur node to t that leaves the
+			// root of the path (up to the spur node) in a new way, and without
+			// revisiting the root.
+			prev := found[len(found)-1].path
+			for i := range len(prev) - 1 {
+				spur, root := prev[i], prev[:i+1]
+				removedArcs := map[Arc[N]]bool{}
+				for _, p := range found {
+					if len(p.path) > i+1 && slices.Equal(p.path[:i+1], root) {
+						removedArcs[Arc[N]{p.path[i], p.path[i+1]}] = true
+					}
+				}
+				removedNodes := map[N]bool{}
+				for _, v := range root[:i] {
+					removedNodes[v] = true
+				}
+				spurPath, spurWeight := g.shortestPathAvoiding(spur, t, removedNodes, removedArcs)
+				if spurPath == nil {
+					continue
+				}
+				path := append(slices.Clone(root[:i]), spurPath...)
+				if !isKnown(path) {
+					candidates = append(candidates, candidate{path, g.pathWeight(root) + spurWeight})
+				}
+			}
+			if len(candidates) == 0 {
+				return
+			}
+			best := 0
+			for i, c := range candidates {
+				if c.weight < candidates[best].weight ||
+					(c.weight == candidates[best].weight && len(c.path) < len(candidates[best].path)) {
+					best = i
+				}
+			}
+			found = append(found, candidates[best])
+			path, weight = candidates[best].path, candidates[best].weight
+			candidates = slices.Delete(candidates, best, best+1)
+		}
+	}
+}
+
+// shortestPathAvoiding finds the shortest path from s to t (using Dijkstra's
+// algorithm) that doesn't visit any of the given nodes, or follow any of the
+// given arcs. Returns a nil path if there is no such path.
+func (g *Graph[N, W]) shortestPathAvoiding(s, t N, nodes map[N]bool, arcs map[Arc[N]]bool) (path []N, weight W) {
+	sp := g.dijkstra(s, func(u N, e edge[N, W]) (W, bool) {
+		return e.weight, !nodes[e.node] && !arcs[Arc[N]{u, e.node}]
+	})
+	return sp.FindPath(t)
+}
+
+// pathWeight returns the weight of the given path, using the lightest of any
+// parallel edges.
+func (g *Graph[N, W]) pathWeight(path []N) (weight W) {
+	for i := 1; i < len(path); i++ {
+		first := true
+		var lightest W
+		for _, e := range g.outgoing(path[i-1]) {
+			if e.node == path[i] && (first || e.weight < lightest) {
+				lightest, first = e.weight, false
+			}
+		}
+		weight += lightest
+	}
+	return
+}
+
+// SimplePaths returns an iterator over the simple (loopless) paths from s to
+// t, along with the weight of each path. Paths are enumerated lazily, using a
+// depth-first search, so are not in any particular order. If maxLength is
+// positive, only paths with at most that many edges are returned; if maxCount
+// is positive, at most that many paths are returned. The number of simple
+// paths can grow exponentially with the size of the graph, so bounds are
+// recommended. Parallel edges result in distinct paths (with the same
+// sequence of nodes.)
+func (g *Graph[N, W]) SimplePaths(s, t N, maxLength, maxCount int) iter.Seq2[[]N, W] {
+	return func(yield func([]N, W) bool) {
+		if !g.HasNode(s) || !g.HasNode(t) {
+			return
+		}
+		path := []N{s}
+		onPath := map[N]bool{s: true}
+		count := 0
+		// visit extends the path from u; returns false once iteration should
+		// stop.
+		var visit func(u N, weight W) bool
+		visit = func(u N, weight W) bool {
+			if u == t {
+				count++
+				return yield(slices.Clone(path), weight) && (maxCount <= 0 || count < maxCount)
+			}
+			if maxLength > 0 && len(path) > maxLength {
+				return true
+			}
+			for _, e := range g.outgoing(u) {
+				if onPath[e.node] {
+					continue
+				}
+				path = append(path, e.node)
+				onPath[e.node] = true
+				more := visit(e.node, weight+e.weight)
+				path = path[:len(path)-1]
+				delete(onPath, e.node)
+				if !more {
+					return false
+				}
+			}
+			return true
+		}
+		visit(s, util.Zero[W]())
+	}
+}
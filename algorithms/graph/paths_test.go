@@ -0,0 +1,131 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+// yenGraph is the example used in the Wikipedia article on Yen's algorithm
+func yenGraph() *Graph[string, int] {
+	g := NewGraph[string, int](Directed)
+	g.AddEdge("C", "D", 3)
+	g.AddEdge("C", "E", 2)
+	g.AddEdge("D", "F", 4)
+	g.AddEdge("E", "D", 1)
+	g.AddEdge("E", "F", 2)
+	g.AddEdge("E", "G", 3)
+	g.AddEdge("F", "G", 2)
+	g.AddEdge("F", "H", 1)
+	g.AddEdge("G", "H", 2)
+	return g
+}
+
+func TestShortestSimplePaths(t *testing.T) {
+	g := yenGraph()
+	paths := []string{}
+	for path, weight := range g.ShortestSimplePaths("C", "H") {
+		paths = append(paths, fmt.Sprint(path, weight))
+		if len(paths) == 3 {
+			break
+		}
+	}
+	assert.DeepEqual(t, []string{"[C E F H] 5", "[C E G H] 7", "[C D F H] 8"}, paths)
+
+	// all of the paths, in order of weight
+	count := 0
+	var last int
+	for path, weight := range g.ShortestSimplePaths("C", "H") {
+		t.Logf("%v %d", path, weight)
+		assert.True(t, weight >= last)
+		assert.Equal(t, g.pathWeight(path), weight)
+		last = weight
+		count++
+	}
+	simple := 0
+	for range g.SimplePaths("C", "H", 0, 0) {
+		simple++
+	}
+	assert.Equal(t, simple, count)
+
+	// undirected, with parallel edges
+	u := NewGraph[int, int](Undirected)
+	u.AddEdge(1, 2, 5)
+	u.AddEdge(1, 2, 1)
+	u.AddEdge(2, 3, 1)
+	u.AddEdge(1, 3, 3)
+	paths = []string{}
+	for path, weight := range u.ShortestSimplePaths(3, 1) {
+		paths = append(paths, fmt.Sprint(path, weight))
+	}
+	assert.DeepEqual(t, []string{"[3 2 1] 2", "[3 1] 3"}, paths)
+
+	for range g.ShortestSimplePaths("H", "C") {
+		t.Fatal("unexpected path")
+	}
+	for range g.ShortestSimplePaths("X", "C") {
+		t.Fatal("unexpected path")
+	}
+	for path, weight := range g.ShortestSimplePaths("C", "C") {
+		assert.DeepEqual(t, []string{"C"}, path)
+		assert.Equal(t, 0, weight)
+	}
+}
+
+func TestSimplePaths(t *testing.T) {
+	g := yenGraph()
+	paths := map[string]int{}
+	for path, weight := range g.SimplePaths("C", "H", 0, 0) {
+		paths[fmt.Sprint(path)] = weight
+	}
+	t.Logf("paths: %v", paths)
+	assert.Equal(t, 7, len(paths))
+	assert.Equal(t, 5, paths["[C E F H]"])
+	assert.Equal(t, 11, paths["[C D F G H]"])
+
+	// bounded by length
+	count := 0
+	for path := range g.SimplePaths("C", "H", 3, 0) {
+		assert.True(t, len(path) <= 4)
+		count++
+	}
+	assert.Equal(t, 3, count)
+	count = 0
+	for range g.SimplePaths("C", "H", 2, 0) {
+		count++
+	}
+	assert.Equal(t, 0, count)
+
+	// bounded by count
+	count = 0
+	for range g.SimplePaths("C", "H", 0, 2) {
+		count++
+	}
+	assert.Equal(t, 2, count)
+
+	// stopping early
+	count = 0
+	for range g.SimplePaths("C", "H", 0, 0) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	assert.Equal(t, 3, count)
+
+	// undirected cycle
+	u := NewGraph[int, int](Undirected)
+	for i := range 5 {
+		u.AddEdge(i, (i+1)%5, 1)
+	}
+	paths = map[string]int{}
+	for path, weight := range u.SimplePaths(0, 2, 0, 0) {
+		paths[fmt.Sprint(path)] = weight
+	}
+	assert.DeepEqual(t, map[string]int{"[0 1 2]": 2, "[0 4 3 2]": 3}, paths)
+	for range u.SimplePaths(0, 9, 0, 0) {
+		t.Fatal("unexpected path")
+	}
+}
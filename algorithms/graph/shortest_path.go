@@ -0,0 +1,161 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"cmp"
+	"errors"
+
+	"github.com/tommika/gorilla/algorithms/heap"
+	"github.com/tommika/gorilla/util"
+)
+
+// ErrNegativeCycle is returned by shortest path algorithms when the graph
+// contains a cycle whose total weight is negative, in which case shortest
+// paths are not well defined.
+var ErrNegativeCycle = errors.New("graph contains a negative-weight cycle")
+
+// ShortestPaths is the result of a single-source shortest path search.
+type ShortestPaths[N comparable, W Weight] struct {
+	source N
+	dist   map[N]W
+	pred   map[N]N
+	cycle  []N
+}
+
+// Source returns the node from which the paths were computed.
+func (sp *ShortestPaths[N, W]) Source() N {
+	return sp.source
+}
+
+// Distance returns the weight of the shortest path from the source to the
+// given node. If the node is not reachable, found will be false.
+func (sp *ShortestPaths[N, W]) Distance(v N) (dist W, found bool) {
+	dist, found = sp.dist[v]
+	return
+}
+
+// FindPath determines if there is a path from the source to the given node.
+// If so, the shortest path and its accumulated weight are returned.
+func (sp *ShortestPaths[N, W]) FindPath(v N) (path []N, weight W) {
+	dist, found := sp.dist[v]
+	if !found || sp.cycle != nil {
+		return
+	}
+	for v != sp.source {
+		path = append(path, v)
+		v = sp.pred[v]
+	}
+	path = append(path, sp.source)
+	return util.ReverseSlice(path), dist
+}
+
+// NegativeCycle returns the nodes of a negative-weight cycle reachable from the
+// source, if one was detected. The first node of the cycle is not repeated at
+// the end.
+func (sp *ShortestPaths[N, W]) NegativeCycle() []N {
+	return sp.cycle
+}
+
+// BellmanFord computes the shortest paths from s to all reachable nodes using
+// the Bellman-Ford algorithm, which (unlike Dijkstra's algorithm) tolerates
+// negative edge weights. If a negative-weight cycle is reachable from s,
+// ErrNegativeCycle is returned and the cycle is available from the result.
+// Runs in O(VE) time.
+func (g *Graph[N, W]) BellmanFord(s N) (sp ShortestPaths[N, W], err error) {
+	sp.source = s
+	sp.dist = map[N]W{}
+	sp.pred = map[N]N{}
+	if !g.HasNode(s) {
+		return
+	}
+	sp.dist[s] = util.Zero[W]()
+	last, relaxed := g.relaxAll(sp.dist, sp.pred)
+	if relaxed {
+		sp.cycle = findCycle(sp.pred, last, len(g.nodes))
+		err = ErrNegativeCycle
+	}
+	return
+}
+
+// relaxAll repeatedly relaxes every edge of the graph, for at most |V| rounds.
+// If edges can still be relaxed in the final round, then there is a
+// negative-weight cycle, and the last node relaxed is returned.
+func (g *Graph[N, W]) relaxAll(dist map[N]W, pred map[N]N) (last N, relaxed bool) {
+	for range len(g.nodes) {
+		relaxed = false
+		for u := range g.nodes {
+			du, found := dist[u]
+			if !found {
+				continue
+			}
+			for _, e := range g.outgoing(u) {
+				if dv, found := dist[e.node]; !found || du+e.weight < dv {
+					dist[e.node] = du + e.weight
+					pred[e.node] = u
+					last, relaxed = e.node, true
+				}
+			}
+		}
+		if !relaxed {
+			break
+		}
+	}
+	return
+}
+
+// findCycle finds the cycle in the predecessor graph that leads to the given
+// node. After |V| steps back along the predecessors we are certain to be on
+// the cycle itself.
+func findCycle[N comparable](pred map[N]N, v N, n int) []N {
+	for range n {
+		v = pred[v]
+	}
+	cycle := []N{v}
+	for u := pred[v]; u != v; u = pred[u] {
+		cycle = append(cycle, u)
+	}
+	return util.ReverseSlice(cycle)
+}
+
+// dijkstra computes the shortest paths from s using Dijkstra's algorithm. All
+// edge weights, as given by the weight function, must be non-negative. Edges
+// for which the weight function returns false are ignored.
+func (g *Graph[N, W]) dijkstra(s N, weight func(u N, e edge[N, W]) (W, bool)) (sp ShortestPaths[N, W]) {
+	type pqItem struct {
+		node N
+		dist W
+	}
+	sp.source = s
+	sp.dist = map[N]W{}
+	sp.pred = map[N]N{}
+	if !g.HasNode(s) {
+		return
+	}
+	done := map[N]bool{}
+	q := heap.NewPriorityQueue(func(a, b pqItem) int {
+		return cmp.Compare(a.dist, b.dist)
+	})
+	sp.dist[s] = util.Zero[W]()
+	q.Enqueue(pqItem{s, sp.dist[s]})
+	for q.Size() > 0 {
+		head := q.MustDequeue()
+		if done[head.node] {
+			// stale entry; node was already reached by a shorter path
+			continue
+		}
+		done[head.node] = true
+		for _, e := range g.outgoing(head.node) {
+			w, ok := weight(head.node, e)
+			if !ok {
+				continue
+			}
+			d := head.dist + w
+			if dv, found := sp.dist[e.node]; !found || d < dv {
+				sp.dist[e.node] = d
+				sp.pred[e.node] = head.node
+				q.Enqueue(pqItem{e.node, d})
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+// negativeEdgeGraph is the example from CLRS (figure 24.4), which has
+// negative edge weights, but no negative cycles.
+func negativeEdgeGraph() *Graph[string, int] {
+	g := NewGraph[string, int](Directed)
+	g.AddEdge("s", "t", 6)
+	g.AddEdge("s", "y", 7)
+	g.AddEdge("t", "x", 5)
+	g.AddEdge("t", "y", 8)
+	g.AddEdge("t", "z", -4)
+	g.AddEdge("x", "t", -2)
+	g.AddEdge("y", "x", -3)
+	g.AddEdge("y", "z", 9)
+	g.AddEdge("z", "s", 2)
+	g.AddEdge("z", "x", 7)
+	return g
+}
+
+func TestBellmanFord(t *testing.T) {
+	g := negativeEdgeGraph()
+	sp, err := g.BellmanFord("s")
+	assert.Nil(t, err)
+	assert.Nil(t, sp.NegativeCycle())
+	assert.Equal(t, "s", sp.Source())
+	expected := map[string]int{"s": 0, "t": 2, "x": 4, "y": 7, "z": -2}
+	for v, d := range expected {
+		dist, found := sp.Distance(v)
+		assert.True(t, found)
+		assert.Equal(t, d, dist)
+	}
+	path, weight := sp.FindPath("z")
+	assert.DeepEqual(t, []string{"s", "y", "x", "t", "z"}, path)
+	assert.Equal(t, -2, weight)
+
+	path, weight = sp.FindPath("bogus")
+	assert.Nil(t, path)
+	assert.Equal(t, 0, weight)
+
+	sp, err = g.BellmanFord("bogus")
+	assert.Nil(t, err)
+	_, found := sp.Distance("s")
+	assert.False(t, found)
+}
+
+func TestBellmanFordNegativeCycle(t *testing.T) {
+	g := NewGraph[string, int](Directed)
+	g.AddEdge("s", "a", 1)
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("b", "c", -3)
+	g.AddEdge("c", "a", 1)
+	g.AddEdge("c", "t", 1)
+	sp, err := g.BellmanFord("s")
+	assert.True(t, errors.Is(err, ErrNegativeCycle))
+	cycle := sp.NegativeCycle()
+	assert.Equal(t, 3, len(cycle))
+	// the cycle should follow edges of the graph, with negative total weight
+	sum := 0
+	for i := range cycle {
+		w, found := g.GetEdgeWeight(cycle[i], cycle[(i+1)%len(cycle)])
+		assert.True(t, found)
+		sum += w
+	}
+	assert.True(t, sum < 0)
+	path, _ := sp.FindPath("t")
+	assert.Nil(t, path)
+
+	// a negative cycle that's not reachable from the source is not a problem
+	_, err = g.BellmanFord("t")
+	assert.Nil(t, err)
+}
+
+func TestBellmanFordUnsigned(t *testing.T) {
+	g := NewGraph[int, uint16](Undirected)
+	g.AddEdge(1, 2, 5)
+	g.AddEdge(2, 3, 5)
+	g.AddEdge(1, 3, 20)
+	sp, err := g.BellmanFord(3)
+	assert.Nil(t, err)
+	path, weight := sp.FindPath(1)
+	assert.DeepEqual(t, []int{3, 2, 1}, path)
+	assert.Equal(t, uint16(10), weight)
+}
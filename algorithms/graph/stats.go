@@ -0,0 +1,321 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+// Structural statistics of a graph. Triangles and clustering coefficients
+// ignore edge direction, self-loops, and parallel edges; distances are
+// measured in hops (i.e., edge weights are ignored.)
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"slices"
+)
+
+// Stats is a summary of the structure of a graph.
+type Stats struct {
+	Directed GraphType
+	Nodes    int
+	Edges    int
+	Density  float64
+	// The minimum, maximum, and average degree (or, if directed, out-degree)
+	MinDegree int
+	MaxDegree int
+	AvgDegree float64
+	// Isolated is the number of nodes without edges.
+	Isolated  int
+	Triangles int
+	// AvgClustering is the average of the local clustering coefficients.
+	AvgClustering float64
+	// Transitivity is the global clustering coefficient.
+	Transitivity float64
+	// Diameter is the longest hop distance between any two (connected)
+	// nodes; it is a lower bound if DiameterExact is false.
+	Diameter      int
+	DiameterExact bool
+}
+
+// Stats computes a summary of the structure of the graph. Finding the exact
+// diameter takes O(VE) time; if samples is positive (and less than the number
+// of nodes) then it is estimated (see Diameter.)
+func (g *Graph[N, W]) Stats(samples int, seed int64) Stats {
+	s := Stats{
+		Directed: g.directed,
+		Nodes:    len(g.nodes),
+		Edges:    g.EdgeCount(),
+		Density:  g.Density(),
+	}
+	if s.Nodes == 0 {
+		s.DiameterExact = true
+		return s
+	}
+	s.MinDegree = s.Edges * 2
+	for _, node := range g.nodes {
+		d := len(node.outgoing) + len(node.incoming)
+		if len(node.outgoing) == 0 && len(node.incoming) == 0 {
+			s.Isolated++
+		}
+		if g.directed {
+			d = len(node.outgoing)
+		}
+		s.MinDegree = min(s.MinDegree, d)
+		s.MaxDegree = max(s.MaxDegree, d)
+	}
+	s.AvgDegree = float64(s.Edges) / float64(s.Nodes)
+	if !g.directed {
+		s.AvgDegree *= 2
+	}
+	tc := g.triangles()
+	s.Triangles = tc.total()
+	s.Transitivity = tc.transitivity()
+	for _, c := range tc.clustering() {
+		s.AvgClustering += c
+	}
+	s.AvgClustering /= float64(s.Nodes)
+	s.Diameter, s.DiameterExact = g.Diameter(samples, seed)
+	return s
+}
+
+// Fprint writes the statistics to the given output stream, one per line.
+func (s *Stats) Fprint(out io.Writer) {
+	graphType := "undirected"
+	if s.Directed {
+		graphType = "directed"
+	}
+	diameter := fmt.Sprintf("%d", s.Diameter)
+	if !s.DiameterExact {
+		diameter = fmt.Sprintf(">= %d (estimated)", s.Diameter)
+	}
+	fmt.Fprintf(out, "type          : %s\n", graphType)
+	fmt.Fprintf(out, "nodes         : %d\n", s.Nodes)
+	fmt.Fprintf(out, "edges         : %d\n", s.Edges)
+	fmt.Fprintf(out, "density       : %.6g\n", s.Density)
+	fmt.Fprintf(out, "degree        : min=%d max=%d avg=%.4f\n", s.MinDegree, s.MaxDegree, s.AvgDegree)
+	fmt.Fprintf(out, "isolated      : %d\n", s.Isolated)
+	fmt.Fprintf(out, "triangles     : %d\n", s.Triangles)
+	fmt.Fprintf(out, "clustering    : %.4f\n", s.AvgClustering)
+	fmt.Fprintf(out, "transitivity  : %.4f\n", s.Transitivity)
+	fmt.Fprintf(out, "diameter      : %s\n", diameter)
+}
+
+// Density returns the ratio of the number of edges to the number of possible
+// edges (between distinct nodes.) Parallel edges and self-loops are counted,
+// so the density of a multigraph may exceed one.
+func (g *Graph[N, W]) Density() float64 {
+	n := float64(len(g.nodes))
+	if n < 2 {
+		return 0
+	}
+	possible := n * (n - 1)
+	if !g.directed {
+		possible /= 2
+	}
+	return float64(g.EdgeCount()) / possible
+}
+
+// DegreeDistribution returns the number of nodes having each degree: element
+// d of the result is the number of nodes with degree d. If the graph is
+// directed, the total degree (in plus out) of each node is used.
+func (g *Graph[N, W]) DegreeDistribution() []int {
+	dist := []int{}
+	for _, node := range g.nodes {
+		d := len(node.outgoing) + len(node.incoming)
+		if d >= len(dist) {
+			dist = append(dist, make([]int, d+1-len(dist))...)
+		}
+		dist[d]++
+	}
+	return dist
+}
+
+// Triangles returns the number of triangles that each node is part of.
+func (g *Graph[N, W]) Triangles() map[N]int {
+	tc := g.triangles()
+	triangles := make(map[N]int, len(tc.nodes))
+	for i, v := range tc.nodes {
+		triangles[v] = tc.count[i]
+	}
+	return triangles
+}
+
+// TriangleCount returns the number of triangles in the graph.
+func (g *Graph[N, W]) TriangleCount() int {
+	tc := g.triangles()
+	return tc.total()
+}
+
+// ClusteringCoefficient returns the local clustering coefficient of each node:
+// the fraction of pairs of the node's neighbors that are themselves
+// neighbors. Nodes with fewer than two neighbors have a coefficient of zero.
+func (g *Graph[N, W]) ClusteringCoefficient() map[N]float64 {
+	tc := g.triangles()
+	coefficients := make(map[N]float64, len(tc.nodes))
+	for i, c := range tc.clustering() {
+		coefficients[tc.nodes[i]] = c
+	}
+	return coefficients
+}
+
+// Transitivity returns the global clustering coefficient of the graph: the
+// fraction of connected triples of nodes (paths of length two) that are
+// closed, forming triangles.
+func (g *Graph[N, W]) Transitivity() float64 {
+	tc := g.triangles()
+	return tc.transitivity()
+}
+
+// triangleCounts holds the number of triangles of each node, along with the
+// number of (distinct) neighbors of each node.
+type triangleCounts[N comparable] struct {
+	nodes  []N
+	count  []int
+	degree []int
+}
+
+// triangles counts the triangles of each node, by orienting each edge from
+// the node of lower degree to the node of higher degree, and then finding the
+// common out-neighbors of the ends of each edge. Each triangle is found once,
+// in O(E^1.5) time overall.
+func (g *Graph[N, W]) triangles() triangleCounts[N] {
+	nodes, adj := g.neighborIndices()
+	tc := triangleCounts[N]{
+		nodes:  nodes,
+		count:  make([]int, len(nodes)),
+		degree: make([]int, len(nodes)),
+	}
+	for i, neighbors := range adj {
+		tc.degree[i] = len(neighbors)
+	}
+	before := func(i, j int) bool {
+		return len(adj[i]) < len(adj[j]) || (len(adj[i]) == len(adj[j]) && i < j)
+	}
+	forward := make([][]int, len(nodes))
+	for i, neighbors := range adj {
+		for _, j := range neighbors {
+			if before(i, j) {
+				forward[i] = append(forward[i], j)
+			}
+		}
+	}
+	marked := make([]bool, len(nodes))
+	for u, out := range forward {
+		for _, v := range out {
+			marked[v] = true
+		}
+		for _, v := range out {
+			for _, w := range forward[v] {
+				if marked[w] {
+					tc.count[u]++
+					tc.count[v]++
+					tc.count[w]++
+				}
+			}
+		}
+		for _, v := range out {
+			marked[v] = false
+		}
+	}
+	return tc
+}
+
+func (tc *triangleCounts[N]) total() int {
+	total := 0
+	for _, c := range tc.count {
+		total += c
+	}
+	return total / 3
+}
+
+func (tc *triangleCounts[N]) clustering() []float64 {
+	coefficients := make([]float64, len(tc.nodes))
+	for i, c := range tc.count {
+		if d := tc.degree[i]; d > 1 {
+			coefficients[i] = float64(2*c) / float64(d*(d-1))
+		}
+	}
+	return coefficients
+}
+
+func (tc *triangleCounts[N]) transitivity() float64 {
+	triples := 0
+	for _, d := range tc.degree {
+		triples += d * (d - 1) / 2
+	}
+	if triples == 0 {
+		return 0
+	}
+	return float64(3*tc.total()) / float64(triples)
+}
+
+// neighborIndices numbers the nodes (in sorted order), and returns the
+// distinct neighbors of each node, ignoring edge direction and self-loops.
+func (g *Graph[N, W]) neighborIndices() (nodes []N, adj [][]int) {
+	nodes = g.sortedNodes()
+	index := make(map[N]int, len(nodes))
+	for i, v := range nodes {
+		index[v] = i
+	}
+	adj = make([][]int, len(nodes))
+	for i, v := range nodes {
+		for _, edges := range g.bothDirections(v) {
+			for _, e := range edges {
+				if j := index[e.node]; j != i {
+					adj[i] = append(adj[i], j)
+				}
+			}
+		}
+		slices.Sort(adj[i])
+		adj[i] = slices.Compact(adj[i])
+	}
+	return
+}
+
+// Eccentricity returns the greatest hop distance from the given node to any
+// node that it can reach.
+func (g *Graph[N, W]) Eccentricity(n N) int {
+	if !g.HasNode(n) {
+		return 0
+	}
+	ecc, _ := farthest(g.hopDistances(n))
+	return ecc
+}
+
+// Diameter returns the greatest eccentricity of any node: i.e., the longest
+// hop distance between two nodes, where one can be reached from the other.
+// Computing the exact diameter requires a search from every node, which takes
+// O(VE) time. If samples is positive (and less than the number of nodes) then
+// the diameter is estimated by searching from a random sample of nodes
+// (chosen using the given seed), along with the farthest node found by each
+// search (the "double sweep" heuristic.) An estimate is a lower bound, and
+// exact will be false.
+func (g *Graph[N, W]) Diameter(samples int, seed int64) (diameter int, exact bool) {
+	sources := g.sortedNodes()
+	exact = samples <= 0 || samples >= len(sources)
+	if !exact {
+		rnd := rand.New(rand.NewSource(seed))
+		rnd.Shuffle(len(sources), func(i, j int) {
+			sources[i], sources[j] = sources[j], sources[i]
+		})
+		sources = sources[:samples]
+	}
+	for _, s := range sources {
+		ecc, far := farthest(g.hopDistances(s))
+		if !exact {
+			ecc = max(ecc, g.Eccentricity(far))
+		}
+		diameter = max(diameter, ecc)
+	}
+	return
+}
+
+// farthest returns the greatest of the given distances, along with a node at
+// that distance (the least such node, when the node type is ordered.)
+func farthest[N comparable](dist map[N]int) (d int, node N) {
+	first := true
+	for v, dv := range dist {
+		if first || dv > d || (dv == d && compareNodes(v, node) < 0) {
+			d, node, first = dv, v, false
+		}
+	}
+	return
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+func TestTriangles(t *testing.T) {
+	g := cliques(1, 4)
+	assert.Equal(t, 4, g.TriangleCount())
+	for v, c := range g.Triangles() {
+		assert.Equal(t, 3, c)
+		assert.Equal(t, 1.0, g.ClusteringCoefficient()[v])
+	}
+	assert.Equal(t, 1.0, g.Transitivity())
+
+	// a triangle, with a pendant edge; direction, self-loops and parallel
+	// edges are ignored
+	g = NewGraph[int, int](Directed)
+	g.AddEdge(0, 1, 1)
+	g.AddEdge(1, 2, 1)
+	g.AddEdge(0, 2, 1)
+	g.AddEdge(2, 0, 1)
+	g.AddEdge(2, 3, 1)
+	g.AddEdge(3, 3, 1)
+	assert.DeepEqual(t, map[int]int{0: 1, 1: 1, 2: 1, 3: 0}, g.Triangles())
+	assert.DeepEqual(t, map[int]float64{0: 1, 1: 1, 2: 1.0 / 3, 3: 0}, g.ClusteringCoefficient())
+	assert.Equal(t, 3.0/5, g.Transitivity())
+
+	assert.Equal(t, 0, pathGraph(10, Undirected).TriangleCount())
+	assert.Equal(t, 0.0, pathGraph(2, Undirected).Transitivity())
+}
+
+func TestTrianglesRandom(t *testing.T) {
+	g := ErdosRenyi(200, 0.1, GeneratorOptions[int]{Seed: 1})
+	expected := map[int]int{}
+	for u := range 200 {
+		for v := u + 1; v < 200; v++ {
+			for w := v + 1; w < 200; w++ {
+				if g.HasEdge(u, v) && g.HasEdge(v, w) && g.HasEdge(u, w) {
+					expected[u]++
+					expected[v]++
+					expected[w]++
+				}
+			}
+		}
+	}
+	total := 0
+	for v, c := range g.Triangles() {
+		assert.Equal(t, expected[v], c)
+		total += c
+	}
+	assert.Equal(t, total/3, g.TriangleCount())
+}
+
+func TestDensity(t *testing.T) {
+	assert.Equal(t, 1.0, cliques(1, 4).Density())
+	assert.Equal(t, 0.2, starGraph(5, Directed).Density())
+	assert.Equal(t, 0.4, starGraph(5, Undirected).Density())
+	assert.Equal(t, 0.0, NewGraph[int, int](Directed).Density())
+}
+
+func TestDegreeDistribution(t *testing.T) {
+	assert.DeepEqual(t, []int{0, 4, 0, 0, 1}, starGraph(5, Undirected).DegreeDistribution())
+	g := starGraph(5, Directed)
+	g.AddNode(5)
+	assert.DeepEqual(t, []int{1, 4, 0, 0, 1}, g.DegreeDistribution())
+	assert.DeepEqual(t, []int{}, NewGraph[int, int](Directed).DegreeDistribution())
+}
+
+func TestDiameter(t *testing.T) {
+	g := pathGraph(5, Undirected)
+	assert.Equal(t, 4, g.Eccentricity(0))
+	assert.Equal(t, 2, g.Eccentricity(2))
+	assert.Equal(t, 0, g.Eccentricity(9))
+	diameter, exact := g.Diameter(0, 0)
+	assert.Equal(t, 4, diameter)
+	assert.True(t, exact)
+
+	g = pathGraph(5, Directed)
+	assert.Equal(t, 0, g.Eccentricity(4))
+	diameter, _ = g.Diameter(0, 0)
+	assert.Equal(t, 4, diameter)
+
+	// in a grid, the double sweep finds a corner, and then the opposite corner
+	g = Grid(10, 10, GeneratorOptions[int]{})
+	diameter, exact = g.Diameter(3, 1)
+	assert.Equal(t, 18, diameter)
+	assert.False(t, exact)
+}
+
+func TestStats(t *testing.T) {
+	g := cliques(2, 4)
+	g.AddNode(8)
+	s := g.Stats(0, 0)
+	assert.Equal(t, 9, s.Nodes)
+	assert.Equal(t, 14, s.Edges)
+	assert.Equal(t, 14.0/36, s.Density)
+	assert.Equal(t, 0, s.MinDegree)
+	assert.Equal(t, 4, s.MaxDegree)
+	assert.Equal(t, 28.0/9, s.AvgDegree)
+	assert.Equal(t, 1, s.Isolated)
+	assert.Equal(t, 8, s.Triangles)
+	// the ends of each bridge have a clustering coefficient of 1/2
+	assert.EqualEpsilon(t, (4*1+4*0.5)/9.0, s.AvgClustering, 1e-12)
+	assert.Equal(t, 3, s.Diameter)
+	assert.True(t, s.DiameterExact)
+
+	out := bytes.Buffer{}
+	s.Fprint(&out)
+	assert.True(t, strings.Contains(out.String(), "triangles     : 8\n"))
+	assert.True(t, strings.Contains(out.String(), "diameter      : 3\n"))
+
+	s = g.Stats(2, 1)
+	assert.False(t, s.DiameterExact)
+	out.Reset()
+	s.Fprint(&out)
+	assert.True(t, strings.Contains(out.String(), "(estimated)"))
+
+	s = NewGraph[string, int](Directed).Stats(0, 0)
+	assert.Equal(t, 0, s.Nodes)
+	assert.True(t, s.DiameterExact)
+}
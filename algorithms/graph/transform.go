@@ -0,0 +1,147 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"fmt"
+)
+
+// Operations that construct new graphs from existing graphs. The resulting
+// graphs have the same type (directed or undirected) and edge policy as the
+// graph on which the operation is invoked.
+
+// Clone returns a copy of the graph.
+func (g *Graph[N, W]) Clone() *Graph[N, W] {
+	c := g.newLike()
+	for v := range g.nodes {
+		c.AddNode(v)
+	}
+	g.visitEdges(func(from, to N, weight W) {
+		c.AddEdge(from, to, weight)
+	})
+	return c
+}
+
+// Transpose returns the transpose of the graph: a graph with the same nodes,
+// and with the direction of every edge reversed. The transpose of an
+// undirected graph is a copy of the graph.
+func (g *Graph[N, W]) Transpose() *Graph[N, W] {
+	t := g.newLike()
+	for v := range g.nodes {
+		t.AddNode(v)
+	}
+	g.visitEdges(func(from, to N, weight W) {
+		t.AddEdge(to, from, weight)
+	})
+	return t
+}
+
+// Subgraph returns the subgraph induced by the given nodes: a graph with those
+// nodes, and every edge of the graph between them. Nodes that aren't in the
+// graph are ignored. Only the edges of the given nodes are examined, so this
+// is fast for a small subgraph of a large graph.
+func (g *Graph[N, W]) Subgraph(nodes []N) *Graph[N, W] {
+	s := g.newLike()
+	for _, v := range nodes {
+		if g.HasNode(v) {
+			s.AddNode(v)
+		}
+	}
+	// As with visitEdges, only outgoing edges are visited, so that each edge
+	// of an undirected graph is visited once.
+	for v := range s.nodes {
+		for _, e := range g.nodes[v].outgoing {
+			if s.HasNode(e.node) {
+				s.AddEdge(v, e.node, e.weight)
+			}
+		}
+	}
+	return s
+}
+
+// EgoNetwork returns the ego network of the given node: the subgraph induced
+// by the nodes within the given number of hops (radius) of the node. As with
+// BFS, a radius of zero means no limit. If the node isn't in the graph, the
+// network is empty.
+func (g *Graph[N, W]) EgoNetwork(center N, radius int) *Graph[N, W] {
+	bft := g.BFS(center, radius)
+	return g.Subgraph(bft.nodes)
+}
+
+// Union returns the union of the graph and another graph of the same type: a
+// graph with the nodes and edges of both. If both graphs have an edge from one
+// node to another, only the edges of this graph are included.
+func (g *Graph[N, W]) Union(other *Graph[N, W]) (*Graph[N, W], error) {
+	if g.directed != other.directed {
+		return nil, fmt.Errorf("can't combine directed and undirected graphs")
+	}
+	u := g.Clone()
+	for v := range other.nodes {
+		u.AddNode(v)
+	}
+	other.visitEdges(func(from, to N, weight W) {
+		if !g.HasEdge(from, to) {
+			u.AddEdge(from, to, weight)
+		}
+	})
+	return u, nil
+}
+
+// Intersection returns the intersection of the graph and another graph of the
+// same type: a graph with the nodes that are in both graphs, and the edges of
+// this graph that connect nodes that are also connected in the other graph.
+func (g *Graph[N, W]) Intersection(other *Graph[N, W]) (*Graph[N, W], error) {
+	if g.directed != other.directed {
+		return nil, fmt.Errorf("can't combine directed and undirected graphs")
+	}
+	i := g.newLike()
+	for v := range g.nodes {
+		if other.HasNode(v) {
+			i.AddNode(v)
+		}
+	}
+	g.visitEdges(func(from, to N, weight W) {
+		if other.HasEdge(from, to) {
+			i.AddEdge(from, to, weight)
+		}
+	})
+	return i, nil
+}
+
+// Complement returns the complement of the graph: a graph with the same nodes,
+// and an edge (with the given weight) between every pair of distinct nodes
+// that aren't connected in the graph. If the graph's policy allows self-loops,
+// the complement also has a self-loop on every node that doesn't have one in
+// the graph. The complement of a sparse graph is dense, so this is only
+// suitable for small graphs.
+func (g *Graph[N, W]) Complement(weight W) *Graph[N, W] {
+	c := g.newLike()
+	nodes := g.sortedNodes()
+	for _, v := range nodes {
+		c.AddNode(v)
+	}
+	for i, u := range nodes {
+		for j, v := range nodes {
+			if (i == j && !g.policy.SelfLoops) || (!g.directed && j < i) {
+				continue
+			}
+			if !g.HasEdge(u, v) {
+				c.AddEdge(u, v, weight)
+			}
+		}
+	}
+	return c
+}
+
+// newLike creates an empty graph of the same type, and with the same policy.
+func (g *Graph[N, W]) newLike() *Graph[N, W] {
+	return NewGraphWithPolicy[N, W](g.directed, g.policy)
+}
+
+// visitEdges visits every edge of the graph once.
+func (g *Graph[N, W]) visitEdges(visit func(from, to N, weight W)) {
+	for v, node := range g.nodes {
+		for _, e := range node.outgoing {
+			visit(v, e.node, e.weight)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package graph
+
+import (
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/must"
+)
+
+func TestClone(t *testing.T) {
+	g := yenGraph()
+	g.AddNode("X")
+	c := g.Clone()
+	assert.Equal(t, g.NodeCount(), c.NodeCount())
+	assert.Equal(t, g.EdgeCount(), c.EdgeCount())
+	m1, nodes1 := g.ToAdjacencyMatrix()
+	m2, nodes2 := c.ToAdjacencyMatrix()
+	assert.DeepEqual(t, nodes1, nodes2)
+	assert.DeepEqual(t, m1, m2)
+	c.AddEdge("X", "C", 1)
+	assert.False(t, g.HasEdge("X", "C"))
+}
+
+func TestTranspose(t *testing.T) {
+	g := yenGraph()
+	tg := g.Transpose()
+	assert.Equal(t, g.EdgeCount(), tg.EdgeCount())
+	assert.True(t, tg.HasEdge("H", "F"))
+	assert.False(t, tg.HasEdge("F", "H"))
+	assert.Equal(t, 4, must.BeOk(tg.GetEdgeWeight("F", "D")))
+	path, weight := tg.Transpose().FindPath("C", "H")
+	assert.Equal(t, 4, len(path))
+	assert.Equal(t, g.pathWeight(path), weight)
+
+	u := cliques(1, 3)
+	assert.Equal(t, 3, u.Transpose().EdgeCount())
+	assert.True(t, u.Transpose().HasEdge(2, 0))
+}
+
+func TestSubgraph(t *testing.T) {
+	g := yenGraph()
+	s := g.Subgraph([]string{"C", "D", "E", "Z"})
+	assert.Equal(t, 3, s.NodeCount())
+	assert.Equal(t, 3, s.EdgeCount())
+	assert.True(t, s.HasEdge("E", "D"))
+	assert.False(t, s.HasNode("Z"))
+	assert.Equal(t, 0, g.Subgraph(nil).NodeCount())
+
+	// each edge of an undirected multigraph is included once, even if a node
+	// is given more than once
+	u := NewGraph[int, int](Undirected)
+	u.AddEdge(1, 2, 1)
+	u.AddEdge(2, 1, 2)
+	u.AddEdge(2, 3, 1)
+	s2 := u.Subgraph([]int{2, 1, 2})
+	assert.Equal(t, 2, s2.NodeCount())
+	assert.Equal(t, 2, s2.EdgeCount())
+	assert.True(t, s2.HasEdge(1, 2))
+	assert.True(t, s2.HasEdge(2, 1))
+}
+
+func TestEgoNetwork(t *testing.T) {
+	g := pathGraph(10, Undirected)
+	e := g.EgoNetwork(5, 2)
+	assert.Equal(t, 5, e.NodeCount())
+	assert.Equal(t, 4, e.EdgeCount())
+	assert.True(t, e.HasNode(3))
+	assert.True(t, e.HasNode(7))
+	assert.False(t, e.HasNode(8))
+	assert.Equal(t, 10, g.EgoNetwork(5, 0).NodeCount())
+	assert.Equal(t, 0, g.EgoNetwork(50, 2).NodeCount())
+
+	// edges between nodes at the edge of the network are included
+	c := cliques(1, 4)
+	c.AddEdge(3, 10, 1)
+	e = c.EgoNetwork(10, 1)
+	assert.Equal(t, 1, e.EdgeCount())
+	e = c.EgoNetwork(10, 2)
+	assert.Equal(t, 7, e.EdgeCount())
+}
+
+func TestUnionIntersection(t *testing.T) {
+	g1 := NewGraph[int, int](Undirected)
+	g1.AddEdge(1, 2, 1)
+	g1.AddEdge(2, 3, 1)
+	g2 := NewGraph[int, int](Undirected)
+	g2.AddEdge(3, 2, 5)
+	g2.AddEdge(3, 4, 5)
+	g2.AddNode(9)
+
+	u, err := g1.Union(g2)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, u.NodeCount())
+	assert.Equal(t, 3, u.EdgeCount())
+	assert.Equal(t, 1, must.BeOk(u.GetEdgeWeight(2, 3)))
+	assert.Equal(t, 5, must.BeOk(u.GetEdgeWeight(4, 3)))
+
+	i, err := g1.Intersection(g2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, i.NodeCount())
+	assert.Equal(t, 1, i.EdgeCount())
+	assert.Equal(t, 1, must.BeOk(i.GetEdgeWeight(3, 2)))
+
+	_, err = g1.Union(NewGraph[int, int](Directed))
+	assert.NotNil(t, err)
+	_, err = g1.Intersection(NewGraph[int, int](Directed))
+	assert.NotNil(t, err)
+}
+
+func TestComplement(t *testing.T) {
+	g := pathGraph(4, Undirected)
+	c := g.Complement(1)
+	assert.Equal(t, 4, c.NodeCount())
+	assert.Equal(t, 3, c.EdgeCount())
+	assert.True(t, c.HasEdge(0, 2))
+	assert.True(t, c.HasEdge(3, 0))
+	assert.False(t, c.HasEdge(1, 2))
+	assert.Equal(t, g.EdgeCount(), c.Complement(1).EdgeCount())
+
+	d := pathGraph(3, Directed)
+	c = d.Complement(7)
+	assert.Equal(t, 4, c.EdgeCount())
+	assert.True(t, c.HasEdge(1, 0))
+	assert.Equal(t, 7, must.BeOk(c.GetEdgeWeight(0, 2)))
+
+	// self-loops, if the policy allows them
+	l := NewGraphWithPolicy[int, int](Undirected, EdgePolicy{SelfLoops: true})
+	l.AddEdge(0, 1, 1)
+	l.AddEdge(1, 1, 1)
+	l.AddNode(2)
+	c = l.Complement(1)
+	assert.Equal(t, 4, c.EdgeCount())
+	assert.True(t, c.HasEdge(0, 0))
+	assert.False(t, c.HasEdge(1, 1))
+	assert.True(t, c.HasEdge(2, 2))
+	assert.Equal(t, l.EdgeCount(), c.Complement(1).EdgeCount())
+}
@@ -0,0 +1,476 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package matrix
+
+// Decompositions of floating-point matrices (LU, QR and Cholesky), and the
+// operations built on them: determinant, inverse, solving linear systems, and
+// least-squares fitting. Right-hand sides are given as matrices, one column
+// per system to solve; use ColumnVector for a single system.
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrNotSquare is returned when an operation requires a square matrix.
+	ErrNotSquare = errors.New("matrix is not square")
+	// ErrSingular is returned when a matrix is singular (or, for least
+	// squares, rank deficient), so a system has no unique solution.
+	ErrSingular = errors.New("matrix is singular")
+	// ErrNotPositiveDefinite is returned when a Cholesky decomposition is
+	// attempted on a matrix that isn't symmetric positive definite.
+	ErrNotPositiveDefinite = errors.New("matrix is not symmetric positive definite")
+)
+
+// ColumnVector returns an n x 1 matrix holding the given values.
+func ColumnVector[T Number](v []T) Matrix[T] {
+	m := NewMatrix[T](len(v), 1, 0)
+	copy(m.data, v)
+	return m
+}
+
+// LU is the LU decomposition, with partial pivoting, of a square matrix A:
+// PA = LU, where P is a permutation matrix, L is unit lower triangular, and U
+// is upper triangular.
+type LU struct {
+	lu       Matrix[float64] // L (below the diagonal) and U
+	pivot    []int           // row i of PA is row pivot[i] of A
+	sign     float64         // the determinant of P
+	singular bool
+}
+
+// NewLU computes the LU decomposition of a square matrix, using Gaussian
+// elimination with partial pivoting, in O(n^3) time. A singular matrix can be
+// decomposed, but can't be used to solve systems.
+func NewLU(a Matrix[float64]) (*LU, error) {
+	if a.numRows != a.numCols {
+		return nil, notSquare(a)
+	}
+	n := a.numRows
+	d := &LU{lu: a.Clone(), pivot: identity(n), sign: 1}
+	lu := d.lu.rows
+	tolerance := tolerance(a)
+	for k := range n {
+		// choose the row with the largest magnitude in column k as the pivot
+		p := k
+		for i := k + 1; i < n; i++ {
+			if math.Abs(lu[i][k]) > math.Abs(lu[p][k]) {
+				p = i
+			}
+		}
+		if p != k {
+			for j := range n {
+				lu[p][j], lu[k][j] = lu[k][j], lu[p][j]
+			}
+			d.pivot[p], d.pivot[k] = d.pivot[k], d.pivot[p]
+			d.sign = -d.sign
+		}
+		if math.Abs(lu[k][k]) <= tolerance {
+			d.singular = true
+			continue
+		}
+		for i := k + 1; i < n; i++ {
+			lu[i][k] /= lu[k][k]
+			f := lu[i][k]
+			for j := k + 1; j < n; j++ {
+				lu[i][j] -= f * lu[k][j]
+			}
+		}
+	}
+	return d, nil
+}
+
+// L returns the unit lower triangular factor.
+func (d *LU) L() Matrix[float64] {
+	n := d.lu.numRows
+	l := NewMatrix(n, n, 0.0)
+	for i := range n {
+		copy(l.rows[i], d.lu.rows[i][:i])
+		l.rows[i][i] = 1
+	}
+	return l
+}
+
+// U returns the upper triangular factor.
+func (d *LU) U() Matrix[float64] {
+	n := d.lu.numRows
+	u := NewMatrix(n, n, 0.0)
+	for i := range n {
+		copy(u.rows[i][i:], d.lu.rows[i][i:])
+	}
+	return u
+}
+
+// P returns the permutation matrix.
+func (d *LU) P() Matrix[float64] {
+	n := d.lu.numRows
+	p := NewMatrix(n, n, 0.0)
+	for i, j := range d.pivot {
+		p.rows[i][j] = 1
+	}
+	return p
+}
+
+// IsSingular determines if the matrix is singular.
+func (d *LU) IsSingular() bool {
+	return d.singular
+}
+
+// Det returns the determinant of the matrix.
+func (d *LU) Det() float64 {
+	det := d.sign
+	for i := range d.lu.numRows {
+		det *= d.lu.rows[i][i]
+	}
+	return det
+}
+
+// Solve solves AX = B for X. Returns ErrSingular if A is singular.
+func (d *LU) Solve(b Matrix[float64]) (Matrix[float64], error) {
+	n := d.lu.numRows
+	if b.numRows != n {
+		return Matrix[float64]{}, mismatch(d.lu, b)
+	}
+	if d.singular {
+		return Matrix[float64]{}, ErrSingular
+	}
+	// X = PB, then forward substitution with L, and back substitution with U
+	x := NewMatrix(n, b.numCols, 0.0)
+	for i, p := range d.pivot {
+		copy(x.rows[i], b.rows[p])
+	}
+	lu := d.lu.rows
+	for k := range n {
+		for i := k + 1; i < n; i++ {
+			axpy(x.rows[i], -lu[i][k], x.rows[k])
+		}
+	}
+	for k := n - 1; k >= 0; k-- {
+		for j := range x.rows[k] {
+			x.rows[k][j] /= lu[k][k]
+		}
+		for i := range k {
+			axpy(x.rows[i], -lu[i][k], x.rows[k])
+		}
+	}
+	return x, nil
+}
+
+// Inverse returns the inverse of the matrix. Returns ErrSingular if it's
+// singular.
+func (d *LU) Inverse() (Matrix[float64], error) {
+	return d.Solve(Identity[float64](d.lu.numRows))
+}
+
+// QR is the QR decomposition of an m x n matrix A, with m >= n: A = QR, where
+// Q is an m x n matrix with orthonormal columns, and R is n x n upper
+// triangular.
+type QR struct {
+	qr    Matrix[float64] // the Householder vectors, and R above the diagonal
+	rdiag []float64       // the diagonal of R
+	zero  float64         // values of R no larger than this are zero
+}
+
+// NewQR computes the QR decomposition of an m x n matrix, with m >= n, using
+// Householder reflections, in O(mn^2) time.
+func NewQR(a Matrix[float64]) (*QR, error) {
+	m, n := a.numRows, a.numCols
+	if m < n {
+		return nil, fmt.Errorf("%w: QR decomposition requires at least as many rows as columns, have %dx%d",
+			ErrDimensionMismatch, m, n)
+	}
+	d := &QR{qr: a.Clone(), rdiag: make([]float64, n), zero: tolerance(a)}
+	qr := d.qr.rows
+	for k := range n {
+		norm := 0.0
+		for i := k; i < m; i++ {
+			norm = math.Hypot(norm, qr[i][k])
+		}
+		if norm != 0 {
+			// reflect column k onto -norm * e_k, choosing the sign that
+			// avoids cancellation
+			if qr[k][k] < 0 {
+				norm = -norm
+			}
+			for i := k; i < m; i++ {
+				qr[i][k] /= norm
+			}
+			qr[k][k]++
+			for j := k + 1; j < n; j++ {
+				s := 0.0
+				for i := k; i < m; i++ {
+					s += qr[i][k] * qr[i][j]
+				}
+				s = -s / qr[k][k]
+				for i := k; i < m; i++ {
+					qr[i][j] += s * qr[i][k]
+				}
+			}
+		}
+		d.rdiag[k] = -norm
+	}
+	return d, nil
+}
+
+// Q returns the m x n factor with orthonormal columns.
+func (d *QR) Q() Matrix[float64] {
+	m, n := d.qr.numRows, d.qr.numCols
+	q := NewMatrix(m, n, 0.0)
+	qr := d.qr.rows
+	for k := n - 1; k >= 0; k-- {
+		q.rows[k][k] = 1
+		for j := k; j < n; j++ {
+			if qr[k][k] == 0 {
+				continue
+			}
+			s := 0.0
+			for i := k; i < m; i++ {
+				s += qr[i][k] * q.rows[i][j]
+			}
+			s = -s / qr[k][k]
+			for i := k; i < m; i++ {
+				q.rows[i][j] += s * qr[i][k]
+			}
+		}
+	}
+	return q
+}
+
+// R returns the n x n upper triangular factor.
+func (d *QR) R() Matrix[float64] {
+	n := d.qr.numCols
+	r := NewMatrix(n, n, 0.0)
+	for i := range n {
+		r.rows[i][i] = d.rdiag[i]
+		copy(r.rows[i][i+1:], d.qr.rows[i][i+1:])
+	}
+	return r
+}
+
+// IsFullRank determines if the columns of the matrix are linearly
+// independent.
+func (d *QR) IsFullRank() bool {
+	for _, r := range d.rdiag {
+		if math.Abs(r) <= d.zero {
+			return false
+		}
+	}
+	return true
+}
+
+// Solve finds the least-squares solution of AX = B: the X that minimizes the
+// 2-norm of AX - B. Returns ErrSingular if A isn't of full rank.
+func (d *QR) Solve(b Matrix[float64]) (Matrix[float64], error) {
+	m, n := d.qr.numRows, d.qr.numCols
+	if b.numRows != m {
+		return Matrix[float64]{}, mismatch(d.qr, b)
+	}
+	if !d.IsFullRank() {
+		return Matrix[float64]{}, ErrSingular
+	}
+	// compute Q'B by applying the reflections, then solve RX = Q'B
+	y := b.Clone()
+	qr := d.qr.rows
+	for k := range n {
+		for j := range b.numCols {
+			s := 0.0
+			for i := k; i < m; i++ {
+				s += qr[i][k] * y.rows[i][j]
+			}
+			s = -s / qr[k][k]
+			for i := k; i < m; i++ {
+				y.rows[i][j] += s * qr[i][k]
+			}
+		}
+	}
+	x := NewMatrix(n, b.numCols, 0.0)
+	for i := range n {
+		copy(x.rows[i], y.rows[i])
+	}
+	for k := n - 1; k >= 0; k-- {
+		for j := range x.rows[k] {
+			x.rows[k][j] /= d.rdiag[k]
+		}
+		for i := range k {
+			axpy(x.rows[i], -qr[i][k], x.rows[k])
+		}
+	}
+	return x, nil
+}
+
+// Cholesky is the Cholesky decomposition of a symmetric positive definite
+// matrix A: A = LL', where L is lower triangular.
+type Cholesky struct {
+	l Matrix[float64]
+}
+
+// NewCholesky computes the Cholesky decomposition of a symmetric positive
+// definite matrix, in O(n^3) time (about half that of LU). Returns
+// ErrNotPositiveDefinite if the matrix isn't symmetric positive definite.
+func NewCholesky(a Matrix[float64]) (*Cholesky, error) {
+	if a.numRows != a.numCols {
+		return nil, notSquare(a)
+	}
+	n := a.numRows
+	tolerance := tolerance(a)
+	l := NewMatrix(n, n, 0.0)
+	for j := range n {
+		d := a.rows[j][j]
+		for k := range j {
+			d -= l.rows[j][k] * l.rows[j][k]
+		}
+		if d <= tolerance {
+			return nil, ErrNotPositiveDefinite
+		}
+		l.rows[j][j] = math.Sqrt(d)
+		for i := j + 1; i < n; i++ {
+			if math.Abs(a.rows[i][j]-a.rows[j][i]) > tolerance {
+				return nil, ErrNotPositiveDefinite
+			}
+			s := a.rows[i][j]
+			for k := range j {
+				s -= l.rows[i][k] * l.rows[j][k]
+			}
+			l.rows[i][j] = s / l.rows[j][j]
+		}
+	}
+	return &Cholesky{l: l}, nil
+}
+
+// L returns the lower triangular factor.
+func (d *Cholesky) L() Matrix[float64] {
+	return d.l.Clone()
+}
+
+// Det returns the determinant of the matrix.
+func (d *Cholesky) Det() float64 {
+	det := 1.0
+	for i := range d.l.numRows {
+		det *= d.l.rows[i][i]
+	}
+	return det * det
+}
+
+// Solve solves AX = B for X.
+func (d *Cholesky) Solve(b Matrix[float64]) (Matrix[float64], error) {
+	n := d.l.numRows
+	if b.numRows != n {
+		return Matrix[float64]{}, mismatch(d.l, b)
+	}
+	// solve LY = B, then L'X = Y
+	x := b.Clone()
+	l := d.l.rows
+	for k := range n {
+		for j := range x.rows[k] {
+			x.rows[k][j] /= l[k][k]
+		}
+		for i := k + 1; i < n; i++ {
+			axpy(x.rows[i], -l[i][k], x.rows[k])
+		}
+	}
+	for k := n - 1; k >= 0; k-- {
+		for j := range x.rows[k] {
+			x.rows[k][j] /= l[k][k]
+		}
+		for i := range k {
+			axpy(x.rows[i], -l[k][i], x.rows[k])
+		}
+	}
+	return x, nil
+}
+
+// Det returns the determinant of a square matrix.
+func Det(a Matrix[float64]) (float64, error) {
+	d, err := NewLU(a)
+	if err != nil {
+		return 0, err
+	}
+	return d.Det(), nil
+}
+
+// Inverse returns the inverse of a square matrix. Returns ErrSingular if it's
+// singular.
+func Inverse(a Matrix[float64]) (Matrix[float64], error) {
+	d, err := NewLU(a)
+	if err != nil {
+		return Matrix[float64]{}, err
+	}
+	return d.Inverse()
+}
+
+// Solve solves AX = B for X, where A is square, using the LU decomposition.
+// Returns ErrSingular if A is singular.
+func Solve(a, b Matrix[float64]) (Matrix[float64], error) {
+	d, err := NewLU(a)
+	if err != nil {
+		return Matrix[float64]{}, err
+	}
+	return d.Solve(b)
+}
+
+// LeastSquares finds the X that minimizes the 2-norm of AX - B, where A is
+// m x n with m >= n, using the QR decomposition. Returns ErrSingular if the
+// columns of A aren't linearly independent.
+func LeastSquares(a, b Matrix[float64]) (Matrix[float64], error) {
+	d, err := NewQR(a)
+	if err != nil {
+		return Matrix[float64]{}, err
+	}
+	return d.Solve(b)
+}
+
+// PolyFit finds the coefficients of the polynomial of the given degree that
+// best fits the points (x[i], y[i]), in the least-squares sense. Returns the
+// coefficients in order of increasing power: y = c[0] + c[1]x + c[2]x^2...
+func PolyFit(x, y []float64, degree int) ([]float64, error) {
+	if len(x) != len(y) {
+		return nil, fmt.Errorf("%w: %d x values and %d y values", ErrDimensionMismatch, len(x), len(y))
+	}
+	// the Vandermonde matrix
+	a := NewMatrix(len(x), degree+1, 0.0)
+	for i, xi := range x {
+		p := 1.0
+		for j := range a.rows[i] {
+			a.rows[i][j] = p
+			p *= xi
+		}
+	}
+	c, err := LeastSquares(a, ColumnVector(y))
+	if err != nil {
+		return nil, err
+	}
+	return c.data, nil
+}
+
+// axpy computes y += alpha * x.
+func axpy(y []float64, alpha float64, x []float64) {
+	if alpha == 0 {
+		return
+	}
+	for i, v := range x {
+		y[i] += alpha * v
+	}
+}
+
+// tolerance returns the magnitude below which values derived from the given
+// matrix are considered to be zero.
+func tolerance(a Matrix[float64]) float64 {
+	norm := 0.0
+	for _, v := range a.data {
+		norm = max(norm, math.Abs(v))
+	}
+	return float64(max(a.numRows, a.numCols)) * norm * 0x1p-52
+}
+
+func identity(n int) []int {
+	p := make([]int, n)
+	for i := range p {
+		p[i] = i
+	}
+	return p
+}
+
+func notSquare[T any](a Matrix[T]) error {
+	return fmt.Errorf("%w: %dx%d", ErrNotSquare, a.numRows, a.numCols)
+}
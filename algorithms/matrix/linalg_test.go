@@ -0,0 +1,192 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package matrix
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/must"
+)
+
+const epsilon = 1e-9
+
+func mul(a, b Matrix[float64]) Matrix[float64] {
+	return must.NotBeAnError(Mul(a, b))
+}
+
+func assertLowerTriangular(t *testing.T, m Matrix[float64]) {
+	t.Helper()
+	for r := range m.NumRows() {
+		for c := r + 1; c < m.NumCols(); c++ {
+			assert.Equal(t, 0.0, m.Get(r, c))
+		}
+	}
+}
+
+func TestLU(t *testing.T) {
+	a := ParseMatrix("0 2 1\n1 1 1\n4 3 -2", parseFloat)
+	d := must.NotBeAnError(NewLU(a))
+	assert.False(t, d.IsSingular())
+	l, u := d.L(), d.U()
+	assertLowerTriangular(t, l)
+	assertLowerTriangular(t, u.Transpose())
+	for i := range 3 {
+		assert.Equal(t, 1.0, l.Get(i, i))
+	}
+	assert.True(t, Equal(mul(d.P(), a), mul(l, u), epsilon))
+	assert.EqualEpsilon(t, 11.0, d.Det(), epsilon)
+
+	b := ParseMatrix("3 1\n3 0\n5 1", parseFloat)
+	x := must.NotBeAnError(d.Solve(b))
+	assert.True(t, Equal(b, mul(a, x), epsilon))
+
+	inv := must.NotBeAnError(d.Inverse())
+	assert.True(t, Equal(Identity[float64](3), mul(a, inv), epsilon))
+	assert.True(t, Equal(Identity[float64](3), mul(inv, a), epsilon))
+
+	_, err := d.Solve(ColumnVector([]float64{1, 2}))
+	assert.True(t, errors.Is(err, ErrDimensionMismatch))
+	_, err = NewLU(NewMatrix(2, 3, 0.0))
+	assert.True(t, errors.Is(err, ErrNotSquare))
+	assert.Equal(t, "matrix is not square: 2x3", err.Error())
+}
+
+func TestSingular(t *testing.T) {
+	a := ParseMatrix("1 2 3\n2 4 6\n1 0 1", parseFloat)
+	d := must.NotBeAnError(NewLU(a))
+	assert.True(t, d.IsSingular())
+	assert.EqualEpsilon(t, 0.0, d.Det(), epsilon)
+	_, err := d.Solve(ColumnVector([]float64{1, 2, 3}))
+	assert.True(t, errors.Is(err, ErrSingular))
+	_, err = Inverse(a)
+	assert.True(t, errors.Is(err, ErrSingular))
+	_, err = Solve(NewMatrix(2, 2, 0.0), ColumnVector([]float64{1, 2}))
+	assert.True(t, errors.Is(err, ErrSingular))
+}
+
+func TestDet(t *testing.T) {
+	assert.Equal(t, 1.0, must.NotBeAnError(Det(Identity[float64](4))))
+	assert.Equal(t, 1.0, must.NotBeAnError(Det(NewMatrix(0, 0, 0.0))))
+	assert.EqualEpsilon(t, -2.0, must.NotBeAnError(Det(ParseMatrix("1 2\n3 4", parseFloat))), epsilon)
+	// swapping rows negates the determinant
+	assert.EqualEpsilon(t, 2.0, must.NotBeAnError(Det(ParseMatrix("3 4\n1 2", parseFloat))), epsilon)
+	// det(AB) = det(A)det(B)
+	a, b := randomMatrix(6, 6, 1), randomMatrix(6, 6, 2)
+	detA, detB := must.NotBeAnError(Det(a)), must.NotBeAnError(Det(b))
+	assert.EqualEpsilon(t, detA*detB, must.NotBeAnError(Det(mul(a, b))), epsilon)
+	_, err := Det(NewMatrix(1, 2, 0.0))
+	assert.True(t, errors.Is(err, ErrNotSquare))
+}
+
+func TestSolve(t *testing.T) {
+	a := randomMatrix(50, 50, 1)
+	b := randomMatrix(50, 3, 2)
+	x := must.NotBeAnError(Solve(a, b))
+	assert.True(t, Equal(b, mul(a, x), 1e-8))
+	inv := must.NotBeAnError(Inverse(a))
+	assert.True(t, Equal(x, mul(inv, b), 1e-8))
+}
+
+func TestQR(t *testing.T) {
+	a := randomMatrix(7, 4, 1)
+	d := must.NotBeAnError(NewQR(a))
+	assert.True(t, d.IsFullRank())
+	q, r := d.Q(), d.R()
+	assert.Equal(t, 7, q.NumRows())
+	assert.Equal(t, 4, q.NumCols())
+	assertLowerTriangular(t, r.Transpose())
+	assert.True(t, Equal(Identity[float64](4), mul(q.Transpose(), q), epsilon))
+	assert.True(t, Equal(a, mul(q, r), epsilon))
+
+	// for a square matrix, the least-squares solution is exact
+	sq := randomMatrix(5, 5, 2)
+	b := randomMatrix(5, 2, 3)
+	x := must.NotBeAnError(must.NotBeAnError(NewQR(sq)).Solve(b))
+	assert.True(t, Equal(b, mul(sq, x), epsilon))
+
+	_, err := NewQR(NewMatrix(2, 3, 0.0))
+	assert.True(t, errors.Is(err, ErrDimensionMismatch))
+	rankDeficient := ParseMatrix("1 2\n2 4\n3 6", parseFloat)
+	d = must.NotBeAnError(NewQR(rankDeficient))
+	assert.False(t, d.IsFullRank())
+	_, err = d.Solve(ColumnVector([]float64{1, 2, 3}))
+	assert.True(t, errors.Is(err, ErrSingular))
+	_, err = d.Solve(ColumnVector([]float64{1, 2}))
+	assert.True(t, errors.Is(err, ErrDimensionMismatch))
+}
+
+func TestLeastSquares(t *testing.T) {
+	// fit a line to points that lie on it exactly
+	a := ParseMatrix("1 0\n1 1\n1 2\n1 3", parseFloat)
+	b := ColumnVector([]float64{1, 3, 5, 7})
+	x := must.NotBeAnError(LeastSquares(a, b))
+	assert.EqualEpsilon(t, 1.0, x.Get(0, 0), epsilon)
+	assert.EqualEpsilon(t, 2.0, x.Get(1, 0), epsilon)
+
+	// the residual of the least-squares solution is orthogonal to the
+	// columns of A (the normal equations: A'(AX - B) = 0)
+	a, b = randomMatrix(30, 3, 1), randomMatrix(30, 1, 2)
+	x = must.NotBeAnError(LeastSquares(a, b))
+	residual := must.NotBeAnError(Sub(mul(a, x), b))
+	assert.True(t, Equal(NewMatrix(3, 1, 0.0), mul(a.Transpose(), residual), epsilon))
+}
+
+func TestPolyFit(t *testing.T) {
+	x := []float64{-2, -1, 0, 1, 2, 3}
+	y := make([]float64, len(x))
+	for i, xi := range x {
+		y[i] = 3 - 2*xi + 0.5*xi*xi
+	}
+	c := must.NotBeAnError(PolyFit(x, y, 2))
+	assert.Equal(t, 3, len(c))
+	for i, expected := range []float64{3, -2, 0.5} {
+		assert.EqualEpsilon(t, expected, c[i], epsilon)
+	}
+	// the best constant is the mean
+	c = must.NotBeAnError(PolyFit(x, y, 0))
+	mean := 0.0
+	for _, yi := range y {
+		mean += yi / float64(len(y))
+	}
+	assert.EqualEpsilon(t, mean, c[0], epsilon)
+
+	_, err := PolyFit(x, y[1:], 1)
+	assert.True(t, errors.Is(err, ErrDimensionMismatch))
+	_, err = PolyFit([]float64{1, 1, 1}, []float64{1, 2, 3}, 1)
+	assert.True(t, errors.Is(err, ErrSingular))
+}
+
+func TestCholesky(t *testing.T) {
+	a := ParseMatrix("4 12 -16\n12 37 -43\n-16 -43 98", parseFloat)
+	d := must.NotBeAnError(NewCholesky(a))
+	l := d.L()
+	assert.True(t, Equal(ParseMatrix("2 0 0\n6 1 0\n-8 5 3", parseFloat), l, epsilon))
+	assert.True(t, Equal(a, mul(l, l.Transpose()), epsilon))
+	assert.EqualEpsilon(t, 36.0, d.Det(), epsilon)
+
+	// A'A + I is symmetric positive definite
+	m := randomMatrix(20, 20, 1)
+	spd := must.NotBeAnError(Add(mul(m.Transpose(), m), Identity[float64](20)))
+	b := randomMatrix(20, 2, 2)
+	x := must.NotBeAnError(must.NotBeAnError(NewCholesky(spd)).Solve(b))
+	assert.True(t, Equal(b, mul(spd, x), 1e-8))
+	assert.True(t, Equal(x, must.NotBeAnError(Solve(spd, b)), 1e-8))
+	assert.EqualEpsilon(t, must.NotBeAnError(Det(spd)),
+		must.NotBeAnError(NewCholesky(spd)).Det(), 1e-6*math.Abs(must.NotBeAnError(Det(spd))))
+
+	for _, s := range []string{
+		"1 2\n2 1",   // indefinite
+		"1 2\n0 5",   // not symmetric
+		"1 0\n0 0",   // singular
+		"-1 0\n0 -1", // negative definite
+	} {
+		_, err := NewCholesky(ParseMatrix(s, parseFloat))
+		assert.True(t, errors.Is(err, ErrNotPositiveDefinite))
+	}
+	_, err := NewCholesky(NewMatrix(2, 1, 1.0))
+	assert.True(t, errors.Is(err, ErrNotSquare))
+	_, err = d.Solve(ColumnVector([]float64{1}))
+	assert.True(t, errors.Is(err, ErrDimensionMismatch))
+}
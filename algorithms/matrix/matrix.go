@@ -32,6 +32,15 @@ func NewMatrix[T any](numRows, numCols int, defVal T) Matrix[T] {
 	return m
 }
 
+// Clone returns a copy of the matrix, which doesn't share its storage.
+func (m Matrix[T]) Clone() Matrix[T] {
+	c := NewMatrix(m.numRows, m.numCols, util.Zero[T]())
+	copy(c.data, m.data)
+	return c
+}
+
+// Rows returns the rows of the matrix as slices, which share its storage. See
+// also Row and AllRows.
 func (m Matrix[T]) Rows() [][]T {
 	return m.rows
 }
@@ -69,7 +78,9 @@ func (m Matrix[T]) Fprintf(out io.Writer) {
 
 type CellParser[T any] func(string) (T, error)
 
-// ParseMatrix is a very tolerant matrix parser.
+// ParseMatrix is a very tolerant matrix parser: cells that can't be parsed are
+// left as the zero value, and short rows are padded with zeros. See ReadMatrix
+// for a strict parser.
 func ParseMatrix[T any](s string, parse CellParser[T]) (m Matrix[T]) {
 	// Split the input on new lines, ensuring that we handle both unix and dos
 	// style line endings
@@ -0,0 +1,246 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package matrix
+
+// Reading and writing matrices in Matrix Market exchange format, as used by
+// the SuiteSparse Matrix Collection (see https://math.nist.gov/MatrixMarket/).
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrMatrixMarket is returned when reading data that isn't valid (or isn't
+// supported) Matrix Market data.
+var ErrMatrixMarket = errors.New("invalid Matrix Market data")
+
+const matrixMarketBanner = "%%MatrixMarket"
+
+// ReadMatrixMarketFile reads a matrix from the given Matrix Market file.
+func ReadMatrixMarketFile[T Number](path string) (*COO[T], error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	return ReadMatrixMarket[T](in)
+}
+
+// ReadMatrixMarket reads a matrix in Matrix Market format. Both coordinate
+// (sparse) and array (dense) formats are supported, with real, integer or
+// pattern values (pattern entries have the value 1), and general, symmetric
+// or skew-symmetric symmetry (the mirrored entries of a symmetric matrix are
+// added to the returned matrix.) Values are converted to T, so real values
+// are truncated if T is an integer type.
+func ReadMatrixMarket[T Number](in io.Reader) (*COO[T], error) {
+	r := &mmReader{scanner: bufio.NewScanner(in)}
+	r.scanner.Buffer(nil, 1024*1024)
+	if !r.next() {
+		return nil, r.errorf("missing header")
+	}
+	header := strings.Fields(strings.ToLower(r.line))
+	if len(header) != 5 || header[0] != strings.ToLower(matrixMarketBanner) || header[1] != "matrix" {
+		return nil, r.errorf("invalid header: %q", r.line)
+	}
+	format, field, symmetry := header[2], header[3], header[4]
+	if format != "coordinate" && format != "array" {
+		return nil, r.errorf("unsupported format: %s", format)
+	}
+	if field != "real" && field != "integer" && field != "pattern" {
+		return nil, r.errorf("unsupported field: %s", field)
+	}
+	if field == "pattern" && format == "array" {
+		return nil, r.errorf("pattern field requires coordinate format")
+	}
+	if symmetry != "general" && symmetry != "symmetric" && symmetry != "skew-symmetric" {
+		return nil, r.errorf("unsupported symmetry: %s", symmetry)
+	}
+	// skip comments and blank lines, to the size line
+	for {
+		if !r.next() {
+			return nil, r.errorf("missing size")
+		}
+		if line := strings.TrimSpace(r.line); line != "" && !strings.HasPrefix(line, "%") {
+			break
+		}
+	}
+	size, err := r.ints()
+	if err != nil {
+		return nil, err
+	}
+	if format == "coordinate" && len(size) != 3 || format == "array" && len(size) != 2 {
+		return nil, r.errorf("invalid size: %q", r.line)
+	}
+	m := NewCOO[T](size[0], size[1])
+	if symmetry != "general" && m.numRows != m.numCols {
+		return nil, r.errorf("%s matrix is not square", symmetry)
+	}
+	add := func(row, col int, v T) {
+		m.Add(row, col, v)
+		if row != col {
+			switch symmetry {
+			case "symmetric":
+				m.Add(col, row, v)
+			case "skew-symmetric":
+				m.Add(col, row, -v)
+			}
+		}
+	}
+	if format == "coordinate" {
+		for range size[2] {
+			row, col, v, err := readCoordinate[T](r, field == "pattern")
+			if err != nil {
+				return nil, err
+			}
+			if row < 1 || row > m.numRows || col < 1 || col > m.numCols {
+				return nil, r.errorf("index [%d,%d] out of range", row, col)
+			}
+			add(row-1, col-1, v)
+		}
+	} else {
+		// values are in column-major order; for a symmetric matrix, only the
+		// lower triangle is given (and for skew-symmetric, without the
+		// diagonal)
+		for col := range m.numCols {
+			first := 0
+			switch symmetry {
+			case "symmetric":
+				first = col
+			case "skew-symmetric":
+				first = col + 1
+			}
+			for row := first; row < m.numRows; row++ {
+				if !r.nextData() {
+					return nil, r.errorf("expected %d values", m.numRows*m.numCols)
+				}
+				v, err := parseValue[T](r, strings.TrimSpace(r.line))
+				if err != nil {
+					return nil, err
+				}
+				if v != 0 {
+					add(row, col, v)
+				}
+			}
+		}
+	}
+	if r.nextData() {
+		return nil, r.errorf("unexpected data: %q", r.line)
+	}
+	return m, r.scanner.Err()
+}
+
+// WriteMatrixMarketFile writes a matrix to the given file, in Matrix Market
+// format.
+func WriteMatrixMarketFile[T Number](path string, m Enumerable[T]) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = WriteMatrixMarket(out, m); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// WriteMatrixMarket writes the non-zero cells of a (dense or sparse) matrix
+// in Matrix Market coordinate format, with general symmetry. Values are
+// written as integers if T is an integer type, and as reals otherwise.
+func WriteMatrixMarket[T Number](out io.Writer, m Enumerable[T]) error {
+	entries := []Entry[T]{}
+	for e := range m.Entries() {
+		if e.Value != 0 {
+			entries = append(entries, e)
+		}
+	}
+	field := "real"
+	if half := 0.5; T(half) == 0 {
+		field = "integer"
+	}
+	w := bufio.NewWriter(out)
+	fmt.Fprintf(w, "%s matrix coordinate %s general\n", matrixMarketBanner, field)
+	fmt.Fprintf(w, "%d %d %d\n", m.NumRows(), m.NumCols(), len(entries))
+	for _, e := range entries {
+		fmt.Fprintf(w, "%d %d %v\n", e.Row+1, e.Col+1, e.Value)
+	}
+	return w.Flush()
+}
+
+// mmReader reads the lines of Matrix Market data.
+type mmReader struct {
+	scanner *bufio.Scanner
+	line    string
+	lineNo  int
+}
+
+func (r *mmReader) next() bool {
+	if !r.scanner.Scan() {
+		return false
+	}
+	r.line = r.scanner.Text()
+	r.lineNo++
+	return true
+}
+
+// nextData advances to the next line that isn't blank.
+func (r *mmReader) nextData() bool {
+	for r.next() {
+		if strings.TrimSpace(r.line) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *mmReader) ints() ([]int, error) {
+	fields := strings.Fields(r.line)
+	values := make([]int, len(fields))
+	for i, f := range fields {
+		v, err := strconv.Atoi(f)
+		if err != nil || v < 0 {
+			return nil, r.errorf("invalid integer: %q", f)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func (r *mmReader) errorf(format string, args ...any) error {
+	if err := r.scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: line %d: %s", ErrMatrixMarket, r.lineNo, fmt.Sprintf(format, args...))
+}
+
+func readCoordinate[T Number](r *mmReader, pattern bool) (row, col int, v T, err error) {
+	if !r.nextData() {
+		return 0, 0, 0, r.errorf("missing entries")
+	}
+	fields := strings.Fields(r.line)
+	if pattern && len(fields) != 2 || !pattern && len(fields) != 3 {
+		return 0, 0, 0, r.errorf("invalid entry: %q", r.line)
+	}
+	if row, err = strconv.Atoi(fields[0]); err != nil {
+		return 0, 0, 0, r.errorf("invalid row: %q", fields[0])
+	}
+	if col, err = strconv.Atoi(fields[1]); err != nil {
+		return 0, 0, 0, r.errorf("invalid column: %q", fields[1])
+	}
+	if pattern {
+		return row, col, 1, nil
+	}
+	v, err = parseValue[T](r, fields[2])
+	return
+}
+
+func parseValue[T Number](r *mmReader, s string) (T, error) {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, r.errorf("invalid value: %q", s)
+	}
+	return T(v), nil
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package matrix
+
+import (
+	"errors"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/must"
+)
+
+func TestReadMatrixMarket(t *testing.T) {
+	const coordinate = `%%MatrixMarket matrix coordinate real general
+% a comment
+
+3 4 4
+1 2 1.5
+2 1 -4
+3 4 7e2
+3 4 1
+`
+	m := must.NotBeAnError(ReadMatrixMarket[float64](strings.NewReader(coordinate)))
+	assert.Equal(t, 4, m.Len())
+	assert.DeepEqual(t, ParseMatrix("0 1.5 0 0\n-4 0 0 0\n0 0 0 701", parseFloat), m.ToDense())
+
+	const symmetric = `%%MatrixMarket matrix coordinate integer symmetric
+3 3 3
+1 1 2
+2 1 3
+3 2 -1
+`
+	s := must.NotBeAnError(ReadMatrixMarket[int](strings.NewReader(symmetric)))
+	assert.DeepEqual(t, ParseMatrix("2 3 0\n3 0 -1\n0 -1 0", strconv.Atoi), s.ToDense())
+
+	const skew = `%%MATRIXMARKET Matrix Coordinate Pattern Skew-Symmetric
+2 2 1
+2 1
+`
+	s = must.NotBeAnError(ReadMatrixMarket[int](strings.NewReader(skew)))
+	assert.DeepEqual(t, ParseMatrix("0 -1\n1 0", strconv.Atoi), s.ToDense())
+
+	// array values are in column-major order
+	const array = `%%MatrixMarket matrix array real general
+2 3
+1
+4
+2
+0
+3
+6
+`
+	a := must.NotBeAnError(ReadMatrixMarket[float64](strings.NewReader(array)))
+	assert.Equal(t, 5, a.Len())
+	assert.DeepEqual(t, ParseMatrix("1 2 3\n4 0 6", parseFloat), a.ToDense())
+
+	const symmetricArray = `%%MatrixMarket matrix array integer symmetric
+2 2
+1
+2
+3
+`
+	s = must.NotBeAnError(ReadMatrixMarket[int](strings.NewReader(symmetricArray)))
+	assert.DeepEqual(t, ParseMatrix("1 2\n2 3", strconv.Atoi), s.ToDense())
+}
+
+func TestReadMatrixMarketErrors(t *testing.T) {
+	for _, tc := range []struct {
+		data, message string
+	}{
+		{"", "line 0: missing header"},
+		{"%%MatrixMarket vector coordinate real general\n", "line 1: invalid header"},
+		{"%%MatrixMarket matrix coordinate complex general\n", "line 1: unsupported field: complex"},
+		{"%%MatrixMarket matrix coordinate real hermitian\n", "line 1: unsupported symmetry: hermitian"},
+		{"%%MatrixMarket matrix array pattern general\n", "line 1: pattern field requires coordinate format"},
+		{"%%MatrixMarket matrix coordinate real general\n% no size\n", "line 2: missing size"},
+		{"%%MatrixMarket matrix coordinate real general\n2 2\n", "line 2: invalid size"},
+		{"%%MatrixMarket matrix coordinate real general\n2 x 1\n", `line 2: invalid integer: "x"`},
+		{"%%MatrixMarket matrix coordinate real symmetric\n2 3 0\n", "line 2: symmetric matrix is not square"},
+		{"%%MatrixMarket matrix coordinate real general\n2 2 2\n1 1 1\n", "line 3: missing entries"},
+		{"%%MatrixMarket matrix coordinate real general\n2 2 1\n1 1\n", `line 3: invalid entry: "1 1"`},
+		{"%%MatrixMarket matrix coordinate real general\n2 2 1\n1 3 1\n", "line 3: index [1,3] out of range"},
+		{"%%MatrixMarket matrix coordinate real general\n2 2 1\n0 1 1\n", "line 3: index [0,1] out of range"},
+		{"%%MatrixMarket matrix coordinate real general\n2 2 1\n1 1 one\n", `line 3: invalid value: "one"`},
+		{"%%MatrixMarket matrix coordinate real general\n2 2 1\n1 1 1\n2 2 2\n", `line 4: unexpected data: "2 2 2"`},
+		{"%%MatrixMarket matrix array real general\n2 1\n1\n", "line 3: expected 2 values"},
+	} {
+		_, err := ReadMatrixMarket[float64](strings.NewReader(tc.data))
+		assert.True(t, errors.Is(err, ErrMatrixMarket))
+		assert.True(t, strings.Contains(err.Error(), tc.message))
+	}
+	_, err := ReadMatrixMarketFile[float64]("no-such-file.mtx")
+	assert.NotNil(t, err)
+}
+
+func TestWriteMatrixMarket(t *testing.T) {
+	d := ParseMatrix("0 1.5 0\n-4 0 0\n0 0 1e-9", parseFloat)
+	var sb strings.Builder
+	assert.Nil(t, WriteMatrixMarket(&sb, d))
+	assert.Equal(t, `%%MatrixMarket matrix coordinate real general
+3 3 3
+1 2 1.5
+2 1 -4
+3 3 1e-09
+`, sb.String())
+	// round trip
+	m := must.NotBeAnError(ReadMatrixMarket[float64](strings.NewReader(sb.String())))
+	assert.DeepEqual(t, d, m.ToDense())
+
+	sb.Reset()
+	assert.Nil(t, WriteMatrixMarket[int](&sb, randomSparse(10, 10, 20, 1).ToCSR()))
+	assert.True(t, strings.HasPrefix(sb.String(), "%%MatrixMarket matrix coordinate integer general\n10 10 "))
+
+	s := randomSparse(50, 30, 200, 1).ToCSR()
+	path := filepath.Join(t.TempDir(), "random.mtx")
+	assert.Nil(t, WriteMatrixMarketFile(path, s))
+	assert.DeepEqual(t, s, must.NotBeAnError(ReadMatrixMarketFile[int](path)).ToCSR())
+}
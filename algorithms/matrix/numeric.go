@@ -0,0 +1,183 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package matrix
+
+// Operations on numeric matrices. Operations return new matrices, leaving
+// their operands unchanged, and return ErrDimensionMismatch if the
+// dimensions of the operands aren't compatible.
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"sync"
+
+	"github.com/tommika/gorilla/util"
+)
+
+// Number is the constraint for the type of the cells of a numeric matrix.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// ErrDimensionMismatch is returned when the dimensions of the operands of an
+// operation aren't compatible.
+var ErrDimensionMismatch = errors.New("dimension mismatch")
+
+// DefaultBlockSize is the block size used by MulBlocked when none is given.
+// Blocks of this size (for three matrices of float64) fit in a typical L2
+// cache.
+const DefaultBlockSize = 64
+
+// Identity returns the n x n identity matrix.
+func Identity[T Number](n int) Matrix[T] {
+	m := NewMatrix[T](n, n, 0)
+	for i := range n {
+		m.Set(i, i, 1)
+	}
+	return m
+}
+
+// Transpose returns the transpose of the matrix: the matrix whose rows are the
+// columns of this matrix.
+func (m Matrix[T]) Transpose() Matrix[T] {
+	t := NewMatrix(m.numCols, m.numRows, util.Zero[T]())
+	for r, row := range m.rows {
+		for c, cell := range row {
+			t.rows[c][r] = cell
+		}
+	}
+	return t
+}
+
+// Add returns the sum of two matrices of the same dimensions.
+func Add[T Number](a, b Matrix[T]) (Matrix[T], error) {
+	return elementwise(a, b, func(x, y T) T { return x + y })
+}
+
+// Sub returns the difference of two matrices of the same dimensions.
+func Sub[T Number](a, b Matrix[T]) (Matrix[T], error) {
+	return elementwise(a, b, func(x, y T) T { return x - y })
+}
+
+func elementwise[T Number](a, b Matrix[T], op func(x, y T) T) (Matrix[T], error) {
+	if a.numRows != b.numRows || a.numCols != b.numCols {
+		return Matrix[T]{}, mismatch(a, b)
+	}
+	m := NewMatrix[T](a.numRows, a.numCols, 0)
+	for i := range m.data {
+		m.data[i] = op(a.data[i], b.data[i])
+	}
+	return m, nil
+}
+
+// Scale returns the matrix with every cell multiplied by the given scalar.
+func Scale[T Number](m Matrix[T], s T) Matrix[T] {
+	scaled := NewMatrix[T](m.numRows, m.numCols, 0)
+	for i, v := range m.data {
+		scaled.data[i] = v * s
+	}
+	return scaled
+}
+
+// Equal determines if two matrices have the same dimensions, and if each pair
+// of corresponding cells differ by no more than epsilon.
+func Equal[T Number](a, b Matrix[T], epsilon float64) bool {
+	if a.numRows != b.numRows || a.numCols != b.numCols {
+		return false
+	}
+	for i, v := range a.data {
+		// compare as float64, to avoid wrapping of unsigned differences
+		if math.Abs(float64(v)-float64(b.data[i])) > epsilon {
+			return false
+		}
+	}
+	return true
+}
+
+// Mul returns the product of an m x n matrix and an n x p matrix, using the
+// naive O(mnp) algorithm. The loops are ordered so that both operands are
+// accessed a row at a time.
+func Mul[T Number](a, b Matrix[T]) (Matrix[T], error) {
+	if a.numCols != b.numRows {
+		return Matrix[T]{}, mismatch(a, b)
+	}
+	c := NewMatrix[T](a.numRows, b.numCols, 0)
+	for i, row := range a.rows {
+		for k, aik := range row {
+			for j, bkj := range b.rows[k] {
+				c.rows[i][j] += aik * bkj
+			}
+		}
+	}
+	return c, nil
+}
+
+// MulBlocked returns the product of an m x n matrix and an n x p matrix, by
+// multiplying blocks (sub-matrices) of the given size, so that the blocks
+// being multiplied stay in cache. This is faster than Mul for large matrices.
+// If blockSize isn't positive, DefaultBlockSize is used.
+func MulBlocked[T Number](a, b Matrix[T], blockSize int) (Matrix[T], error) {
+	return MulParallel(a, b, blockSize, 1)
+}
+
+// MulParallel returns the product of an m x n matrix and an n x p matrix, as
+// MulBlocked does, but with bands of rows of the product computed in parallel
+// by the given number of workers (GOMAXPROCS workers, if workers isn't
+// positive.)
+func MulParallel[T Number](a, b Matrix[T], blockSize, workers int) (Matrix[T], error) {
+	if a.numCols != b.numRows {
+		return Matrix[T]{}, mismatch(a, b)
+	}
+	if blockSize <= 0 {
+		blockSize = DefaultBlockSize
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	c := NewMatrix[T](a.numRows, b.numCols, 0)
+	// each worker computes bands of blockSize rows; bands don't overlap, so
+	// no synchronization is needed
+	bands := make(chan int)
+	wg := sync.WaitGroup{}
+	for range min(workers, (a.numRows+blockSize-1)/blockSize) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i0 := range bands {
+				mulBand(a, b, c, i0, min(i0+blockSize, a.numRows), blockSize)
+			}
+		}()
+	}
+	for i0 := 0; i0 < a.numRows; i0 += blockSize {
+		bands <- i0
+	}
+	close(bands)
+	wg.Wait()
+	return c, nil
+}
+
+// mulBand computes rows [i0,i1) of the product c = a * b, a block at a time.
+func mulBand[T Number](a, b, c Matrix[T], i0, i1, blockSize int) {
+	n, p := a.numCols, b.numCols
+	for k0 := 0; k0 < n; k0 += blockSize {
+		k1 := min(k0+blockSize, n)
+		for j0 := 0; j0 < p; j0 += blockSize {
+			j1 := min(j0+blockSize, p)
+			for i := i0; i < i1; i++ {
+				cRow := c.rows[i][j0:j1]
+				for k, aik := range a.rows[i][k0:k1] {
+					for j, bkj := range b.rows[k0+k][j0:j1] {
+						cRow[j] += aik * bkj
+					}
+				}
+			}
+		}
+	}
+}
+
+func mismatch[T any](a, b Matrix[T]) error {
+	return fmt.Errorf("%w: %dx%d and %dx%d", ErrDimensionMismatch, a.numRows, a.numCols, b.numRows, b.numCols)
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package matrix
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/must"
+)
+
+func parseFloat(s string) (float64, error) {
+	return strconv.ParseFloat(s, 64)
+}
+
+func randomMatrix(rows, cols int, seed int64) Matrix[float64] {
+	rnd := rand.New(rand.NewSource(seed))
+	m := NewMatrix(rows, cols, 0.0)
+	for r := range rows {
+		for c := range cols {
+			m.Set(r, c, rnd.Float64()*2-1)
+		}
+	}
+	return m
+}
+
+func TestIdentity(t *testing.T) {
+	i := Identity[int](3)
+	assert.DeepEqual(t, ParseMatrix("1 0 0\n0 1 0\n0 0 1", strconv.Atoi), i)
+	assert.Equal(t, 0, Identity[float64](0).NumRows())
+}
+
+func TestTranspose(t *testing.T) {
+	m := ParseMatrix("1 2 3\n4 5 6", strconv.Atoi)
+	tm := m.Transpose()
+	assert.Equal(t, 3, tm.NumRows())
+	assert.Equal(t, 2, tm.NumCols())
+	assert.DeepEqual(t, ParseMatrix("1 4\n2 5\n3 6", strconv.Atoi), tm)
+	assert.DeepEqual(t, m, tm.Transpose())
+	s := NewMatrix(1, 2, "x").Transpose()
+	assert.Equal(t, "x", s.Get(1, 0))
+}
+
+func TestAddSub(t *testing.T) {
+	a := ParseMatrix("1 2\n3 4", strconv.Atoi)
+	b := ParseMatrix("10 20\n30 40", strconv.Atoi)
+	sum := must.NotBeAnError(Add(a, b))
+	assert.DeepEqual(t, ParseMatrix("11 22\n33 44", strconv.Atoi), sum)
+	diff := must.NotBeAnError(Sub(sum, b))
+	assert.DeepEqual(t, a, diff)
+	// operands are unchanged
+	assert.Equal(t, 1, a.Get(0, 0))
+
+	_, err := Add(a, a.Transpose().Transpose())
+	assert.Nil(t, err)
+	_, err = Add(a, NewMatrix(2, 3, 0))
+	assert.True(t, errors.Is(err, ErrDimensionMismatch))
+	assert.Equal(t, "dimension mismatch: 2x2 and 2x3", err.Error())
+	_, err = Sub(a, NewMatrix(3, 2, 0))
+	assert.True(t, errors.Is(err, ErrDimensionMismatch))
+}
+
+func TestScale(t *testing.T) {
+	m := ParseMatrix("1 -2\n3 0.5", parseFloat)
+	assert.DeepEqual(t, ParseMatrix("2 -4\n6 1", parseFloat), Scale(m, 2))
+	assert.Equal(t, 1.0, m.Get(0, 0))
+}
+
+func TestEqual(t *testing.T) {
+	a := ParseMatrix("1 2\n3 4", parseFloat)
+	b := ParseMatrix("1 2\n3 4.0001", parseFloat)
+	assert.False(t, Equal(a, b, 0))
+	assert.True(t, Equal(a, b, 1e-3))
+	assert.False(t, Equal(a, a.Transpose(), 0))
+	assert.False(t, Equal(a, NewMatrix(2, 1, 0.0), 1))
+	// unsigned differences don't wrap
+	u1, u2 := NewMatrix[uint](1, 1, 1), NewMatrix[uint](1, 1, 2)
+	assert.False(t, Equal(u1, u2, 0.5))
+	assert.True(t, Equal(u1, u2, 1))
+}
+
+func TestMul(t *testing.T) {
+	a := ParseMatrix("1 2 3\n4 5 6", strconv.Atoi)
+	b := ParseMatrix("7 8\n9 10\n11 12", strconv.Atoi)
+	expected := ParseMatrix("58 64\n139 154", strconv.Atoi)
+	assert.DeepEqual(t, expected, must.NotBeAnError(Mul(a, b)))
+	assert.DeepEqual(t, expected, must.NotBeAnError(MulBlocked(a, b, 1)))
+	assert.DeepEqual(t, expected, must.NotBeAnError(MulParallel(a, b, 1, 3)))
+	assert.DeepEqual(t, a, must.NotBeAnError(Mul(Identity[int](2), a)))
+	assert.DeepEqual(t, a, must.NotBeAnError(Mul(a, Identity[int](3))))
+
+	_, err := Mul(a, a)
+	assert.True(t, errors.Is(err, ErrDimensionMismatch))
+	_, err = MulBlocked(a, a, 0)
+	assert.True(t, errors.Is(err, ErrDimensionMismatch))
+	_, err = MulParallel(a, a, 0, 0)
+	assert.True(t, errors.Is(err, ErrDimensionMismatch))
+
+	empty := must.NotBeAnError(MulParallel(NewMatrix(0, 3, 0), b, 0, 0))
+	assert.Equal(t, 0, empty.NumRows())
+	assert.Equal(t, 2, empty.NumCols())
+}
+
+func TestMulLarge(t *testing.T) {
+	// dimensions that aren't multiples of the block size
+	a, b := randomMatrix(150, 97, 1), randomMatrix(97, 131, 2)
+	expected := must.NotBeAnError(Mul(a, b))
+	for _, blockSize := range []int{0, 7, 32, 200} {
+		assert.True(t, Equal(expected, must.NotBeAnError(MulBlocked(a, b, blockSize)), 1e-9))
+		for _, workers := range []int{0, 1, 4} {
+			assert.True(t, Equal(expected, must.NotBeAnError(MulParallel(a, b, blockSize, workers)), 1e-9))
+		}
+	}
+	// (AB)' = B'A'
+	assert.True(t, Equal(expected.Transpose(), must.NotBeAnError(Mul(b.Transpose(), a.Transpose())), 1e-9))
+}
+
+const benchSize = 512
+
+func BenchmarkMul(b *testing.B) {
+	m1, m2 := randomMatrix(benchSize, benchSize, 1), randomMatrix(benchSize, benchSize, 2)
+	b.ResetTimer()
+	for range b.N {
+		Mul(m1, m2)
+	}
+}
+
+func BenchmarkMulBlocked(b *testing.B) {
+	m1, m2 := randomMatrix(benchSize, benchSize, 1), randomMatrix(benchSize, benchSize, 2)
+	b.ResetTimer()
+	for range b.N {
+		MulBlocked(m1, m2, 0)
+	}
+}
+
+func BenchmarkMulParallel(b *testing.B) {
+	m1, m2 := randomMatrix(benchSize, benchSize, 1), randomMatrix(benchSize, benchSize, 2)
+	b.ResetTimer()
+	for range b.N {
+		MulParallel(m1, m2, 0, 0)
+	}
+}
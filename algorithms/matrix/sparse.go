@@ -0,0 +1,261 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package matrix
+
+// Sparse matrices, which store only their non-zero cells: COO (coordinate
+// list) format, which is suited to constructing a matrix, and CSR (compressed
+// sparse row) format, which is suited to computing with it.
+
+import (
+	"cmp"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// Entry is a cell of a matrix, with its position.
+type Entry[T any] struct {
+	Row, Col int
+	Value    T
+}
+
+// Enumerable is implemented by both dense and sparse matrices: a matrix whose
+// entries can be enumerated. Sparse matrices enumerate only the cells they
+// store, and dense matrices enumerate every cell.
+type Enumerable[T any] interface {
+	NumRows() int
+	NumCols() int
+	Entries() iter.Seq[Entry[T]]
+}
+
+// Entries returns the cells of the matrix, in row-major order.
+func (m Matrix[T]) Entries() iter.Seq[Entry[T]] {
+	return func(yield func(Entry[T]) bool) {
+		for r, row := range m.rows {
+			for c, v := range row {
+				if !yield(Entry[T]{r, c, v}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// COO is a sparse matrix in coordinate list format: a list of entries, in the
+// order they were added. An entry may be added more than once, in which case
+// its values are summed.
+type COO[T Number] struct {
+	numRows int
+	numCols int
+	entries []Entry[T]
+}
+
+// NewCOO creates an empty sparse matrix of the given dimensions.
+func NewCOO[T Number](numRows, numCols int) *COO[T] {
+	return &COO[T]{numRows: numRows, numCols: numCols}
+}
+
+// COOFromDense creates a sparse matrix holding the non-zero cells of the
+// given matrix.
+func COOFromDense[T Number](m Matrix[T]) *COO[T] {
+	s := NewCOO[T](m.numRows, m.numCols)
+	for e := range m.Entries() {
+		if e.Value != 0 {
+			s.entries = append(s.entries, e)
+		}
+	}
+	return s
+}
+
+func (m *COO[T]) NumRows() int {
+	return m.numRows
+}
+
+func (m *COO[T]) NumCols() int {
+	return m.numCols
+}
+
+// Len returns the number of entries.
+func (m *COO[T]) Len() int {
+	return len(m.entries)
+}
+
+// Add adds an entry to the matrix. Panics if the position is out of range.
+func (m *COO[T]) Add(row, col int, val T) {
+	checkRange(row, col, m.numRows, m.numCols)
+	m.entries = append(m.entries, Entry[T]{row, col, val})
+}
+
+// Entries returns the entries of the matrix, in the order they were added.
+func (m *COO[T]) Entries() iter.Seq[Entry[T]] {
+	return slices.Values(m.entries)
+}
+
+// ToCSR converts the matrix to CSR format, summing the values of duplicate
+// entries, and dropping entries that are zero.
+func (m *COO[T]) ToCSR() *CSR[T] {
+	entries := slices.Clone(m.entries)
+	slices.SortStableFunc(entries, func(a, b Entry[T]) int {
+		if c := cmp.Compare(a.Row, b.Row); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Col, b.Col)
+	})
+	// combine duplicates in place
+	n := 0
+	for i, e := range entries {
+		if i > 0 && e.Row == entries[n-1].Row && e.Col == entries[n-1].Col {
+			entries[n-1].Value += e.Value
+		} else {
+			entries[n] = e
+			n++
+		}
+	}
+	entries = slices.DeleteFunc(entries[:n], func(e Entry[T]) bool { return e.Value == 0 })
+	s := &CSR[T]{
+		numRows: m.numRows,
+		numCols: m.numCols,
+		offsets: make([]int, m.numRows+1),
+		cols:    make([]int, len(entries)),
+		values:  make([]T, len(entries)),
+	}
+	for i, e := range entries {
+		s.offsets[e.Row+1]++
+		s.cols[i], s.values[i] = e.Col, e.Value
+	}
+	for r := range m.numRows {
+		s.offsets[r+1] += s.offsets[r]
+	}
+	return s
+}
+
+// ToDense converts the matrix to a dense matrix.
+func (m *COO[T]) ToDense() Matrix[T] {
+	return toDense[T](m)
+}
+
+// CSR is a sparse matrix in compressed sparse row format: the column indices
+// and values of the non-zero cells, in row-major order, with the cells of row
+// r at offsets[r] through offsets[r+1]-1. CSR matrices are immutable.
+type CSR[T Number] struct {
+	numRows int
+	numCols int
+	offsets []int
+	cols    []int
+	values  []T
+}
+
+// CSRFromDense creates a sparse matrix holding the non-zero cells of the
+// given matrix.
+func CSRFromDense[T Number](m Matrix[T]) *CSR[T] {
+	return COOFromDense(m).ToCSR()
+}
+
+func (m *CSR[T]) NumRows() int {
+	return m.numRows
+}
+
+func (m *CSR[T]) NumCols() int {
+	return m.numCols
+}
+
+// Len returns the number of non-zero cells.
+func (m *CSR[T]) Len() int {
+	return len(m.values)
+}
+
+// Get returns the value of a cell, in O(log k) time for a row with k
+// non-zero cells. Panics if the position is out of range.
+func (m *CSR[T]) Get(row, col int) T {
+	checkRange(row, col, m.numRows, m.numCols)
+	lo, hi := m.offsets[row], m.offsets[row+1]
+	if i, found := slices.BinarySearch(m.cols[lo:hi], col); found {
+		return m.values[lo+i]
+	}
+	return 0
+}
+
+// Entries returns the non-zero cells of the matrix, in row-major order.
+func (m *CSR[T]) Entries() iter.Seq[Entry[T]] {
+	return func(yield func(Entry[T]) bool) {
+		for r := range m.numRows {
+			for i := m.offsets[r]; i < m.offsets[r+1]; i++ {
+				if !yield(Entry[T]{r, m.cols[i], m.values[i]}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Row returns the non-zero cells of the given row: their column indices and
+// values. The returned slices must not be modified.
+func (m *CSR[T]) Row(row int) (cols []int, values []T) {
+	lo, hi := m.offsets[row], m.offsets[row+1]
+	return m.cols[lo:hi], m.values[lo:hi]
+}
+
+// MulVec returns the product of the matrix and the vector x, in time
+// proportional to the number of non-zero cells.
+func (m *CSR[T]) MulVec(x []T) ([]T, error) {
+	if len(x) != m.numCols {
+		return nil, fmt.Errorf("%w: %dx%d and %d", ErrDimensionMismatch, m.numRows, m.numCols, len(x))
+	}
+	y := make([]T, m.numRows)
+	for r := range y {
+		for i := m.offsets[r]; i < m.offsets[r+1]; i++ {
+			y[r] += m.values[i] * x[m.cols[i]]
+		}
+	}
+	return y, nil
+}
+
+// Transpose returns the transpose of the matrix.
+func (m *CSR[T]) Transpose() *CSR[T] {
+	t := &CSR[T]{
+		numRows: m.numCols,
+		numCols: m.numRows,
+		offsets: make([]int, m.numCols+1),
+		cols:    make([]int, len(m.cols)),
+		values:  make([]T, len(m.values)),
+	}
+	for _, c := range m.cols {
+		t.offsets[c+1]++
+	}
+	for c := range m.numCols {
+		t.offsets[c+1] += t.offsets[c]
+	}
+	// visiting the rows in order keeps the columns of the transpose sorted
+	next := slices.Clone(t.offsets[:m.numCols])
+	for e := range m.Entries() {
+		i := next[e.Col]
+		next[e.Col]++
+		t.cols[i], t.values[i] = e.Row, e.Value
+	}
+	return t
+}
+
+// ToCOO converts the matrix to COO format.
+func (m *CSR[T]) ToCOO() *COO[T] {
+	s := NewCOO[T](m.numRows, m.numCols)
+	s.entries = slices.Collect(m.Entries())
+	return s
+}
+
+// ToDense converts the matrix to a dense matrix.
+func (m *CSR[T]) ToDense() Matrix[T] {
+	return toDense[T](m)
+}
+
+func toDense[T Number](m Enumerable[T]) Matrix[T] {
+	d := NewMatrix[T](m.NumRows(), m.NumCols(), 0)
+	for e := range m.Entries() {
+		d.rows[e.Row][e.Col] += e.Value
+	}
+	return d
+}
+
+func checkRange(row, col, numRows, numCols int) {
+	if row < 0 || row >= numRows || col < 0 || col >= numCols {
+		panic(fmt.Sprintf("matrix: index [%d,%d] out of range for %dx%d matrix", row, col, numRows, numCols))
+	}
+}
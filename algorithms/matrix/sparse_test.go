@@ -0,0 +1,121 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package matrix
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/must"
+)
+
+func randomSparse(rows, cols, nnz int, seed int64) *COO[int] {
+	rnd := rand.New(rand.NewSource(seed))
+	m := NewCOO[int](rows, cols)
+	for range nnz {
+		m.Add(rnd.Intn(rows), rnd.Intn(cols), rnd.Intn(19)-9)
+	}
+	return m
+}
+
+func assertPanics(t *testing.T, f func()) {
+	t.Helper()
+	defer func() {
+		assert.NotNil(t, recover())
+	}()
+	f()
+}
+
+func TestCOO(t *testing.T) {
+	m := NewCOO[int](3, 4)
+	assert.Equal(t, 3, m.NumRows())
+	assert.Equal(t, 4, m.NumCols())
+	m.Add(2, 3, 5)
+	m.Add(0, 1, 1)
+	m.Add(2, 3, 2)
+	m.Add(1, 0, 4)
+	m.Add(1, 2, 0)
+	m.Add(0, 0, 3)
+	m.Add(0, 0, -3)
+	assert.Equal(t, 7, m.Len())
+	expected := ParseMatrix("0 1 0 0\n4 0 0 0\n0 0 0 7", strconv.Atoi)
+	assert.DeepEqual(t, expected, m.ToDense())
+
+	s := m.ToCSR()
+	// duplicates are summed, and zeros dropped
+	assert.Equal(t, 3, s.Len())
+	assert.DeepEqual(t, expected, s.ToDense())
+	assert.DeepEqual(t, []Entry[int]{{0, 1, 1}, {1, 0, 4}, {2, 3, 7}}, slices.Collect(s.Entries()))
+	assert.Equal(t, 7, s.Get(2, 3))
+	assert.Equal(t, 0, s.Get(2, 2))
+	cols, values := s.Row(2)
+	assert.DeepEqual(t, []int{3}, cols)
+	assert.DeepEqual(t, []int{7}, values)
+	cols, _ = s.Row(1)
+	assert.DeepEqual(t, []int{0}, cols)
+
+	assert.DeepEqual(t, expected, s.ToCOO().ToDense())
+	assert.Equal(t, 3, s.ToCOO().Len())
+
+	assertPanics(t, func() { m.Add(3, 0, 1) })
+	assertPanics(t, func() { m.Add(0, -1, 1) })
+	assertPanics(t, func() { s.Get(0, 4) })
+}
+
+func TestDenseConversion(t *testing.T) {
+	d := ParseMatrix("0 0 1\n2 0 0\n0 0 0\n0 3 4", parseFloat)
+	s := CSRFromDense(d)
+	assert.Equal(t, 4, s.Len())
+	assert.DeepEqual(t, d, s.ToDense())
+	assert.DeepEqual(t, d, COOFromDense(d).ToDense())
+	assert.Equal(t, 4, COOFromDense(d).Len())
+	// a dense matrix enumerates every cell
+	assert.Equal(t, 12, len(slices.Collect(d.Entries())))
+
+	empty := CSRFromDense(NewMatrix(0, 0, 0.0))
+	assert.Equal(t, 0, empty.Len())
+	assert.Equal(t, 0, empty.ToDense().NumRows())
+}
+
+func TestSparseTranspose(t *testing.T) {
+	m := randomSparse(30, 20, 100, 1)
+	s := m.ToCSR()
+	st := s.Transpose()
+	assert.Equal(t, 20, st.NumRows())
+	assert.Equal(t, 30, st.NumCols())
+	assert.DeepEqual(t, m.ToDense().Transpose(), st.ToDense())
+	assert.DeepEqual(t, s, st.Transpose())
+}
+
+func TestMulVec(t *testing.T) {
+	m := randomSparse(40, 25, 200, 1)
+	s := m.ToCSR()
+	d := m.ToDense()
+	x := make([]int, 25)
+	for i := range x {
+		x[i] = i - 12
+	}
+	y := must.NotBeAnError(s.MulVec(x))
+	expected := must.NotBeAnError(Mul(d, ColumnVector(x)))
+	for r, v := range y {
+		assert.Equal(t, expected.Get(r, 0), v)
+	}
+	_, err := s.MulVec(x[1:])
+	assert.True(t, errors.Is(err, ErrDimensionMismatch))
+}
+
+func BenchmarkMulVec(b *testing.B) {
+	const n = 100000
+	s := randomSparse(n, n, 10*n, 1).ToCSR()
+	x := make([]int, n)
+	for i := range x {
+		x[i] = 1
+	}
+	b.ResetTimer()
+	for range b.N {
+		s.MulVec(x)
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package matrix
+
+// Strict reading, and writing, of matrices as text: either cells separated by
+// whitespace (as accepted by ParseMatrix), or delimited text such as CSV or
+// TSV, optionally with a header naming the columns. Unlike ParseMatrix,
+// ReadMatrix reports cells that can't be parsed, and (by default) rows that
+// don't have the same number of cells.
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/tommika/gorilla/util"
+)
+
+// ErrRaggedRow is returned when reading a row with a different number of
+// cells than the rows before it (or the header.)
+var ErrRaggedRow = errors.New("ragged row")
+
+// ErrUnwritableCell is returned when writing a cell that can't be read back;
+// e.g., an empty cell, or a cell containing whitespace, when cells are
+// separated by whitespace.
+var ErrUnwritableCell = errors.New("cell can't be written")
+
+// RaggedPolicy determines how ReadMatrix handles rows with differing numbers
+// of cells.
+type RaggedPolicy int
+
+const (
+	// RejectRagged rejects rows that don't have the same number of cells as
+	// the first row (or the header), with ErrRaggedRow.
+	RejectRagged RaggedPolicy = iota
+	// PadRagged pads rows with fewer cells than the widest row with the zero
+	// value, as ParseMatrix does.
+	PadRagged
+)
+
+// TextFormat describes how a matrix is read from (and written to) text. The
+// zero value is cells separated by whitespace, without a header, rejecting
+// ragged rows.
+type TextFormat struct {
+	// Separator between cells; e.g. ',' or '\t'. If zero, cells are separated
+	// by any amount of whitespace.
+	Separator rune
+	// Header indicates that the first row is a header, naming the columns.
+	Header bool
+	// Comment, if not zero, is the character that begins a comment line.
+	// Comment lines are ignored when reading.
+	Comment rune
+	// Ragged determines how rows with differing numbers of cells are read.
+	Ragged RaggedPolicy
+}
+
+// CSV is the format of comma-separated values, with a header.
+var CSV = TextFormat{Separator: ',', Header: true}
+
+// TSV is the format of tab-separated values, with a header.
+var TSV = TextFormat{Separator: '\t', Header: true}
+
+// ParseError is returned by ReadMatrix for input that can't be parsed. It
+// gives the position of the row, and cell (if any), at fault.
+type ParseError struct {
+	Line   int // line number of the row, starting at 1
+	Column int // column of the cell, starting at 1; zero if not a cell
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if e.Column == 0 {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// CellFormatter converts the value of a cell to text.
+type CellFormatter[T any] func(T) string
+
+// ReadMatrix reads a matrix in the given format, parsing each cell with the
+// given parser. Blank lines are skipped. If the format has a header, the
+// names of the columns are returned. Returns a ParseError if a cell can't be
+// parsed, or (depending on the ragged row policy) a row has the wrong number
+// of cells.
+func ReadMatrix[T any](in io.Reader, parse CellParser[T], format TextFormat) (m Matrix[T], header []string, err error) {
+	var rows []textRow
+	if format.Separator == 0 {
+		rows, err = readFields(in, format.Comment)
+	} else {
+		rows, err = readDelimited(in, format.Separator, format.Comment)
+	}
+	if err != nil {
+		return
+	}
+	if format.Header && len(rows) > 0 {
+		header = rows[0].cells
+		for i, name := range header {
+			header[i] = strings.TrimSpace(name)
+		}
+		rows = rows[1:]
+	}
+	numCols := len(header)
+	for i, row := range rows {
+		switch {
+		case format.Ragged == PadRagged:
+			numCols = max(numCols, len(row.cells))
+		case i == 0 && header == nil:
+			numCols = len(row.cells)
+		case len(row.cells) != numCols:
+			err = &ParseError{row.line, 0, fmt.Errorf("%w: expected %d cells; got %d", ErrRaggedRow, numCols, len(row.cells))}
+			return
+		}
+	}
+	m = NewMatrix(len(rows), numCols, util.Zero[T]())
+	for r, row := range rows {
+		for c, cell := range row.cells {
+			v, perr := parse(strings.TrimSpace(cell))
+			if perr != nil {
+				err = &ParseError{row.line, c + 1, fmt.Errorf("invalid cell %q: %w", cell, perr)}
+				return
+			}
+			m.rows[r][c] = v
+		}
+	}
+	return
+}
+
+// WriteMatrix writes a matrix in the given format, formatting each cell with
+// the given formatter (or fmt.Sprint, if nil); this is the inverse of
+// ReadMatrix. If the format has a header, the header (which must have a name
+// for each column) is written first. Delimited cells are quoted as needed.
+// Returns ErrUnwritableCell if a cell can't be written so that it can be read
+// back.
+func WriteMatrix[T any](out io.Writer, m Matrix[T], header []string, format CellFormatter[T], tf TextFormat) error {
+	if format == nil {
+		format = func(v T) string { return fmt.Sprint(v) }
+	}
+	if tf.Header && len(header) != m.numCols {
+		return fmt.Errorf("%w: %d columns and %d header names", ErrDimensionMismatch, m.numCols, len(header))
+	}
+	var writeRecord func(record []string) error
+	var flush func() error
+	if tf.Separator == 0 {
+		w := bufio.NewWriter(out)
+		writeRecord = func(record []string) error {
+			for _, cell := range record {
+				if cell == "" || strings.ContainsFunc(cell, unicode.IsSpace) {
+					return fmt.Errorf("%w: %q", ErrUnwritableCell, cell)
+				}
+			}
+			_, err := fmt.Fprintln(w, strings.Join(record, " "))
+			return err
+		}
+		flush = w.Flush
+	} else {
+		w := csv.NewWriter(out)
+		w.Comma = tf.Separator
+		writeRecord = func(record []string) error {
+			for _, cell := range record {
+				// cells are trimmed when read
+				if cell != strings.TrimSpace(cell) {
+					return fmt.Errorf("%w: %q", ErrUnwritableCell, cell)
+				}
+			}
+			if len(record) == 1 && record[0] == "" {
+				// an empty line is skipped when read
+				return fmt.Errorf("%w: %q", ErrUnwritableCell, "")
+			}
+			return w.Write(record)
+		}
+		flush = func() error {
+			w.Flush()
+			return w.Error()
+		}
+	}
+	write := func(record []string) error {
+		if len(record) > 0 && tf.Comment != 0 && strings.HasPrefix(record[0], string(tf.Comment)) {
+			// the line would be read as a comment
+			return fmt.Errorf("%w: %q", ErrUnwritableCell, record[0])
+		}
+		return writeRecord(record)
+	}
+	if tf.Header {
+		if err := write(header); err != nil {
+			return err
+		}
+	}
+	record := make([]string, m.numCols)
+	for _, row := range m.rows {
+		for c, cell := range row {
+			record[c] = format(cell)
+		}
+		if err := write(record); err != nil {
+			return err
+		}
+	}
+	return flush()
+}
+
+// textRow is a row of cells read from text, and its line number.
+type textRow struct {
+	line  int
+	cells []string
+}
+
+func readFields(in io.Reader, comment rune) (rows []textRow, err error) {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || comment != 0 && strings.HasPrefix(text, string(comment)) {
+			continue
+		}
+		rows = append(rows, textRow{line, strings.Fields(text)})
+	}
+	return rows, scanner.Err()
+}
+
+func readDelimited(in io.Reader, separator, comment rune) (rows []textRow, err error) {
+	r := csv.NewReader(in)
+	r.Comma = separator
+	r.Comment = comment
+	r.FieldsPerRecord = -1 // checked by the caller
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			var pe *csv.ParseError
+			if errors.As(err, &pe) {
+				err = &ParseError{pe.Line, 0, pe.Err}
+			}
+			return nil, err
+		}
+		line, _ := r.FieldPos(0)
+		rows = append(rows, textRow{line, record})
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package matrix
+
+import (
+	"encoding/csv"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+func identityString(s string) (string, error) {
+	return s, nil
+}
+
+func assertParseError(t *testing.T, err error, line, column int) *ParseError {
+	t.Helper()
+	var pe *ParseError
+	assert.True(t, errors.As(err, &pe))
+	assert.Equal(t, line, pe.Line)
+	assert.Equal(t, column, pe.Column)
+	return pe
+}
+
+func TestReadMatrix(t *testing.T) {
+	const s = `1 2 3
+
+# a comment
+4 5 6
+`
+	m, header, err := ReadMatrix(strings.NewReader(s), strconv.Atoi, TextFormat{Comment: '#'})
+	assert.Nil(t, err)
+	assert.Nil(t, header)
+	assert.DeepEqual(t, ParseMatrix("1 2 3\n4 5 6", strconv.Atoi), m)
+
+	// the tolerant parser ignores the bad cell; the strict one doesn't
+	const bad = "1 2 3\n4 x 6"
+	assert.DeepEqual(t, ParseMatrix("1 2 3\n4 0 6", strconv.Atoi), ParseMatrix(bad, strconv.Atoi))
+	_, _, err = ReadMatrix(strings.NewReader(bad), strconv.Atoi, TextFormat{})
+	pe := assertParseError(t, err, 2, 2)
+	assert.True(t, errors.Is(err, strconv.ErrSyntax))
+	assert.Equal(t, `line 2, column 2: invalid cell "x": strconv.Atoi: parsing "x": invalid syntax`, pe.Error())
+
+	m, _, err = ReadMatrix(strings.NewReader(""), strconv.Atoi, TextFormat{})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, m.NumRows())
+}
+
+func TestRaggedRows(t *testing.T) {
+	const ragged = "1 2 3\n\n4 5\n6"
+	_, _, err := ReadMatrix(strings.NewReader(ragged), strconv.Atoi, TextFormat{})
+	pe := assertParseError(t, err, 3, 0)
+	assert.True(t, errors.Is(err, ErrRaggedRow))
+	assert.Equal(t, "line 3: ragged row: expected 3 cells; got 2", pe.Error())
+
+	m, _, err := ReadMatrix(strings.NewReader(ragged), strconv.Atoi, TextFormat{Ragged: PadRagged})
+	assert.Nil(t, err)
+	assert.DeepEqual(t, ParseMatrix(ragged, strconv.Atoi), m)
+	assert.DeepEqual(t, ParseMatrix("1 2 3\n4 5 0\n6 0 0", strconv.Atoi), m)
+
+	// rows are padded to the widest row, even if it's wider than the header
+	m, header, err := ReadMatrix(strings.NewReader("a,b\n1\n2,3,4"), strconv.Atoi, TextFormat{Separator: ',', Header: true, Ragged: PadRagged})
+	assert.Nil(t, err)
+	assert.DeepEqual(t, []string{"a", "b"}, header)
+	assert.DeepEqual(t, ParseMatrix("1 0 0\n2 3 4", strconv.Atoi), m)
+
+	// rows must match the header
+	_, _, err = ReadMatrix(strings.NewReader("a,b\n1,2,3"), strconv.Atoi, CSV)
+	assertParseError(t, err, 2, 0)
+	assert.True(t, errors.Is(err, ErrRaggedRow))
+}
+
+func TestReadCSV(t *testing.T) {
+	const s = `name, count ,"weight"
+"Smith, J.", 12, 0.5
+# not a comment,1,2
+`
+	m, header, err := ReadMatrix(strings.NewReader(s), identityString, CSV)
+	assert.Nil(t, err)
+	assert.DeepEqual(t, []string{"name", "count", "weight"}, header)
+	assert.Equal(t, 2, m.NumRows())
+	assert.DeepEqual(t, []string{"Smith, J.", "12", "0.5"}, m.Rows()[0])
+	assert.DeepEqual(t, []string{"# not a comment"}, m.Rows()[1][:1])
+
+	const tsv = "x\ty\n1.5\t-2\n\n3\t4e1\n"
+	f, header, err := ReadMatrix(strings.NewReader(tsv), parseFloat, TSV)
+	assert.Nil(t, err)
+	assert.DeepEqual(t, []string{"x", "y"}, header)
+	assert.DeepEqual(t, ParseMatrix("1.5 -2\n3 40", parseFloat), f)
+
+	_, _, err = ReadMatrix(strings.NewReader("x,y\n1,2\n3,\n"), strconv.Atoi, CSV)
+	assertParseError(t, err, 3, 2)
+	_, _, err = ReadMatrix(strings.NewReader("x,y\n1,2\n3,\"4\n"), strconv.Atoi, CSV)
+	assertParseError(t, err, 3, 0)
+	assert.True(t, errors.Is(err, csv.ErrQuote))
+}
+
+func TestWriteMatrix(t *testing.T) {
+	m := ParseMatrix("1.5 -2 0\n3 40 1e-09", parseFloat)
+	header := []string{"a", "b c", "d"}
+	for _, tf := range []TextFormat{{}, CSV, TSV, {Separator: ';', Comment: '#'}} {
+		var sb strings.Builder
+		assert.Nil(t, WriteMatrix(&sb, m, header, nil, tf))
+		read, readHeader, err := ReadMatrix(strings.NewReader(sb.String()), parseFloat, tf)
+		assert.Nil(t, err)
+		assert.DeepEqual(t, m, read)
+		if tf.Header {
+			assert.DeepEqual(t, header, readHeader)
+		}
+	}
+	var sb strings.Builder
+	assert.Nil(t, WriteMatrix(&sb, m, nil, nil, TextFormat{}))
+	assert.Equal(t, "1.5 -2 0\n3 40 1e-09\n", sb.String())
+	sb.Reset()
+	assert.Nil(t, WriteMatrix(&sb, m, header, nil, TSV))
+	assert.Equal(t, "a\tb c\td\n1.5\t-2\t0\n3\t40\t1e-09\n", sb.String())
+
+	// strings that need quoting
+	s := ParseMatrix("a b\nc d", identityString)
+	s.Set(0, 0, "Smith, J.")
+	s.Set(1, 1, `say "hi"`)
+	sb.Reset()
+	assert.Nil(t, WriteMatrix(&sb, s, []string{"name", "greeting"}, nil, CSV))
+	assert.Equal(t, "name,greeting\n\"Smith, J.\",b\nc,\"say \"\"hi\"\"\"\n", sb.String())
+	read, _, err := ReadMatrix(strings.NewReader(sb.String()), identityString, CSV)
+	assert.Nil(t, err)
+	assert.DeepEqual(t, s, read)
+
+	// with a formatter
+	sb.Reset()
+	i := ParseMatrix("1 2\n3 4", strconv.Atoi)
+	assert.Nil(t, WriteMatrix(&sb, i, nil, func(v int) string { return strconv.Itoa(v * 10) }, TextFormat{}))
+	assert.Equal(t, "10 20\n30 40\n", sb.String())
+}
+
+func TestWriteMatrixErrors(t *testing.T) {
+	var sb strings.Builder
+	m := ParseMatrix("a b\nc d", identityString)
+	err := WriteMatrix(&sb, m, []string{"x"}, nil, CSV)
+	assert.True(t, errors.Is(err, ErrDimensionMismatch))
+
+	for _, tc := range []struct {
+		cell string
+		tf   TextFormat
+	}{
+		{"b c", TextFormat{}},
+		{"", TextFormat{}},
+		{" b", CSV},
+		{"#b", TextFormat{Comment: '#'}},
+		{"#b", TextFormat{Separator: ',', Comment: '#'}},
+	} {
+		m.Set(1, 0, tc.cell)
+		err = WriteMatrix(&sb, m, []string{"x", "y"}, nil, tc.tf)
+		assert.True(t, errors.Is(err, ErrUnwritableCell))
+	}
+	// an empty cell can be written, unless it's the only one in the row
+	m.Set(1, 0, "")
+	assert.Nil(t, WriteMatrix(&sb, m, []string{"x", "y"}, nil, CSV))
+	single := NewMatrix(1, 1, "")
+	err = WriteMatrix(&sb, single, nil, nil, TextFormat{Separator: ','})
+	assert.True(t, errors.Is(err, ErrUnwritableCell))
+}
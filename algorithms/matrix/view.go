@@ -0,0 +1,206 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package matrix
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/tommika/gorilla/util"
+)
+
+// View is a view of the cells of a matrix (e.g., a block of it, a row or a
+// column), which shares the storage of the matrix: setting a cell of the
+// view sets the cell of the matrix, and vice versa. Views are cheap to
+// create, and are passed by value. Cell (r, c) of a view is at offset +
+// r*rowStride + c*colStride of the matrix's data.
+type View[T any] struct {
+	data      []T
+	offset    int
+	numRows   int
+	numCols   int
+	rowStride int
+	colStride int
+}
+
+// View returns a view of the whole matrix.
+func (m Matrix[T]) View() View[T] {
+	return View[T]{
+		data:      m.data,
+		numRows:   m.numRows,
+		numCols:   m.numCols,
+		rowStride: m.numCols,
+		colStride: 1,
+	}
+}
+
+// Sub returns a view of the block of the matrix from rows r0 to r1-1, and
+// columns c0 to c1-1.
+func (m Matrix[T]) Sub(r0, r1, c0, c1 int) View[T] {
+	return m.View().Sub(r0, r1, c0, c1)
+}
+
+// Row returns a view of a row of the matrix, as a 1 x n view.
+func (m Matrix[T]) Row(r int) View[T] {
+	return m.View().Row(r)
+}
+
+// Col returns a view of a column of the matrix, as an m x 1 view.
+func (m Matrix[T]) Col(c int) View[T] {
+	return m.View().Col(c)
+}
+
+// Values returns the cells of the matrix, in row-major order.
+func (m Matrix[T]) Values() iter.Seq[T] {
+	return m.View().Values()
+}
+
+// AllRows returns views of the rows of the matrix, with their indices.
+func (m Matrix[T]) AllRows() iter.Seq2[int, View[T]] {
+	return m.View().AllRows()
+}
+
+// AllCols returns views of the columns of the matrix, with their indices.
+func (m Matrix[T]) AllCols() iter.Seq2[int, View[T]] {
+	return m.View().AllCols()
+}
+
+func (v View[T]) NumRows() int {
+	return v.numRows
+}
+
+func (v View[T]) NumCols() int {
+	return v.numCols
+}
+
+// Get returns the value of a cell. Panics if the position is out of range.
+func (v View[T]) Get(row, col int) T {
+	return v.data[v.index(row, col)]
+}
+
+// Set sets the value of a cell. Panics if the position is out of range.
+func (v View[T]) Set(row, col int, val T) {
+	v.data[v.index(row, col)] = val
+}
+
+// SetAll sets the value of every cell of the view.
+func (v View[T]) SetAll(val T) {
+	for r := range v.numRows {
+		for c := range v.numCols {
+			v.data[v.offset+r*v.rowStride+c*v.colStride] = val
+		}
+	}
+}
+
+// Sub returns a view of the block of this view from rows r0 to r1-1, and
+// columns c0 to c1-1. Panics if the ranges are out of bounds.
+func (v View[T]) Sub(r0, r1, c0, c1 int) View[T] {
+	if r0 < 0 || r1 < r0 || r1 > v.numRows || c0 < 0 || c1 < c0 || c1 > v.numCols {
+		panic(fmt.Sprintf("matrix: block [%d:%d,%d:%d] out of range for %dx%d view", r0, r1, c0, c1, v.numRows, v.numCols))
+	}
+	sub := v
+	sub.numRows, sub.numCols = r1-r0, c1-c0
+	if sub.numRows > 0 && sub.numCols > 0 {
+		sub.offset += r0*v.rowStride + c0*v.colStride
+	}
+	return sub
+}
+
+// Step returns a view of every rowStep-th row and every colStep-th column of
+// this view, starting with the first. Panics if a step isn't positive.
+func (v View[T]) Step(rowStep, colStep int) View[T] {
+	if rowStep <= 0 || colStep <= 0 {
+		panic(fmt.Sprintf("matrix: invalid step %d,%d", rowStep, colStep))
+	}
+	stepped := v
+	stepped.numRows = (v.numRows + rowStep - 1) / rowStep
+	stepped.numCols = (v.numCols + colStep - 1) / colStep
+	stepped.rowStride *= rowStep
+	stepped.colStride *= colStep
+	return stepped
+}
+
+// Transpose returns a view of the transpose of this view. Unlike
+// Matrix.Transpose, no cells are copied.
+func (v View[T]) Transpose() View[T] {
+	t := v
+	t.numRows, t.numCols = v.numCols, v.numRows
+	t.rowStride, t.colStride = v.colStride, v.rowStride
+	return t
+}
+
+// Row returns a view of a row of this view, as a 1 x n view.
+func (v View[T]) Row(r int) View[T] {
+	return v.Sub(r, r+1, 0, v.numCols)
+}
+
+// Col returns a view of a column of this view, as an m x 1 view.
+func (v View[T]) Col(c int) View[T] {
+	return v.Sub(0, v.numRows, c, c+1)
+}
+
+// Values returns the cells of the view, in row-major order.
+func (v View[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for r := range v.numRows {
+			i := v.offset + r*v.rowStride
+			for range v.numCols {
+				if !yield(v.data[i]) {
+					return
+				}
+				i += v.colStride
+			}
+		}
+	}
+}
+
+// Entries returns the cells of the view, with their positions, in row-major
+// order.
+func (v View[T]) Entries() iter.Seq[Entry[T]] {
+	return func(yield func(Entry[T]) bool) {
+		for r := range v.numRows {
+			for c := range v.numCols {
+				if !yield(Entry[T]{r, c, v.data[v.offset+r*v.rowStride+c*v.colStride]}) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// AllRows returns views of the rows of this view, with their indices.
+func (v View[T]) AllRows() iter.Seq2[int, View[T]] {
+	return func(yield func(int, View[T]) bool) {
+		for r := range v.numRows {
+			if !yield(r, v.Row(r)) {
+				return
+			}
+		}
+	}
+}
+
+// AllCols returns views of the columns of this view, with their indices.
+func (v View[T]) AllCols() iter.Seq2[int, View[T]] {
+	return func(yield func(int, View[T]) bool) {
+		for c := range v.numCols {
+			if !yield(c, v.Col(c)) {
+				return
+			}
+		}
+	}
+}
+
+// Clone returns a new matrix holding a copy of the cells of the view.
+func (v View[T]) Clone() Matrix[T] {
+	m := NewMatrix(v.numRows, v.numCols, util.Zero[T]())
+	i := 0
+	for val := range v.Values() {
+		m.data[i] = val
+		i++
+	}
+	return m
+}
+
+func (v View[T]) index(row, col int) int {
+	checkRange(row, col, v.numRows, v.numCols)
+	return v.offset + row*v.rowStride + col*v.colStride
+}
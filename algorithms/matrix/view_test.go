@@ -0,0 +1,128 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package matrix
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+func sequence(rows, cols int) Matrix[int] {
+	m := NewMatrix(rows, cols, 0)
+	for i := range m.data {
+		m.data[i] = i
+	}
+	return m
+}
+
+func TestView(t *testing.T) {
+	m := sequence(4, 5)
+	v := m.View()
+	assert.Equal(t, 4, v.NumRows())
+	assert.Equal(t, 5, v.NumCols())
+	assert.DeepEqual(t, m, v.Clone())
+	assert.DeepEqual(t, slices.Collect(m.Values()), m.data)
+
+	// views share storage with the matrix
+	v.Set(1, 2, 100)
+	assert.Equal(t, 100, m.Get(1, 2))
+	m.Set(3, 4, 200)
+	assert.Equal(t, 200, v.Get(3, 4))
+
+	assertPanics(t, func() { v.Get(4, 0) })
+	assertPanics(t, func() { v.Set(0, 5, 1) })
+	assertPanics(t, func() { v.Get(-1, 0) })
+}
+
+func TestSub(t *testing.T) {
+	m := sequence(4, 5)
+	b := m.Sub(1, 3, 2, 5)
+	assert.Equal(t, 2, b.NumRows())
+	assert.Equal(t, 3, b.NumCols())
+	assert.DeepEqual(t, ParseMatrix("7 8 9\n12 13 14", strconv.Atoi), b.Clone())
+	// a block of a block
+	bb := b.Sub(1, 2, 1, 3)
+	assert.DeepEqual(t, []int{13, 14}, slices.Collect(bb.Values()))
+	bb.SetAll(-1)
+	assert.DeepEqual(t, ParseMatrix("0 1 2 3 4\n5 6 7 8 9\n10 11 12 -1 -1\n15 16 17 18 19", strconv.Atoi), m)
+
+	// the cell after the last column of a block isn't in the block
+	assertPanics(t, func() { b.Get(0, 3) })
+	assertPanics(t, func() { m.Sub(0, 5, 0, 1) })
+	assertPanics(t, func() { m.Sub(2, 1, 0, 1) })
+	assertPanics(t, func() { b.Sub(0, 1, 0, 4) })
+
+	empty := m.Sub(4, 4, 5, 5)
+	assert.Equal(t, 0, empty.NumRows())
+	assert.Equal(t, 0, len(slices.Collect(empty.Values())))
+	assert.Equal(t, 0, empty.Clone().NumRows())
+}
+
+func TestRowCol(t *testing.T) {
+	m := sequence(3, 4)
+	assert.DeepEqual(t, []int{4, 5, 6, 7}, slices.Collect(m.Row(1).Values()))
+	assert.DeepEqual(t, []int{2, 6, 10}, slices.Collect(m.Col(2).Values()))
+	assert.Equal(t, 1, m.Row(1).NumRows())
+	assert.Equal(t, 1, m.Col(2).NumCols())
+	m.Col(0).SetAll(9)
+	assert.DeepEqual(t, []int{9, 9, 9}, slices.Collect(m.Col(0).Values()))
+	assert.Equal(t, 9, m.Get(2, 0))
+	assertPanics(t, func() { m.Row(3) })
+	assertPanics(t, func() { m.Col(-1) })
+
+	rows := [][]int{}
+	for r, row := range m.AllRows() {
+		assert.Equal(t, len(rows), r)
+		rows = append(rows, slices.Collect(row.Values()))
+	}
+	assert.DeepEqual(t, m.Rows(), rows)
+	cols := [][]int{}
+	for c, col := range m.AllCols() {
+		assert.Equal(t, len(cols), c)
+		cols = append(cols, slices.Collect(col.Values()))
+	}
+	assert.DeepEqual(t, m.Transpose().Rows(), cols)
+
+	// iteration can stop early
+	for r := range m.AllRows() {
+		if r == 1 {
+			break
+		}
+	}
+	for range m.Values() {
+		break
+	}
+}
+
+func TestStepTranspose(t *testing.T) {
+	m := sequence(5, 6)
+	s := m.View().Step(2, 3)
+	assert.DeepEqual(t, ParseMatrix("0 3\n12 15\n24 27", strconv.Atoi), s.Clone())
+	assert.DeepEqual(t, ParseMatrix("15 17", strconv.Atoi), m.Sub(2, 4, 3, 6).Step(1, 2).Row(0).Clone())
+	assertPanics(t, func() { m.View().Step(0, 1) })
+
+	tv := m.View().Transpose()
+	assert.Equal(t, 6, tv.NumRows())
+	assert.DeepEqual(t, m.Transpose(), tv.Clone())
+	tv.Set(5, 0, -5)
+	assert.Equal(t, -5, m.Get(0, 5))
+	assert.DeepEqual(t, m.Col(1).Clone(), m.View().Transpose().Row(1).Transpose().Clone())
+
+	// a view is enumerable, so can be used as an adjacency matrix, or written
+	// in Matrix Market format
+	var sub Enumerable[int] = ParseMatrix("0 1 0\n2 0 3", strconv.Atoi).Sub(0, 2, 1, 3)
+	assert.DeepEqual(t, []Entry[int]{{0, 0, 1}, {0, 1, 0}, {1, 0, 0}, {1, 1, 3}}, slices.Collect(sub.Entries()))
+}
+
+func TestClone(t *testing.T) {
+	m := sequence(2, 3)
+	c := m.Clone()
+	assert.DeepEqual(t, m, c)
+	c.Set(0, 0, 10)
+	assert.Equal(t, 0, m.Get(0, 0))
+	empty := Matrix[int]{}
+	assert.Equal(t, 0, empty.Clone().NumRows())
+	assert.Equal(t, 0, len(slices.Collect(empty.Values())))
+}
@@ -3,6 +3,9 @@ package search
 
 import "github.com/tommika/gorilla/algorithms/types"
 
+// BinarySearch searches the sorted items for an item equal to find. Returns
+// the item, if found. See LowerBound for the index of the item (or where it
+// would be inserted), and EqualRange for all of the items equal to find.
 func BinarySearch[T any](items []T, find T, compare types.Compare[T]) (val T, found bool) {
 	i, j := 0, len(items)
 	for i < j {
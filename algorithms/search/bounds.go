@@ -0,0 +1,50 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package search
+
+import "github.com/tommika/gorilla/algorithms/types"
+
+// Search returns the smallest index i in [0, n) for which pred(i) is true,
+// where pred is false up to some index, and true from then on; returns n if
+// pred is false for every index. Makes O(log n) calls to pred. This is the
+// basis of the other binary searches.
+func Search(n int, pred func(i int) bool) int {
+	i, j := 0, n
+	for i < j {
+		m := i + (j-i)/2
+		if pred(m) {
+			j = m
+		} else {
+			i = m + 1
+		}
+	}
+	return i
+}
+
+// LowerBound returns the index of the first of the sorted items that isn't
+// less than find: the index of the first item equal to find, if there is
+// one, otherwise the index at which find would be inserted to keep the items
+// sorted.
+func LowerBound[T any](items []T, find T, compare types.Compare[T]) int {
+	return Search(len(items), func(i int) bool {
+		return compare(items[i], find) >= 0
+	})
+}
+
+// UpperBound returns the index of the first of the sorted items that is
+// greater than find: the index after the last item equal to find, if there is
+// one, otherwise the index at which find would be inserted to keep the items
+// sorted.
+func UpperBound[T any](items []T, find T, compare types.Compare[T]) int {
+	return Search(len(items), func(i int) bool {
+		return compare(items[i], find) > 0
+	})
+}
+
+// EqualRange returns the range of the sorted items that are equal to find,
+// items[lo:hi]; the range is empty (lo == hi) if there are none, and lo is
+// the index at which find would be inserted.
+func EqualRange[T any](items []T, find T, compare types.Compare[T]) (lo, hi int) {
+	lo = LowerBound(items, find, compare)
+	hi = lo + UpperBound(items[lo:], find, compare)
+	return
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package search
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/util"
+)
+
+// lowerWords returns the words, in lower case, sorted by strings.Compare
+func lowerWords(t testing.TB) []string {
+	words, err := util.ReadFileAsWords("../test-data/words.txt")
+	assert.Nil(t, err)
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	slices.Sort(words)
+	return words
+}
+
+func TestSearch(t *testing.T) {
+	for n := range 10 {
+		for k := range n + 1 {
+			assert.Equal(t, k, Search(n, func(i int) bool { return i >= k }))
+		}
+	}
+	assert.Equal(t, 0, Search(0, func(int) bool { panic("not called") }))
+}
+
+func TestBounds(t *testing.T) {
+	items := []int{1, 2, 2, 2, 5, 7, 7}
+	for _, tc := range []struct {
+		find, lo, hi int
+	}{
+		{0, 0, 0},
+		{1, 0, 1},
+		{2, 1, 4},
+		{3, 4, 4},
+		{5, 4, 5},
+		{6, 5, 5},
+		{7, 5, 7},
+		{8, 7, 7},
+	} {
+		assert.Equal(t, tc.lo, LowerBound(items, tc.find, cmp.Compare[int]))
+		assert.Equal(t, tc.hi, UpperBound(items, tc.find, cmp.Compare[int]))
+		lo, hi := EqualRange(items, tc.find, cmp.Compare[int])
+		assert.Equal(t, tc.lo, lo)
+		assert.Equal(t, tc.hi, hi)
+	}
+	lo, hi := EqualRange([]int{}, 1, cmp.Compare[int])
+	assert.Equal(t, 0, lo)
+	assert.Equal(t, 0, hi)
+}
+
+func TestBoundsRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for range 100 {
+		items := make([]int, rnd.Intn(50))
+		for i := range items {
+			items[i] = rnd.Intn(20)
+		}
+		slices.Sort(items)
+		for find := -1; find <= 20; find++ {
+			lo, hi := EqualRange(items, find, cmp.Compare[int])
+			assert.Equal(t, sort.SearchInts(items, find), lo)
+			assert.Equal(t, sort.SearchInts(items, find+1), hi)
+			// inserting at the lower bound keeps the items sorted
+			assert.True(t, slices.IsSorted(slices.Insert(slices.Clone(items), lo, find)))
+		}
+	}
+}
+
+func TestBoundsWords(t *testing.T) {
+	words := lowerWords(t)
+	// each word appears twice
+	doubled := slices.Sorted(slices.Values(append(slices.Clone(words), words...)))
+	for i, w := range words {
+		lo, hi := EqualRange(doubled, w, strings.Compare)
+		assert.Equal(t, 2*i, lo)
+		assert.Equal(t, 2*i+2, hi)
+		assert.Equal(t, i, LowerBound(words, w, strings.Compare))
+		_, found := BinarySearch(words, w, strings.Compare)
+		assert.True(t, found)
+	}
+	// words that aren't in the list are inserted after their prefixes
+	i := LowerBound(words, "aardvarkz", strings.Compare)
+	assert.Equal(t, "aardvarks", words[i-1])
+	assert.Equal(t, i, UpperBound(words, "aardvarkz", strings.Compare))
+}
+
+func BenchmarkLowerBoundWords(b *testing.B) {
+	words := lowerWords(b)
+	b.ResetTimer()
+	for i := range b.N {
+		LowerBound(words, words[i%len(words)], strings.Compare)
+	}
+}
+
+func BenchmarkBinarySearchWords(b *testing.B) {
+	words := lowerWords(b)
+	b.ResetTimer()
+	for i := range b.N {
+		BinarySearch(words, words[i%len(words)], strings.Compare)
+	}
+}
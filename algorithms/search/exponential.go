@@ -0,0 +1,37 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package search
+
+import "github.com/tommika/gorilla/algorithms/types"
+
+// ExponentialSearch returns the smallest index i >= 0 for which pred(i) is
+// true, where pred is false up to some index, and true from then on (pred
+// must eventually be true.) Unlike Search, the number of indices needn't be
+// known: indices 0, 1, 3, 7, 15... are probed until pred is true, and the
+// last interval is then searched, so pred is called O(log i) times. This is
+// suited to unbounded sequences, and to sequences where the index sought is
+// likely to be near the start.
+func ExponentialSearch(pred func(i int) bool) int {
+	lo, bound := 0, 1
+	for !pred(bound - 1) {
+		lo, bound = bound, bound*2
+	}
+	// pred(lo-1) is false (or lo is zero), and pred(bound-1) is true
+	return lo + Search(bound-1-lo, func(i int) bool {
+		return pred(lo + i)
+	})
+}
+
+// ExponentialLowerBound finds the first item of a sorted sequence of unknown
+// length that isn't less than find, using ExponentialSearch (also known as
+// galloping search.) The sequence is given by at, which returns the item at
+// an index, or false if the index is past the end. Returns the index of the
+// item (or the length of the sequence, if there's no such item), and whether
+// the item is equal to find.
+func ExponentialLowerBound[T any](at func(i int) (T, bool), find T, compare types.Compare[T]) (index int, found bool) {
+	index = ExponentialSearch(func(i int) bool {
+		item, ok := at(i)
+		return !ok || compare(item, find) >= 0
+	})
+	item, ok := at(index)
+	return index, ok && compare(item, find) == 0
+}
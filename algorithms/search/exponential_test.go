@@ -0,0 +1,104 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package search
+
+import (
+	"cmp"
+	"strings"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+func TestExponentialSearch(t *testing.T) {
+	for k := range 100 {
+		calls := 0
+		i := ExponentialSearch(func(i int) bool {
+			calls++
+			return i >= k
+		})
+		assert.Equal(t, k, i)
+		// O(log k) calls
+		assert.True(t, calls <= 2*bitLength(k)+1)
+	}
+	// a large index
+	const big = 1<<40 + 12345
+	assert.Equal(t, big, ExponentialSearch(func(i int) bool { return i >= big }))
+}
+
+func bitLength(n int) (bits int) {
+	for ; n > 0; n >>= 1 {
+		bits++
+	}
+	return
+}
+
+// stream returns the items of an "unbounded" sequence, which ends at the
+// given length
+func stream[T any](items []T) func(i int) (T, bool) {
+	return func(i int) (item T, ok bool) {
+		if i >= len(items) {
+			return
+		}
+		return items[i], true
+	}
+}
+
+func TestExponentialLowerBound(t *testing.T) {
+	items := []int{1, 3, 3, 8}
+	for find, expected := range []int{0, 0, 1, 1, 3, 3, 3, 3, 3, 4} {
+		i, found := ExponentialLowerBound(stream(items), find, cmp.Compare[int])
+		assert.Equal(t, expected, i)
+		assert.Equal(t, find == 1 || find == 3 || find == 8, found)
+	}
+	i, found := ExponentialLowerBound(stream([]int{}), 1, cmp.Compare[int])
+	assert.Equal(t, 0, i)
+	assert.False(t, found)
+
+	// squares, without end
+	squares := func(i int) (int, bool) { return i * i, true }
+	i, found = ExponentialLowerBound(squares, 1000000, cmp.Compare[int])
+	assert.Equal(t, 1000, i)
+	assert.True(t, found)
+	i, found = ExponentialLowerBound(squares, 1000001, cmp.Compare[int])
+	assert.Equal(t, 1001, i)
+	assert.False(t, found)
+}
+
+func TestExponentialWords(t *testing.T) {
+	words := lowerWords(t)
+	for i := 0; i < len(words); i += 7 {
+		j, found := ExponentialLowerBound(stream(words), words[i], strings.Compare)
+		assert.True(t, found)
+		assert.Equal(t, LowerBound(words, words[i], strings.Compare), j)
+	}
+	j, found := ExponentialLowerBound(stream(words), "zzzzz", strings.Compare)
+	assert.Equal(t, len(words), j)
+	assert.False(t, found)
+}
+
+func BenchmarkExponentialWords(b *testing.B) {
+	words := lowerWords(b)
+	at := stream(words)
+	b.ResetTimer()
+	for i := range b.N {
+		ExponentialLowerBound(at, words[i%len(words)], strings.Compare)
+	}
+}
+
+// galloping is fast when the item is near the start
+func BenchmarkExponentialWordsNearStart(b *testing.B) {
+	words := lowerWords(b)
+	at := stream(words)
+	b.ResetTimer()
+	for i := range b.N {
+		ExponentialLowerBound(at, words[i%100], strings.Compare)
+	}
+}
+
+func BenchmarkLowerBoundWordsNearStart(b *testing.B) {
+	words := lowerWords(b)
+	b.ResetTimer()
+	for i := range b.N {
+		LowerBound(words, words[i%100], strings.Compare)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package search
+
+import (
+	"cmp"
+
+	"github.com/tommika/gorilla/algorithms/types"
+)
+
+// Number is the constraint for numeric keys.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// InterpolationSearch finds the first of the sorted items that isn't less
+// than find, as LowerBound does. Rather than probing the middle of the range
+// being searched, it probes where find is expected to be, by interpolating
+// between the numeric keys of the items at the ends of the range; the key
+// must be non-decreasing over the items. For uniformly distributed keys, this
+// takes O(log log n) probes. A probe that doesn't halve the range is followed
+// by a bisection, so the search never takes more than twice the probes of a
+// binary search. Returns the index of the item, and whether it's equal to
+// find.
+func InterpolationSearch[T any, K Number](items []T, find T, compare types.Compare[T], key func(T) K) (index int, found bool) {
+	// items[:lo] are less than find; items[hi:] are not
+	lo, hi := 0, len(items)
+	probe := func(m int) {
+		if compare(items[m], find) < 0 {
+			lo = m + 1
+		} else {
+			hi = m
+		}
+	}
+	target := float64(key(find))
+	for lo < hi {
+		size := hi - lo
+		first, last := float64(key(items[lo])), float64(key(items[hi-1]))
+		m := lo + size/2
+		switch {
+		case target <= first:
+			m = lo
+		case target > last:
+			m = hi - 1
+		case last > first:
+			m = lo + int((target-first)/(last-first)*float64(size-1))
+		}
+		probe(m)
+		if lo < hi && hi-lo > size/2 {
+			probe(lo + (hi-lo)/2)
+		}
+	}
+	return lo, lo < len(items) && compare(items[lo], find) == 0
+}
+
+// InterpolationSearchNumbers finds the first of the sorted numbers that isn't
+// less than find, using InterpolationSearch.
+func InterpolationSearchNumbers[K Number](items []K, find K) (index int, found bool) {
+	return InterpolationSearch(items, find, cmp.Compare[K], func(k K) K { return k })
+}
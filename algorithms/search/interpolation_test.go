@@ -0,0 +1,140 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package search
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+)
+
+// prefixKey returns a numeric key for a string, from its first 8 bytes, that
+// is non-decreasing in the order of strings.Compare
+func prefixKey(s string) (key uint64) {
+	for i := range 8 {
+		key <<= 8
+		if i < len(s) {
+			key |= uint64(s[i])
+		}
+	}
+	return
+}
+
+func TestInterpolationSearch(t *testing.T) {
+	items := []int{1, 2, 2, 2, 5, 7, 7, 100}
+	for find := -1; find <= 101; find++ {
+		i, found := InterpolationSearchNumbers(items, find)
+		assert.Equal(t, LowerBound(items, find, cmp.Compare[int]), i)
+		assert.Equal(t, slices.Contains(items, find), found)
+	}
+	i, found := InterpolationSearchNumbers([]float64{}, 1)
+	assert.Equal(t, 0, i)
+	assert.False(t, found)
+	i, found = InterpolationSearchNumbers([]uint8{3, 3, 3}, 3)
+	assert.Equal(t, 0, i)
+	assert.True(t, found)
+}
+
+func TestInterpolationSearchRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for _, distribution := range []func() float64{
+		rnd.Float64,
+		func() float64 { return math.Exp(20 * rnd.Float64()) }, // skewed
+	} {
+		items := make([]float64, 10000)
+		for i := range items {
+			items[i] = distribution()
+		}
+		slices.Sort(items)
+		for _, find := range items[:1000] {
+			i, found := InterpolationSearchNumbers(items, find)
+			assert.True(t, found)
+			assert.Equal(t, find, items[i])
+			assert.Equal(t, LowerBound(items, find, cmp.Compare[float64]), i)
+			i, found = InterpolationSearchNumbers(items, find+1e-12)
+			assert.False(t, found)
+			assert.Equal(t, LowerBound(items, find+1e-12, cmp.Compare[float64]), i)
+		}
+	}
+}
+
+func TestInterpolationProbes(t *testing.T) {
+	items := make([]int, 1<<20)
+	for i := range items {
+		items[i] = 3 * i
+	}
+	probes := 0
+	count := func(a, b int) int {
+		probes++
+		return cmp.Compare(a, b)
+	}
+	identity := func(k int) int { return k }
+	// uniform keys take very few probes
+	for _, find := range []int{0, 3, 12345, 3 * 777777, 3*len(items) - 3} {
+		probes = 0
+		i, found := InterpolationSearch(items, find, count, identity)
+		assert.True(t, found)
+		assert.Equal(t, find/3, i)
+		assert.True(t, probes <= 5)
+	}
+	// skewed keys take no more than twice the probes of a binary search
+	for i := range items {
+		items[i] = i * i * i
+	}
+	probes = 0
+	_, found := InterpolationSearch(items, 1000*1000*1000, count, identity)
+	assert.True(t, found)
+	assert.True(t, probes <= 2*21+1)
+}
+
+func TestInterpolationWords(t *testing.T) {
+	words := lowerWords(t)
+	for i := 0; i < len(words); i += 7 {
+		j, found := InterpolationSearch(words, words[i], strings.Compare, prefixKey)
+		assert.True(t, found)
+		assert.Equal(t, LowerBound(words, words[i], strings.Compare), j)
+		j, found = InterpolationSearch(words, words[i]+"-bogus", strings.Compare, prefixKey)
+		assert.False(t, found)
+		assert.Equal(t, LowerBound(words, words[i]+"-bogus", strings.Compare), j)
+	}
+}
+
+// word prefixes aren't uniformly distributed, so interpolation takes more
+// probes than bisection
+func BenchmarkInterpolationWords(b *testing.B) {
+	words := lowerWords(b)
+	b.ResetTimer()
+	for i := range b.N {
+		InterpolationSearch(words, words[i%len(words)], strings.Compare, prefixKey)
+	}
+}
+
+func BenchmarkInterpolationNumbers(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	items := make([]float64, 1<<20)
+	for i := range items {
+		items[i] = rnd.Float64()
+	}
+	slices.Sort(items)
+	b.ResetTimer()
+	for i := range b.N {
+		InterpolationSearchNumbers(items, items[i%len(items)])
+	}
+}
+
+func BenchmarkLowerBoundNumbers(b *testing.B) {
+	rnd := rand.New(rand.NewSource(1))
+	items := make([]float64, 1<<20)
+	for i := range items {
+		items[i] = rnd.Float64()
+	}
+	slices.Sort(items)
+	b.ResetTimer()
+	for i := range b.N {
+		LowerBound(items, items[i%len(items)], cmp.Compare[float64])
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package tsp
+
+// Heuristics for the (symmetric) traveling salesman problem: finding a short
+// tour that visits each of a set of points exactly once, and returns to the
+// start. Finding the shortest tour is NP-hard, so these find good tours
+// quickly rather than optimal ones.
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/tommika/gorilla/algorithms/graph"
+	"github.com/tommika/gorilla/algorithms/matrix"
+	"github.com/tommika/gorilla/must"
+)
+
+// ErrInvalidDistance is returned when a distance between two points isn't a
+// finite number.
+var ErrInvalidDistance = errors.New("invalid distance")
+
+// Problem is an instance of the traveling salesman problem: the distances
+// between each pair of points, which are numbered 0..n-1. Distances should
+// be symmetric, and satisfy the triangle inequality.
+type Problem struct {
+	dist matrix.Matrix[float64]
+}
+
+// NewProblem creates a problem from the given points, using the given
+// function to compute the distance between two points (e.g., the haversine
+// distance between two geographic points.) Distances are computed once, and
+// stored in an n x n matrix. Returns ErrInvalidDistance if a distance is NaN
+// or infinite.
+func NewProblem[P any](points []P, distance func(a, b P) float64) (*Problem, error) {
+	n := len(points)
+	p := &Problem{dist: matrix.NewMatrix(n, n, 0.0)}
+	for i := range n {
+		for j := i + 1; j < n; j++ {
+			d := distance(points[i], points[j])
+			if math.IsNaN(d) || math.IsInf(d, 0) {
+				return nil, fmt.Errorf("%w: %v between points %d and %d", ErrInvalidDistance, d, i, j)
+			}
+			p.dist.Set(i, j, d)
+			p.dist.Set(j, i, d)
+		}
+	}
+	return p, nil
+}
+
+// Size returns the number of points.
+func (p *Problem) Size() int {
+	return p.dist.NumRows()
+}
+
+// Distance returns the distance between two points.
+func (p *Problem) Distance(i, j int) float64 {
+	return p.dist.Get(i, j)
+}
+
+// TourLength returns the length of the given tour, including the distance from
+// the last point back to the first.
+func (p *Problem) TourLength(tour []int) (length float64) {
+	for i, v := range tour {
+		length += p.Distance(tour[(i+len(tour)-1)%len(tour)], v)
+	}
+	return
+}
+
+// NearestNeighbor constructs a tour by starting at the given point, and
+// repeatedly moving to the nearest point not yet visited. Runs in O(n^2) time;
+// tours are typically about 25% longer than optimal.
+func (p *Problem) NearestNeighbor(start int) []int {
+	n := p.Size()
+	if n == 0 {
+		return []int{}
+	}
+	visited := make([]bool, n)
+	tour := make([]int, 0, n)
+	for u := start; len(tour) < n; {
+		visited[u] = true
+		tour = append(tour, u)
+		next, nearest := -1, math.Inf(1)
+		for v := range n {
+			if !visited[v] && p.Distance(u, v) < nearest {
+				next, nearest = v, p.Distance(u, v)
+			}
+		}
+		u = next
+	}
+	return tour
+}
+
+// TwoOpt improves the given tour using the 2-opt local search: as long as
+// replacing two edges of the tour, (a,b) and (c,d), with (a,c) and (b,d)
+// makes the tour shorter, the section of the tour from b to c is reversed.
+// Returns the improved tour, which starts at the same point; the given tour is
+// not modified.
+func (p *Problem) TwoOpt(tour []int) []int {
+	// ignore improvements smaller than this, to avoid looping due to
+	// rounding errors
+	const epsilon = 1e-9
+	tour = slices.Clone(tour)
+	n := len(tour)
+	for improved := n > 3; improved; {
+		improved = false
+		for i := 0; i < n-2; i++ {
+			a, b := tour[i], tour[i+1]
+			for j := i + 2; j < n; j++ {
+				c, d := tour[j], tour[(j+1)%n]
+				if d == a {
+					continue
+				}
+				delta := p.Distance(a, c) + p.Distance(b, d) - p.Distance(a, b) - p.Distance(c, d)
+				if delta < -epsilon {
+					slices.Reverse(tour[i+1 : j+1])
+					b = tour[i+1]
+					improved = true
+				}
+			}
+		}
+	}
+	return tour
+}
+
+// Christofides constructs a tour in the style of Christofides' algorithm:
+// find a minimum spanning tree of the points; add a matching of the points
+// with an odd degree in the tree; find an Eulerian circuit of the result; and
+// then skip points that have already been visited. The matching is found
+// greedily (shortest edges first) rather than exactly, so the tour isn't
+// guaranteed to be within 1.5 times the optimal length, but is typically
+// much closer. Runs in O(n^2 log n) time. The tour starts at point zero.
+func (p *Problem) Christofides() []int {
+	n := p.Size()
+	if n < 3 {
+		return identity(n)
+	}
+	g := graph.NewGraph[int, float64](graph.Undirected)
+	degree := make([]int, n)
+	for v, u := range p.spanningTree() {
+		if u != noPoint {
+			g.AddEdge(u, v, p.Distance(u, v))
+			degree[u]++
+			degree[v]++
+		}
+	}
+	odd := []int{}
+	for v, d := range degree {
+		if d%2 != 0 {
+			odd = append(odd, v)
+		}
+	}
+	type pair struct{ u, v int }
+	pairs := []pair{}
+	for i, u := range odd {
+		for _, v := range odd[i+1:] {
+			pairs = append(pairs, pair{u, v})
+		}
+	}
+	slices.SortStableFunc(pairs, func(a, b pair) int {
+		return cmp.Compare(p.Distance(a.u, a.v), p.Distance(b.u, b.v))
+	})
+	matched := make([]bool, n)
+	for _, e := range pairs {
+		if !matched[e.u] && !matched[e.v] {
+			g.AddEdge(e.u, e.v, p.Distance(e.u, e.v))
+			matched[e.u], matched[e.v] = true, true
+		}
+	}
+	// every node has an even degree, and the tree connects them
+	circuit := must.NotBeAnError(g.EulerianCircuit())
+	visited := make([]bool, n)
+	tour := make([]int, 0, n)
+	for _, v := range circuit {
+		if !visited[v] {
+			visited[v] = true
+			tour = append(tour, v)
+		}
+	}
+	return tour
+}
+
+// Solve finds a good tour, by improving both the nearest neighbor tour and the
+// Christofides tour using 2-opt, and choosing the shorter. The tour starts at
+// point zero.
+func (p *Problem) Solve() []int {
+	best := p.TwoOpt(p.NearestNeighbor(0))
+	if tour := p.TwoOpt(p.Christofides()); p.TourLength(tour) < p.TourLength(best) {
+		best = tour
+	}
+	return best
+}
+
+// noPoint indicates the absence of a point.
+const noPoint = -1
+
+// spanningTree finds a minimum spanning tree of the points, using Prim's
+// algorithm (in O(n^2) time, which is optimal for a complete graph.) Returns
+// the parent of each point in the tree, rooted at point zero.
+func (p *Problem) spanningTree() []int {
+	n := p.Size()
+	parent := make([]int, n)
+	nearest := make([]float64, n) // distance to the tree
+	inTree := make([]bool, n)
+	for v := range n {
+		parent[v], nearest[v] = noPoint, math.Inf(1)
+	}
+	nearest[0] = 0
+	for range n {
+		u := noPoint
+		for v := range n {
+			if !inTree[v] && (u == noPoint || nearest[v] < nearest[u]) {
+				u = v
+			}
+		}
+		inTree[u] = true
+		for v := range n {
+			if !inTree[v] && p.Distance(u, v) < nearest[v] {
+				parent[v], nearest[v] = u, p.Distance(u, v)
+			}
+		}
+	}
+	return parent
+}
+
+func identity(n int) []int {
+	tour := make([]int, n)
+	for i := range tour {
+		tour[i] = i
+	}
+	return tour
+}
@@ -0,0 +1,149 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package tsp
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/must"
+)
+
+type point struct{ x, y float64 }
+
+func euclidean(a, b point) float64 {
+	return math.Hypot(a.x-b.x, a.y-b.y)
+}
+
+func randomPoints(n int, seed int64) []point {
+	rnd := rand.New(rand.NewSource(seed))
+	points := make([]point, n)
+	for i := range points {
+		points[i] = point{rnd.Float64() * 1000, rnd.Float64() * 1000}
+	}
+	return points
+}
+
+// assertTour asserts that the tour visits each point exactly once
+func assertTour(t *testing.T, p *Problem, tour []int) {
+	t.Helper()
+	assert.Equal(t, p.Size(), len(tour))
+	sorted := slices.Sorted(slices.Values(tour))
+	for i, v := range sorted {
+		assert.Equal(t, i, v)
+	}
+}
+
+// optimalLength finds the length of the shortest tour, by trying every tour
+// that starts at point zero
+func optimalLength(p *Problem) float64 {
+	best := math.Inf(1)
+	var permute func(tour []int, k int)
+	permute = func(tour []int, k int) {
+		if k == len(tour) {
+			best = min(best, p.TourLength(tour))
+			return
+		}
+		for i := k; i < len(tour); i++ {
+			tour[k], tour[i] = tour[i], tour[k]
+			permute(tour, k+1)
+			tour[k], tour[i] = tour[i], tour[k]
+		}
+	}
+	permute(identity(p.Size()), 1)
+	return best
+}
+
+func TestTourLength(t *testing.T) {
+	p := must.NotBeAnError(NewProblem([]point{{0, 0}, {3, 0}, {3, 4}}, euclidean))
+	assert.Equal(t, 3, p.Size())
+	assert.Equal(t, 5.0, p.Distance(2, 0))
+	assert.Equal(t, 12.0, p.TourLength([]int{0, 1, 2}))
+	assert.Equal(t, 0.0, p.TourLength([]int{}))
+}
+
+func TestInvalidDistance(t *testing.T) {
+	points := []point{{0, 0}, {3, 0}, {3, 4}}
+	for _, bad := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, err := NewProblem(points, func(a, b point) float64 {
+			if a == points[1] || b == points[1] {
+				return bad
+			}
+			return euclidean(a, b)
+		})
+		t.Log(err)
+		assert.True(t, errors.Is(err, ErrInvalidDistance))
+	}
+}
+
+func TestCircle(t *testing.T) {
+	// for points on a circle, the shortest tour visits them in order around
+	// the circle
+	const n = 40
+	points := make([]point, n)
+	for i := range points {
+		angle := 2 * math.Pi * float64(i) / n
+		points[i] = point{math.Cos(angle), math.Sin(angle)}
+	}
+	rand.New(rand.NewSource(1)).Shuffle(n, func(i, j int) {
+		points[i], points[j] = points[j], points[i]
+	})
+	p := must.NotBeAnError(NewProblem(points, euclidean))
+	optimal := n * 2 * math.Sin(math.Pi/n)
+	for _, tour := range [][]int{
+		p.TwoOpt(identity(n)),
+		p.TwoOpt(p.NearestNeighbor(5)),
+		p.TwoOpt(p.Christofides()),
+		p.Solve(),
+	} {
+		assertTour(t, p, tour)
+		assert.EqualEpsilon(t, optimal, p.TourLength(tour), 1e-9)
+	}
+	assert.Equal(t, 5, p.NearestNeighbor(5)[0])
+	assert.Equal(t, 0, p.Solve()[0])
+}
+
+func TestSmall(t *testing.T) {
+	for seed := range int64(5) {
+		p := must.NotBeAnError(NewProblem(randomPoints(8, seed), euclidean))
+		optimal := optimalLength(p)
+		nn := p.NearestNeighbor(0)
+		assertTour(t, p, nn)
+		christofides := p.Christofides()
+		assertTour(t, p, christofides)
+		assert.True(t, p.TourLength(christofides) <= 2*optimal)
+		improved := p.TwoOpt(nn)
+		assert.True(t, p.TourLength(improved) <= p.TourLength(nn))
+		tour := p.Solve()
+		assertTour(t, p, tour)
+		assert.True(t, p.TourLength(tour) <= 1.1*optimal)
+	}
+	for n := range 4 {
+		p := must.NotBeAnError(NewProblem(randomPoints(n, 1), euclidean))
+		assertTour(t, p, p.Solve())
+		assertTour(t, p, p.Christofides())
+	}
+}
+
+func TestLarge(t *testing.T) {
+	p := must.NotBeAnError(NewProblem(randomPoints(500, 1), euclidean))
+	nn := p.NearestNeighbor(0)
+	christofides := p.Christofides()
+	tour := p.Solve()
+	assertTour(t, p, tour)
+	assert.True(t, p.TourLength(tour) < p.TourLength(nn))
+	assert.True(t, p.TourLength(tour) < p.TourLength(christofides))
+	t.Logf("nearest neighbor: %.0f; christofides: %.0f; solved: %.0f",
+		p.TourLength(nn), p.TourLength(christofides), p.TourLength(tour))
+}
+
+func BenchmarkSolve(b *testing.B) {
+	p := must.NotBeAnError(NewProblem(randomPoints(500, 1), euclidean))
+	b.ResetTimer()
+	for range b.N {
+		p.Solve()
+	}
+}
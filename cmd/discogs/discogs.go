@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"github.com/tommika/gorilla/discogs"
 	"github.com/tommika/gorilla/xflags"
@@ -15,7 +16,13 @@ type Options struct {
 	Debug        bool     `flag:"d|debug,Enable debug logging"`
 	ArtistId     uint64   `flag:"a|artist,Artist id to search"`
 	PathToId     uint64   `flag:"p|path-to,Find the path to this artist"`
+	NumPaths     int      `flag:"n|num-paths,Show up to this many alternative paths"`
 	RelatedDepth int      `flag:"r|related-depth,Show related artists to this depth"`
+	EgoFile      string   `flag:"e|ego-dot,Write the network of related artists (to related-depth) in DOT format to this file"`
+	Top          int      `flag:"t|top,Show this many of the most influential artists"`
+	Scenes       bool     `flag:"s|scenes,Cluster artists into scenes"`
+	Bipartite    bool     `flag:"b|bipartite,Analyze the artist-to-group relation as a bipartite graph"`
+	Stats        bool     `flag:"S|stats,Show structural statistics of the artist network"`
 	Files        []string `flag:"*,<artists.xml> ..."` // artists to import
 }
 
@@ -49,7 +56,44 @@ func main() {
 	fmt.Printf("#nodes  : %d\n", d.ArtistNodeCount())
 	fmt.Printf("#edges  : %d\n", d.ArtistEdgeCount())
 
+	if opts.Stats {
+		stats := d.Stats()
+		fmt.Printf("artist network:\n")
+		stats.Fprint(os.Stdout)
+	}
+
+	if opts.Top > 0 {
+		fmt.Printf("most influential artists:\n")
+		for _, r := range d.InfluentialArtists(opts.Top) {
+			fmt.Printf("\t[%d] %s (%.4f)\n", r.Node, d.ArtistName(r.Node), r.Score)
+		}
+	}
+
+	if opts.Bipartite {
+		left, right, oddCycle := d.Bipartition()
+		if oddCycle != nil {
+			fmt.Printf("not bipartite; odd cycle:\n")
+			for _, id := range oddCycle {
+				fmt.Printf("\t[%d] %s\n", id, d.ArtistName(id))
+			}
+		} else {
+			fmt.Printf("bipartite: %d and %d artists\n", len(left), len(right))
+			matching, _ := d.MemberGroupMatching()
+			fmt.Printf("maximum matching: %d pairs\n", len(matching))
+		}
+	}
+
 	artistId := discogs.ArtistId(opts.ArtistId)
+	if opts.Scenes {
+		scenes := d.Scenes()
+		fmt.Printf("#scenes : %d (modularity=%.4f)\n", scenes.Count(), scenes.Modularity())
+		if scene, found := scenes.Community(artistId); found {
+			fmt.Printf("scene of [%d] %s:\n", artistId, d.ArtistName(artistId))
+			for _, id := range scenes.Members()[scene] {
+				fmt.Printf("\t[%d] %s\n", id, d.ArtistName(id))
+			}
+		}
+	}
 	pathToId := discogs.ArtistId(opts.PathToId)
 	if opts.ArtistId != 0 {
 		name := d.ArtistName(artistId)
@@ -62,12 +106,18 @@ func main() {
 		if opts.RelatedDepth > 0 {
 			fmt.Fprintf(os.Stderr, "related artists (depth==%d):\n", opts.RelatedDepth)
 			count := 0
-			d.RelatedArtists(artistId, opts.RelatedDepth, func(id discogs.ArtistId) {
+			d.RelatedArtistsWithDistance(artistId, opts.RelatedDepth, func(id discogs.ArtistId, distance int) {
 				count++
-				fmt.Fprintf(os.Stderr, "\t[%d] %s\n", id, d.ArtistName(id))
+				fmt.Fprintf(os.Stderr, "\t%d: [%d] %s\n", distance, id, d.ArtistName(id))
 			})
 			fmt.Fprintf(os.Stderr, "found %d related artists\n", count)
 		}
+		if len(opts.EgoFile) > 0 {
+			if err := writeEgoNetwork(d, artistId, opts.RelatedDepth, opts.EgoFile); err != nil {
+				fmt.Fprintf(os.Stderr, "error: %s\n", err)
+				errors += 1
+			}
+		}
 		if opts.PathToId != 0 {
 			path := d.PathBetweenArtists(artistId, pathToId, 0)
 			if len(path) == 0 {
@@ -78,6 +128,20 @@ func main() {
 					fmt.Fprintf(os.Stderr, "\t[%d]%s\n", id, d.ArtistName(id))
 				}
 			}
+			if len(path) > 0 && opts.NumPaths > 1 {
+				fmt.Fprintf(os.Stderr, "Alternative paths to [%d]%s:\n", opts.PathToId, d.ArtistName(pathToId))
+				n := 0
+				for path := range d.PathsBetweenArtists(artistId, pathToId) {
+					if n++; n > opts.NumPaths {
+						break
+					}
+					names := make([]string, len(path))
+					for i, id := range path {
+						names[i] = fmt.Sprintf("[%d]%s", id, d.ArtistName(id))
+					}
+					fmt.Fprintf(os.Stderr, "\t%d: %s\n", n, strings.Join(names, " -> "))
+				}
+			}
 		}
 	}
 	if errors > 0 {
@@ -85,3 +149,12 @@ func main() {
 		return // needed for testing
 	}
 }
+
+func writeEgoNetwork(d *discogs.Discogs, id discogs.ArtistId, maxDepth int, fileName string) error {
+	out, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	return d.WriteEgoNetworkDOT(out, id, maxDepth)
+}
@@ -3,6 +3,7 @@ package main
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/tommika/gorilla/assert"
@@ -27,7 +28,13 @@ func Test(t *testing.T) {
 		"discogs",
 		"--artist=316130",
 		"--path-to=229492",
+		"--num-paths=3",
+		"--ego-dot=" + filepath.Join(t.TempDir(), "ego.dot"),
 		"--related-depth=4",
+		"--top=3",
+		"--scenes",
+		"--bipartite",
+		"--stats",
 		"../../discogs/test-data/discogs-artists-xtc.xml",
 	}
 	main()
@@ -9,12 +9,12 @@ import (
 	"os"
 
 	"github.com/tommika/gorilla/geo/gpx"
-	"github.com/tommika/gorilla/must"
 	"github.com/tommika/gorilla/xflags"
 )
 
 type Options struct {
 	Debug bool     `flag:"d|debug,Enable debug logging"`
+	Route string   `flag:"r|route,Plan a route visiting the waypoints of all files and write it in GPX format to this file"`
 	Files []string `flag:"*,<file.gpx> ..."`
 }
 
@@ -41,14 +41,37 @@ func main() {
 		fmt.Fprintf(os.Stderr, "options: %+v\n", opts)
 	}
 	errors := 0
+	waypoints := []gpx.Waypoint{}
 
 	for _, file := range opts.Files {
 		if doc, err := gpx.ReadGpxDocument(file); err != nil {
 			errors += 1
 			fmt.Fprintf(os.Stderr, "error %s\n", err)
 		} else {
-			summary := must.NotBeAnError(json.MarshalIndent(doc.Summarize(), "", "  "))
-			fmt.Print(string(summary))
+			if summary, err := json.MarshalIndent(doc.Summarize(), "", "  "); err != nil {
+				// e.g., the summary of a document without tracks has no
+				// elevations (NaN), which can't be encoded
+				fmt.Fprintf(os.Stderr, "%s: no summary: %s\n", file, err)
+			} else {
+				fmt.Print(string(summary))
+			}
+			waypoints = append(waypoints, doc.Wpt...)
+		}
+	}
+	if len(opts.Route) > 0 && len(waypoints) == 0 {
+		fmt.Fprintf(os.Stderr, "route: no waypoints\n")
+	} else if len(opts.Route) > 0 {
+		if plan, err := gpx.PlanRoute(waypoints); err != nil {
+			errors += 1
+			fmt.Fprintf(os.Stderr, "error %s\n", err)
+		} else {
+			fmt.Fprintf(os.Stderr, "route: %d waypoints; distance %.0fm (was %.0fm)\n",
+				len(waypoints), plan.Distance, plan.OriginalDistance)
+			doc := gpx.Document{Creator: "gorilla", Rte: []gpx.Route{plan.Route}}
+			if err := gpx.WriteGpxDocument(opts.Route, &doc); err != nil {
+				errors += 1
+				fmt.Fprintf(os.Stderr, "error %s\n", err)
+			}
 		}
 	}
 	if errors > 0 {
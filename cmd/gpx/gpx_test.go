@@ -3,9 +3,11 @@ package main
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/geo/gpx"
 )
 
 func setup() func() {
@@ -31,6 +33,43 @@ func Test(t *testing.T) {
 	assert.Equal(t, 0, exitCode)
 }
 
+func TestRoute(t *testing.T) {
+	defer setup()()
+	route := filepath.Join(t.TempDir(), "route.gpx")
+	os.Args = []string{
+		"gpx",
+		"--route=" + route,
+		"../.././geo/gpx/test-data/Waypoints.gpx",
+		"../.././geo/gpx/test-data/Test1.gpx",
+	}
+	main()
+	assert.Equal(t, 0, exitCode)
+	doc, err := gpx.ReadGpxDocument(route)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(doc.Rte))
+	assert.Equal(t, 10, len(doc.Rte[0].Rtept))
+
+	os.Args = []string{
+		"gpx",
+		"--route=" + filepath.Join(route, "bogus"),
+		"../.././geo/gpx/test-data/Test1.gpx",
+	}
+	main()
+	assert.Equal(t, 2, exitCode)
+
+	// no waypoints; no route is written
+	exitCode = 0
+	os.Args = []string{
+		"gpx",
+		"--route=" + route + ".none",
+		"../.././geo/gpx/test-data/FortHillLoop.gpx",
+	}
+	main()
+	assert.Equal(t, 0, exitCode)
+	_, err = os.Stat(route + ".none")
+	assert.True(t, os.IsNotExist(err))
+}
+
 func TestDebugEnabled(t *testing.T) {
 	defer setup()()
 	os.Args = []string{
@@ -6,6 +6,7 @@ package discogs
 import (
 	"fmt"
 	"io"
+	"iter"
 	"log"
 	"os"
 	"strings"
@@ -75,12 +76,99 @@ func (d *Discogs) RelatedArtists(from ArtistId, maxDepth int, cb func(id ArtistI
 	})
 }
 
+// RelatedArtistsWithDistance visits the artists related to an artist, to the
+// given depth, nearest first, along with the distance (number of hops) of each
+// from the artist. The artist itself is visited first, at a distance of zero.
+func (d *Discogs) RelatedArtistsWithDistance(from ArtistId, maxDepth int, cb func(id ArtistId, distance int)) {
+	bft := d.aG.BFS(from, maxDepth)
+	bft.VisitNodes(func(id ArtistId) {
+		distance, _ := bft.Depth(id)
+		cb(id, distance)
+	})
+}
+
+// EgoNetwork returns the network of artists within the given distance of an
+// artist (i.e., the artist's ego network.)
+func (d *Discogs) EgoNetwork(id ArtistId, maxDepth int) *graph.Graph[ArtistId, uint8] {
+	return d.aG.EgoNetwork(id, maxDepth)
+}
+
+// WriteEgoNetworkDOT writes the ego network of an artist in the Graphviz DOT
+// language, labeling each artist with their name.
+func (d *Discogs) WriteEgoNetworkDOT(out io.Writer, id ArtistId, maxDepth int) error {
+	return d.EgoNetwork(id, maxDepth).WriteDOT(out, graph.ExportOptions[ArtistId, uint8]{
+		Name: d.ArtistName(id),
+		NodeAttrs: func(n ArtistId) map[string]string {
+			attrs := map[string]string{"label": d.ArtistName(n)}
+			if n == id {
+				attrs["style"], attrs["fillcolor"] = "filled", "gold"
+			}
+			return attrs
+		},
+	})
+}
+
+// PathsBetweenArtists returns an iterator over the chains of related artists
+// connecting two artists, shortest first, along with the number of links in
+// each chain. Each chain visits an artist at most once.
+func (d *Discogs) PathsBetweenArtists(from, to ArtistId) iter.Seq2[[]ArtistId, int] {
+	return func(yield func([]ArtistId, int) bool) {
+		for path := range d.aG.ShortestSimplePaths(from, to) {
+			if !yield(path, len(path)-1) {
+				return
+			}
+		}
+	}
+}
+
 func (d *Discogs) PathBetweenArtists(from, to ArtistId, maxDepth int) []ArtistId {
 	bft := d.aG.BFS(from, maxDepth)
 	path, _ := bft.FindPath(to)
 	return path
 }
 
+// InfluentialArtists returns the k most influential artists, ranked by their
+// PageRank in the graph of related artists.
+func (d *Discogs) InfluentialArtists(k int) []graph.Ranked[ArtistId, float64] {
+	rank, err := d.aG.PageRank(graph.PageRankOptions{})
+	if err != nil {
+		log.Printf("%v", err)
+	}
+	return graph.TopK(rank, k)
+}
+
+// statsSamples is the number of artists from which the diameter of the artist
+// network is estimated.
+const statsSamples = 16
+
+// Stats computes structural statistics of the artist network. The diameter is
+// estimated, as the network can be very large.
+func (d *Discogs) Stats() graph.Stats {
+	return d.aG.Stats(statsSamples, 1)
+}
+
+// Scenes clusters the artists into scenes (communities of related artists),
+// using Louvain modularity optimization.
+func (d *Discogs) Scenes() graph.Communities[ArtistId] {
+	return d.aG.Louvain()
+}
+
+// Bipartition divides the artists into two sides, such that artists are only
+// related to artists on the other side; e.g., members on one side, and groups
+// on the other. This isn't possible if, for example, a group is itself a member
+// of another group that shares one of its members; in that case, the artists
+// forming such a cycle (of odd length) are returned instead.
+func (d *Discogs) Bipartition() (left, right []ArtistId, oddCycle []ArtistId) {
+	return d.aG.Bipartition()
+}
+
+// MemberGroupMatching pairs artists with related artists (e.g., members with
+// groups) such that no artist is in more than one pair, and the number of
+// pairs is as large as possible.
+func (d *Discogs) MemberGroupMatching() ([]graph.Arc[ArtistId], error) {
+	return d.aG.MaximumMatching()
+}
+
 func (d *Discogs) ImportArtists(fileName string) error {
 	errors := 0
 	if in, err := os.Open(fileName); err != nil {
@@ -3,6 +3,7 @@ package discogs
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/tommika/gorilla/assert"
@@ -27,7 +28,94 @@ func Test(t *testing.T) {
 	for _, id := range path {
 		fmt.Printf("\t%s\n", d.ArtistName(id))
 	}
+}
+
+func TestInfluentialArtists(t *testing.T) {
+	d := NewDiscogs()
+	err := d.ImportArtists("./test-data/discogs-artists-xtc.xml")
+	assert.Nil(t, err)
+	top := d.InfluentialArtists(2)
+	assert.Equal(t, 2, len(top))
+	assert.True(t, top[0].Score >= top[1].Score)
+	for _, r := range top {
+		t.Logf("%s (%.3f)", d.ArtistName(r.Node), r.Score)
+	}
+}
+
+func TestScenes(t *testing.T) {
+	d := NewDiscogs()
+	err := d.ImportArtists("./test-data/discogs-artists-xtc.xml")
+	assert.Nil(t, err)
+	scenes := d.Scenes()
+	assert.True(t, scenes.Count() > 0)
+	scene, found := scenes.Community(15118)
+	assert.True(t, found)
+	t.Logf("scene of XTC: %v (modularity=%.3f)", scenes.Members()[scene], scenes.Modularity())
+}
+
+func TestBipartition(t *testing.T) {
+	d := NewDiscogs()
+	err := d.ImportArtists("./test-data/discogs-artists-xtc.xml")
+	assert.Nil(t, err)
+	left, right, oddCycle := d.Bipartition()
+	assert.Nil(t, oddCycle)
+	assert.Equal(t, d.ArtistNodeCount(), len(left)+len(right))
+	matching, err := d.MemberGroupMatching()
+	assert.Nil(t, err)
+	assert.True(t, len(matching) > 0)
+}
+
+func TestPathsBetweenArtists(t *testing.T) {
+	d := NewDiscogs()
+	err := d.ImportArtists("./test-data/discogs-artists-xtc.xml")
+	assert.Nil(t, err)
+	count := 0
+	for path, length := range d.PathsBetweenArtists(316130, 229492) {
+		if count == 0 {
+			assert.Equal(t, 3, len(path))
+			assert.Equal(t, 2, length)
+		}
+		count++
+	}
+	assert.True(t, count > 0)
+}
+
+func TestEgoNetwork(t *testing.T) {
+	d := NewDiscogs()
+	err := d.ImportArtists("./test-data/discogs-artists-xtc.xml")
+	assert.Nil(t, err)
+	ego := d.EgoNetwork(15118, 1)
+	assert.True(t, ego.HasNode(229492))
+	dot := strings.Builder{}
+	assert.Nil(t, d.WriteEgoNetworkDOT(&dot, 15118, 1))
+	assert.True(t, strings.Contains(dot.String(), `"label"="Andy Partridge"`))
+}
+
+func TestStats(t *testing.T) {
+	d := NewDiscogs()
+	err := d.ImportArtists("./test-data/discogs-artists-xtc.xml")
+	assert.Nil(t, err)
+	stats := d.Stats()
+	assert.Equal(t, d.ArtistNodeCount(), stats.Nodes)
+	assert.Equal(t, d.ArtistEdgeCount(), stats.Edges)
+	assert.True(t, stats.Diameter >= 2)
+}
 
+func TestRelatedArtists(t *testing.T) {
+	d := NewDiscogs()
+	err := d.ImportArtists("./test-data/discogs-artists-xtc.xml")
+	assert.Nil(t, err)
+	prev, count := 0, 0
+	d.RelatedArtistsWithDistance(316130, 3, func(id ArtistId, distance int) {
+		assert.True(t, distance >= prev && distance <= 3)
+		prev = distance
+		count++
+	})
+	related := 0
+	d.RelatedArtists(316130, 3, func(id ArtistId) {
+		related++
+	})
+	assert.Equal(t, count, related)
 }
 
 func TestFileNotFound(t *testing.T) {
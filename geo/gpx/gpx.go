@@ -21,6 +21,7 @@ var (
 type Document struct {
 	Creator string `x:",attr" json:",omitempty"`
 	Wpt     []Waypoint
+	Rte     []Route
 	Trk     []Track
 }
 
@@ -33,6 +34,12 @@ type Waypoint struct {
 	Desc *string `json:",omitempty"`
 }
 
+// Route is an ordered list of waypoints leading to a destination
+type Route struct {
+	Name  *string `json:",omitempty"`
+	Rtept []Waypoint
+}
+
 type Track struct {
 	Trkseg []TrackSegment
 }
@@ -2,7 +2,12 @@
 package gpx
 
 import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"github.com/tommika/gorilla/xxml"
 )
@@ -23,3 +28,75 @@ func ReadGpxDocumentFromStream(in *os.File) (*Document, error) {
 	err := xxml.ReadXmlWithRootName(in, &gpx, GPX_NAME)
 	return &gpx, err
 }
+
+// WriteGpxDocument writes a GPX document to the given named file.
+func WriteGpxDocument(path string, doc *Document) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = WriteGpxDocumentToStream(file, doc); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteGpxDocumentToStream writes a GPX (version 1.1) document to the given
+// output stream.
+func WriteGpxDocumentToStream(out io.Writer, doc *Document) error {
+	w := bufio.NewWriter(out)
+	fmt.Fprintf(w, "%s", xml.Header)
+	fmt.Fprintf(w, "<gpx xmlns=%q version=\"1.1\" creator=\"", GPX_1_1_NS)
+	xml.EscapeText(w, []byte(doc.Creator))
+	fmt.Fprintf(w, "\">\n")
+	for i := range doc.Wpt {
+		writeWaypoint(w, "  ", "wpt", &doc.Wpt[i], false)
+	}
+	for _, rte := range doc.Rte {
+		fmt.Fprintf(w, "  <rte>\n")
+		writeOptional(w, "    ", "name", rte.Name)
+		for i := range rte.Rtept {
+			writeWaypoint(w, "    ", "rtept", &rte.Rtept[i], false)
+		}
+		fmt.Fprintf(w, "  </rte>\n")
+	}
+	for _, trk := range doc.Trk {
+		fmt.Fprintf(w, "  <trk>\n")
+		for _, trkseg := range trk.Trkseg {
+			fmt.Fprintf(w, "    <trkseg>\n")
+			for i := range trkseg.Trkpt {
+				writeWaypoint(w, "      ", "trkpt", &trkseg.Trkpt[i], true)
+			}
+			fmt.Fprintf(w, "    </trkseg>\n")
+		}
+		fmt.Fprintf(w, "  </trk>\n")
+	}
+	fmt.Fprintf(w, "</gpx>\n")
+	return w.Flush()
+}
+
+// writeWaypoint writes a waypoint as an element with the given name. The time
+// is omitted when it's zero, as is the elevation, unless withEle is set (as it
+// is for track points, where an elevation of zero is sea level, rather than
+// missing.)
+func writeWaypoint(w *bufio.Writer, indent, elem string, wp *Waypoint, withEle bool) {
+	fmt.Fprintf(w, "%s<%s lat=\"%v\" lon=\"%v\">\n", indent, elem, wp.Lat, wp.Lon)
+	if withEle || wp.Ele != 0 {
+		fmt.Fprintf(w, "%s  <ele>%v</ele>\n", indent, wp.Ele)
+	}
+	if !wp.Time.IsZero() {
+		fmt.Fprintf(w, "%s  <time>%s</time>\n", indent, wp.Time.UTC().Format(time.RFC3339))
+	}
+	writeOptional(w, indent+"  ", "name", wp.Name)
+	writeOptional(w, indent+"  ", "desc", wp.Desc)
+	fmt.Fprintf(w, "%s</%s>\n", indent, elem)
+}
+
+func writeOptional(w *bufio.Writer, indent, elem string, val *string) {
+	if val != nil {
+		fmt.Fprintf(w, "%s<%s>", indent, elem)
+		xml.EscapeText(w, []byte(*val))
+		fmt.Fprintf(w, "</%s>\n", elem)
+	}
+}
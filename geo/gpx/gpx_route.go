@@ -0,0 +1,52 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package gpx
+
+// Plan a route visiting a set of waypoints
+
+import (
+	"github.com/tommika/gorilla/algorithms/tsp"
+)
+
+// RoutePlan is a route that visits a set of waypoints, along with the
+// distance (in meters) of the route, and of a route that visits the waypoints
+// in their original order.
+type RoutePlan struct {
+	Route            Route
+	Distance         float64
+	OriginalDistance float64
+}
+
+// PlanRoute finds a short route that visits each of the document's waypoints,
+// starting and ending at the first waypoint (i.e., a solution to the
+// traveling salesman problem.) Distances are haversine distances between the
+// waypoints. If the document has no waypoints, the route is empty. Returns
+// an error if the distance between two waypoints can't be computed (e.g., a
+// waypoint has invalid coordinates.)
+func (doc *Document) PlanRoute() (RoutePlan, error) {
+	return PlanRoute(doc.Wpt)
+}
+
+// PlanRoute finds a short route that visits each of the given waypoints,
+// starting and ending at the first waypoint.
+func PlanRoute(waypoints []Waypoint) (plan RoutePlan, err error) {
+	if len(waypoints) == 0 {
+		return
+	}
+	p, err := tsp.NewProblem(waypoints, func(a, b Waypoint) float64 {
+		return haversineDistance(&a, &b)
+	})
+	if err != nil {
+		return
+	}
+	original := make([]int, len(waypoints))
+	for i := range original {
+		original[i] = i
+	}
+	tour := p.Solve()
+	for _, i := range append(tour, tour[0]) {
+		plan.Route.Rtept = append(plan.Route.Rtept, waypoints[i])
+	}
+	plan.Distance = p.TourLength(tour)
+	plan.OriginalDistance = p.TourLength(original)
+	return
+}
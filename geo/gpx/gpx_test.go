@@ -3,6 +3,9 @@ package gpx
 
 import (
 	"encoding/json"
+	"math"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/tommika/gorilla/assert"
@@ -62,3 +65,79 @@ func TestLoop(t *testing.T) {
 
 func TestSummaryWithBadTrack_TODO(t *testing.T) {
 }
+
+func TestWriteGpxDocument(t *testing.T) {
+	doc, err := ReadGpxDocument("./test-data/BeaconToColdSpring.gpx")
+	assert.Nil(t, err)
+	name := "Beacon <to> Cold Spring"
+	doc.Rte = []Route{{Name: &name, Rtept: doc.Wpt}}
+	path := filepath.Join(t.TempDir(), "out.gpx")
+	assert.Nil(t, WriteGpxDocument(path, doc))
+	doc2, err := ReadGpxDocument(path)
+	assert.Nil(t, err)
+	assert.DeepEqual(t, doc.Wpt, doc2.Wpt)
+	assert.Equal(t, 1, len(doc2.Rte))
+	assert.Equal(t, name, *doc2.Rte[0].Name)
+	assert.DeepEqual(t, doc.Wpt, doc2.Rte[0].Rtept)
+	assert.DeepEqual(t, doc.Trk, doc2.Trk)
+	assert.DeepEqual(t, doc.Summarize(), doc2.Summarize())
+
+	assert.NotNil(t, WriteGpxDocument(filepath.Join(path, "bogus"), doc))
+}
+
+func TestWriteGpxDocumentSeaLevel(t *testing.T) {
+	doc := &Document{Creator: "A & B", Trk: []Track{{Trkseg: []TrackSegment{{Trkpt: []Waypoint{
+		{Lat: 40.5, Lon: -74, Ele: 0},
+		{Lat: 40.6, Lon: -74, Ele: 10},
+	}}}}}}
+	out := strings.Builder{}
+	assert.Nil(t, WriteGpxDocumentToStream(&out, doc))
+	t.Logf("\n%s", out.String())
+	assert.Equal(t, 2, strings.Count(out.String(), "<ele>"))
+	assert.True(t, strings.Contains(out.String(), "<ele>0</ele>"))
+	assert.True(t, strings.Contains(out.String(), `creator="A &amp; B"`))
+}
+
+func TestPlanRoute(t *testing.T) {
+	// waypoints on a 4x4 grid, about 100 meters apart, in a scrambled order
+	waypoints := []Waypoint{}
+	for i := range 16 {
+		j := (i * 7) % 16
+		waypoints = append(waypoints, Waypoint{
+			Lat: 41.42 + float64(j/4)*0.0009,
+			Lon: -73.95 + float64(j%4)*0.0012,
+		})
+	}
+	doc := Document{Wpt: waypoints}
+	plan, err := doc.PlanRoute()
+	assert.Nil(t, err)
+	logJson(t, plan)
+	assert.Equal(t, 17, len(plan.Route.Rtept))
+	assert.Equal(t, waypoints[0], plan.Route.Rtept[0])
+	assert.Equal(t, waypoints[0], plan.Route.Rtept[16])
+	assert.True(t, plan.Distance < plan.OriginalDistance/2)
+	// the shortest route around the grid is 16 steps of ~100 meters
+	assert.EqualEpsilon(t, 1600, plan.Distance, 50)
+	route := 0.0
+	for i := 1; i < len(plan.Route.Rtept); i++ {
+		route += haversineDistance(&plan.Route.Rtept[i-1], &plan.Route.Rtept[i])
+	}
+	assert.EqualEpsilon(t, plan.Distance, route, 1e-6)
+
+	doc2, err := ReadGpxDocument("./test-data/Waypoints.gpx")
+	assert.Nil(t, err)
+	plan, err = doc2.PlanRoute()
+	assert.Nil(t, err)
+	assert.Equal(t, 9, len(plan.Route.Rtept))
+	assert.Equal(t, "Cold Spring Station", *plan.Route.Rtept[0].Name)
+	assert.True(t, plan.Distance < plan.OriginalDistance)
+
+	plan, err = PlanRoute(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(plan.Route.Rtept))
+	assert.Equal(t, 0.0, plan.Distance)
+
+	_, err = PlanRoute(append(waypoints[:2:2], Waypoint{Lat: math.NaN(), Lon: -73.95}))
+	t.Log(err)
+	assert.NotNil(t, err)
+}
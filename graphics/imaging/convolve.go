@@ -0,0 +1,148 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package imaging
+
+import (
+	"math"
+
+	"github.com/tommika/gorilla/algorithms/matrix"
+)
+
+// Convolve returns the convolution of an image (channel) with a kernel, which
+// should have odd dimensions, so that it's centered on each pixel. Pixels
+// beyond the edges of the image take the value of the nearest edge pixel.
+func Convolve(m, kernel matrix.Matrix[float64]) matrix.Matrix[float64] {
+	rows, cols := m.NumRows(), m.NumCols()
+	kRows, kCols := kernel.NumRows(), kernel.NumCols()
+	cr, cc := kRows/2, kCols/2
+	src, k := m.Rows(), kernel.Rows()
+	out := matrix.NewMatrix(rows, cols, 0.0)
+	if rows == 0 || cols == 0 {
+		return out
+	}
+	for y, row := range out.Rows() {
+		for x := range row {
+			sum := 0.0
+			for i := range kRows {
+				sy := clamp(y+cr-i, 0, rows-1)
+				for j := range kCols {
+					sum += k[i][j] * src[sy][clamp(x+cc-j, 0, cols-1)]
+				}
+			}
+			row[x] = sum
+		}
+	}
+	return out
+}
+
+// GaussianKernel returns a normalized (summing to one) Gaussian kernel with
+// the given standard deviation, extending three standard deviations from
+// the center.
+func GaussianKernel(sigma float64) matrix.Matrix[float64] {
+	g := gaussian(sigma)
+	k := matrix.NewMatrix(len(g), len(g), 0.0)
+	for i, gi := range g {
+		for j, gj := range g {
+			k.Set(i, j, gi*gj)
+		}
+	}
+	return k
+}
+
+// GaussianBlur blurs an image (channel) by convolving it with a Gaussian
+// kernel with the given standard deviation. As the kernel is separable, this
+// is done by convolving with a one-dimensional kernel in each direction,
+// which is much faster than convolving with GaussianKernel.
+func GaussianBlur(m matrix.Matrix[float64], sigma float64) matrix.Matrix[float64] {
+	g := gaussian(sigma)
+	row := matrix.NewMatrix(1, len(g), 0.0)
+	copy(row.Rows()[0], g)
+	return Convolve(Convolve(m, row), row.Transpose())
+}
+
+// SharpenKernel returns a 3x3 kernel that sharpens an image, by subtracting
+// the neighbors of each pixel from it.
+func SharpenKernel() matrix.Matrix[float64] {
+	return kernel3x3(
+		0, -1, 0,
+		-1, 5, -1,
+		0, -1, 0)
+}
+
+// Sharpen sharpens an image (channel).
+func Sharpen(m matrix.Matrix[float64]) matrix.Matrix[float64] {
+	return Convolve(m, SharpenKernel())
+}
+
+// SobelKernels returns the Sobel kernels, which estimate the gradient of an
+// image in the x (left to right) and y (top to bottom) directions.
+func SobelKernels() (x, y matrix.Matrix[float64]) {
+	x = kernel3x3(
+		1, 0, -1,
+		2, 0, -2,
+		1, 0, -1)
+	return x, x.Transpose()
+}
+
+// Sobel detects edges in an image (channel), using the Sobel operator.
+// Returns the magnitude of the gradient of each pixel, which is large at
+// edges, and zero where the image is uniform. The magnitude can exceed one;
+// see Normalize.
+func Sobel(m matrix.Matrix[float64]) matrix.Matrix[float64] {
+	kx, ky := SobelKernels()
+	gx, gy := Convolve(m, kx), Convolve(m, ky)
+	out := matrix.NewMatrix(m.NumRows(), m.NumCols(), 0.0)
+	for y, row := range out.Rows() {
+		for x := range row {
+			row[x] = math.Hypot(gx.Get(y, x), gy.Get(y, x))
+		}
+	}
+	return out
+}
+
+// Normalize linearly scales the values of an image (channel) to the range
+// [0,1]. A uniform image is unchanged.
+func Normalize(m matrix.Matrix[float64]) matrix.Matrix[float64] {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for v := range m.Values() {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	out := m.Clone()
+	if hi <= lo {
+		return out
+	}
+	for _, row := range out.Rows() {
+		for x, v := range row {
+			row[x] = (v - lo) / (hi - lo)
+		}
+	}
+	return out
+}
+
+// gaussian returns a normalized one-dimensional Gaussian kernel. If sigma
+// isn't positive, the kernel has the single value one (so leaves an image
+// unchanged.)
+func gaussian(sigma float64) []float64 {
+	if sigma <= 0 {
+		return []float64{1}
+	}
+	radius := int(math.Ceil(3 * sigma))
+	g := make([]float64, 2*radius+1)
+	sum := 0.0
+	for i := range g {
+		d := float64(i - radius)
+		g[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += g[i]
+	}
+	for i := range g {
+		g[i] /= sum
+	}
+	return g
+}
+
+func kernel3x3(values ...float64) matrix.Matrix[float64] {
+	k := matrix.NewMatrix(3, 3, 0.0)
+	for i, v := range values {
+		k.Set(i/3, i%3, v)
+	}
+	return k
+}
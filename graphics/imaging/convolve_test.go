@@ -0,0 +1,106 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package imaging
+
+import (
+	"testing"
+
+	"github.com/tommika/gorilla/algorithms/matrix"
+	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/must"
+)
+
+const epsilon = 1e-9
+
+func sum(m matrix.Matrix[float64]) (s float64) {
+	for v := range m.Values() {
+		s += v
+	}
+	return
+}
+
+func TestConvolve(t *testing.T) {
+	m := matrix.NewMatrix(5, 5, 0.0)
+	m.Set(2, 2, 1)
+	// convolving an impulse with a kernel gives the kernel
+	k := kernel3x3(1, 2, 3, 4, 5, 6, 7, 8, 9)
+	out := Convolve(m, k)
+	assert.True(t, matrix.Equal(k, out.Sub(1, 4, 1, 4).Clone(), epsilon))
+	assert.Equal(t, 45.0, sum(out))
+
+	// the identity kernel, and edge handling
+	g := gradient(3, 4)
+	assert.True(t, matrix.Equal(g, Convolve(g, kernel3x3(0, 0, 0, 0, 1, 0, 0, 0, 0)), epsilon))
+	shifted := Convolve(g, kernel3x3(0, 0, 0, 1, 0, 0, 0, 0, 0))
+	assert.EqualEpsilon(t, g.Get(1, 3), shifted.Get(1, 2), epsilon)
+	assert.EqualEpsilon(t, g.Get(0, 3), shifted.Get(0, 3), epsilon)
+
+	assert.Equal(t, 0, Convolve(matrix.NewMatrix(0, 0, 0.0), k).NumRows())
+}
+
+func TestGaussian(t *testing.T) {
+	k := GaussianKernel(1)
+	assert.Equal(t, 7, k.NumRows())
+	assert.EqualEpsilon(t, 1, sum(k), epsilon)
+	assert.True(t, matrix.Equal(k, k.Transpose(), epsilon))
+	center := k.Get(3, 3)
+	for v := range k.Values() {
+		assert.True(t, v <= center)
+	}
+	assert.Equal(t, 1, GaussianKernel(0).NumRows())
+
+	img := FromGray(must.NotBeAnError(ReadImage(testImage)))
+	blurred := GaussianBlur(img, 1.5)
+	assert.True(t, matrix.Equal(Convolve(img, GaussianKernel(1.5)), blurred, 1e-9))
+	// blurring preserves the mean, and reduces the variation
+	assert.EqualEpsilon(t, sum(img), sum(blurred), 1e-3*sum(img))
+	assert.True(t, sum(Sobel(blurred)) < sum(Sobel(img)))
+	assert.True(t, matrix.Equal(img, GaussianBlur(img, 0), 0))
+}
+
+func TestSharpen(t *testing.T) {
+	// sharpening leaves uniform regions (and linear gradients) unchanged
+	g := gradient(5, 5)
+	assert.True(t, matrix.Equal(g.Sub(0, 5, 1, 4).Clone(), Sharpen(g).Sub(0, 5, 1, 4).Clone(), epsilon))
+	step := matrix.NewMatrix(3, 4, 0.25)
+	step.Sub(0, 3, 2, 4).SetAll(0.75)
+	s := Sharpen(step)
+	// the contrast at the edge is increased
+	assert.EqualEpsilon(t, -0.25, s.Get(1, 1), epsilon)
+	assert.EqualEpsilon(t, 1.25, s.Get(1, 2), epsilon)
+	assert.EqualEpsilon(t, 0.25, s.Get(1, 0), epsilon)
+}
+
+func TestSobel(t *testing.T) {
+	kx, ky := SobelKernels()
+	assert.True(t, matrix.Equal(kx, ky.Transpose(), 0))
+
+	// a vertical edge
+	step := matrix.NewMatrix(5, 6, 0.0)
+	step.Sub(0, 5, 3, 6).SetAll(1)
+	edges := Sobel(step)
+	for y := range 5 {
+		assert.Equal(t, 0.0, edges.Get(y, 0))
+		assert.Equal(t, 4.0, edges.Get(y, 2))
+		assert.Equal(t, 4.0, edges.Get(y, 3))
+		assert.Equal(t, 0.0, edges.Get(y, 5))
+	}
+	// the gradient points left to right
+	assert.Equal(t, 4.0, Convolve(step, kx).Get(2, 2))
+	assert.Equal(t, 0.0, Convolve(step, ky).Get(2, 2))
+	assert.Equal(t, 4.0, Convolve(step.Transpose(), ky).Get(2, 2))
+
+	n := Normalize(edges)
+	assert.Equal(t, 1.0, n.Get(0, 2))
+	assert.Equal(t, 0.0, n.Get(0, 0))
+	assert.True(t, matrix.Equal(step, Normalize(step), 0))
+	uniform := matrix.NewMatrix(2, 2, 3.0)
+	assert.True(t, matrix.Equal(uniform, Normalize(uniform), 0))
+}
+
+func BenchmarkGaussianBlur(b *testing.B) {
+	img := FromGray(must.NotBeAnError(ReadImage(testImage)))
+	b.ResetTimer()
+	for range b.N {
+		GaussianBlur(img, 2)
+	}
+}
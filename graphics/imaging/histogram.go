@@ -0,0 +1,53 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package imaging
+
+import (
+	"github.com/tommika/gorilla/algorithms/matrix"
+)
+
+// HistogramBins is the number of bins used by Histogram and Equalize: one for
+// each level of an 8-bit channel.
+const HistogramBins = 256
+
+// Histogram returns the number of pixels of an image (channel) at each level,
+// from 0 to HistogramBins-1. Values are clamped to [0,1].
+func Histogram(m matrix.Matrix[float64]) []int {
+	h := make([]int, HistogramBins)
+	for v := range m.Values() {
+		h[level(v)]++
+	}
+	return h
+}
+
+// Equalize improves the contrast of an image (channel) using histogram
+// equalization: each value is mapped to the fraction of pixels at or below
+// its level, so the levels of the result are spread (roughly) uniformly
+// over [0,1].
+func Equalize(m matrix.Matrix[float64]) matrix.Matrix[float64] {
+	h := Histogram(m)
+	// the cumulative distribution, scaled so the lowest level used maps to 0
+	cdf := make([]int, HistogramBins)
+	total, lowest := 0, 0
+	for i, n := range h {
+		total += n
+		cdf[i] = total
+		if lowest == 0 {
+			lowest = total
+		}
+	}
+	out := m.Clone()
+	if total == lowest {
+		// uniform (or empty) image
+		return out
+	}
+	for _, row := range out.Rows() {
+		for x, v := range row {
+			row[x] = float64(cdf[level(v)]-lowest) / float64(total-lowest)
+		}
+	}
+	return out
+}
+
+func level(v float64) int {
+	return int(toByte(v))
+}
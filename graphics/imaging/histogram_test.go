@@ -0,0 +1,57 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package imaging
+
+import (
+	"testing"
+
+	"github.com/tommika/gorilla/algorithms/matrix"
+	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/must"
+)
+
+func TestHistogram(t *testing.T) {
+	m := gradient(2, 3)
+	m.Set(1, 2, 2)
+	h := Histogram(m)
+	assert.Equal(t, HistogramBins, len(h))
+	assert.Equal(t, 2, h[0])
+	assert.Equal(t, 2, h[128])
+	assert.Equal(t, 2, h[255])
+}
+
+func TestEqualize(t *testing.T) {
+	// a low-contrast image, with values in [0.4,0.6]
+	m := matrix.NewMatrix(10, 10, 0.0)
+	i := 0
+	for y := range 10 {
+		for x := range 10 {
+			m.Set(y, x, 0.4+0.2*float64(i)/99)
+			i++
+		}
+	}
+	e := Equalize(m)
+	lo, hi := 1.0, 0.0
+	for v := range e.Values() {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	assert.Equal(t, 0.0, lo)
+	assert.Equal(t, 1.0, hi)
+	// the order of values is preserved
+	assert.True(t, e.Get(0, 0) < e.Get(5, 0) && e.Get(5, 0) < e.Get(9, 9))
+	// roughly uniform: about half the pixels are below the middle
+	below := 0
+	for v := range e.Values() {
+		if v < 0.5 {
+			below++
+		}
+	}
+	assert.True(t, below >= 45 && below <= 55)
+
+	uniform := matrix.NewMatrix(3, 3, 0.3)
+	assert.True(t, matrix.Equal(uniform, Equalize(uniform), 0))
+	assert.Equal(t, 0, Equalize(matrix.NewMatrix(0, 0, 0.0)).NumRows())
+
+	img := FromGray(must.NotBeAnError(ReadImage(testImage)))
+	h := Histogram(Equalize(img))
+	assert.True(t, h[255] > 0)
+}
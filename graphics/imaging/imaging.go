@@ -0,0 +1,153 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package imaging
+
+// Image processing, with images represented as matrices: one matrix per
+// channel, with a cell per pixel, and values from 0 (black, or transparent)
+// to 1 (full intensity, or opaque.) Cell (r, c) of a channel is the pixel at
+// x=c, y=r, relative to the image's bounds.
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+
+	"github.com/tommika/gorilla/algorithms/matrix"
+)
+
+// Channels are the red, green, blue and alpha channels of an image. Colors are
+// not premultiplied by alpha.
+type Channels struct {
+	R, G, B, A matrix.Matrix[float64]
+}
+
+// FromImage splits an image into its channels.
+func FromImage(img image.Image) Channels {
+	bounds := img.Bounds()
+	rows, cols := bounds.Dy(), bounds.Dx()
+	c := Channels{
+		R: matrix.NewMatrix(rows, cols, 0.0),
+		G: matrix.NewMatrix(rows, cols, 0.0),
+		B: matrix.NewMatrix(rows, cols, 0.0),
+		A: matrix.NewMatrix(rows, cols, 0.0),
+	}
+	for y := range rows {
+		for x := range cols {
+			r, g, b, a := nrgba(img.At(bounds.Min.X+x, bounds.Min.Y+y))
+			c.R.Set(y, x, r)
+			c.G.Set(y, x, g)
+			c.B.Set(y, x, b)
+			c.A.Set(y, x, a)
+		}
+	}
+	return c
+}
+
+// ToImage combines the channels into an image. Values are clamped to [0,1].
+func (c Channels) ToImage() *image.NRGBA {
+	rows, cols := c.R.NumRows(), c.R.NumCols()
+	img := image.NewNRGBA(image.Rect(0, 0, cols, rows))
+	for y := range rows {
+		for x := range cols {
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: toByte(c.R.Get(y, x)),
+				G: toByte(c.G.Get(y, x)),
+				B: toByte(c.B.Get(y, x)),
+				A: toByte(c.A.Get(y, x)),
+			})
+		}
+	}
+	return img
+}
+
+// Map applies the given function to each channel (including alpha), and
+// returns the resulting channels; e.g., to blur or resize a color image.
+func (c Channels) Map(f func(m matrix.Matrix[float64]) matrix.Matrix[float64]) Channels {
+	return Channels{R: f(c.R), G: f(c.G), B: f(c.B), A: f(c.A)}
+}
+
+// Gray converts the channels to grayscale, as the luminance of each pixel
+// (ignoring alpha.)
+func (c Channels) Gray() matrix.Matrix[float64] {
+	rows, cols := c.R.NumRows(), c.R.NumCols()
+	m := matrix.NewMatrix(rows, cols, 0.0)
+	for y := range rows {
+		for x := range cols {
+			m.Set(y, x, luminance(c.R.Get(y, x), c.G.Get(y, x), c.B.Get(y, x)))
+		}
+	}
+	return m
+}
+
+// FromGray converts an image to grayscale, as the luminance of each pixel
+// (ignoring alpha.)
+func FromGray(img image.Image) matrix.Matrix[float64] {
+	return FromImage(img).Gray()
+}
+
+// ToGray creates a grayscale image from a matrix. Values are clamped to
+// [0,1].
+func ToGray(m matrix.Matrix[float64]) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, m.NumCols(), m.NumRows()))
+	for y := range m.NumRows() {
+		for x := range m.NumCols() {
+			img.SetGray(x, y, color.Gray{Y: toByte(m.Get(y, x))})
+		}
+	}
+	return img
+}
+
+// ReadImage reads an image from the given file. PNG is supported, along with
+// any other format whose decoder has been registered (see image.Decode.)
+func ReadImage(path string) (image.Image, error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	img, _, err := image.Decode(in)
+	return img, err
+}
+
+// WritePNG writes an image to the given file, in PNG format.
+func WritePNG(path string, img image.Image) error {
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = png.Encode(out, img); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// nrgba returns the components of a color, not premultiplied by alpha.
+// Non-premultiplied colors are used as-is, as converting them to
+// premultiplied colors (and back) loses precision.
+func nrgba(c color.Color) (r, g, b, a float64) {
+	switch c := c.(type) {
+	case color.NRGBA:
+		return float64(c.R) / 0xff, float64(c.G) / 0xff, float64(c.B) / 0xff, float64(c.A) / 0xff
+	case color.NRGBA64:
+		return float64(c.R) / 0xffff, float64(c.G) / 0xffff, float64(c.B) / 0xffff, float64(c.A) / 0xffff
+	}
+	p := color.NRGBA64Model.Convert(c).(color.NRGBA64)
+	return float64(p.R) / 0xffff, float64(p.G) / 0xffff, float64(p.B) / 0xffff, float64(p.A) / 0xffff
+}
+
+// luminance returns the luminance of a color, using the ITU-R BT.601 weights
+// (as color.GrayModel does.)
+func luminance(r, g, b float64) float64 {
+	return 0.299*r + 0.587*g + 0.114*b
+}
+
+// toByte converts a value in [0,1] to a byte, clamping values outside the
+// range.
+func toByte(v float64) uint8 {
+	return uint8(clamp(v, 0, 1)*255 + 0.5)
+}
+
+func clamp[T int | float64](v, lo, hi T) T {
+	return max(lo, min(v, hi))
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"path/filepath"
+	"testing"
+
+	"github.com/tommika/gorilla/algorithms/matrix"
+	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/must"
+)
+
+const testImage = "../../doc/gorilla-icon.png"
+
+// gradient returns an image whose values increase from left to right
+func gradient(rows, cols int) matrix.Matrix[float64] {
+	m := matrix.NewMatrix(rows, cols, 0.0)
+	for y := range rows {
+		for x := range cols {
+			m.Set(y, x, float64(x)/float64(cols-1))
+		}
+	}
+	return m
+}
+
+func TestChannels(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(10, 20, 13, 22))
+	img.SetNRGBA(10, 20, color.NRGBA{255, 0, 0, 255})
+	img.SetNRGBA(12, 21, color.NRGBA{0, 51, 255, 102})
+	c := FromImage(img)
+	assert.Equal(t, 2, c.R.NumRows())
+	assert.Equal(t, 3, c.R.NumCols())
+	assert.Equal(t, 1.0, c.R.Get(0, 0))
+	assert.Equal(t, 1.0, c.A.Get(0, 0))
+	assert.Equal(t, 0.0, c.A.Get(0, 1))
+	assert.Equal(t, 0.2, c.G.Get(1, 2))
+	assert.Equal(t, 1.0, c.B.Get(1, 2))
+	assert.Equal(t, 0.4, c.A.Get(1, 2))
+
+	// the round trip is lossless for 8-bit images, and moves the origin
+	out := c.ToImage()
+	assert.DeepEqual(t, image.Rect(0, 0, 3, 2), out.Bounds())
+	for y := range 2 {
+		for x := range 3 {
+			assert.Equal(t, img.NRGBAAt(10+x, 20+y), out.NRGBAAt(x, y))
+		}
+	}
+
+	gray := c.Gray()
+	assert.EqualEpsilon(t, 0.299, gray.Get(0, 0), 1e-9)
+	assert.EqualEpsilon(t, 0.587*0.2+0.114, gray.Get(1, 2), 1e-9)
+
+	doubled := c.Map(func(m matrix.Matrix[float64]) matrix.Matrix[float64] {
+		return matrix.Scale(m, 2)
+	})
+	assert.Equal(t, 0.8, doubled.A.Get(1, 2))
+	// values are clamped
+	assert.Equal(t, color.NRGBA{0, 102, 255, 204}, doubled.ToImage().NRGBAAt(2, 1))
+}
+
+func TestGray(t *testing.T) {
+	m := gradient(4, 6)
+	m.Set(0, 0, -1)
+	m.Set(0, 1, 2)
+	img := ToGray(m)
+	assert.Equal(t, uint8(0), img.GrayAt(0, 0).Y)
+	assert.Equal(t, uint8(255), img.GrayAt(1, 0).Y)
+	assert.Equal(t, uint8(153), img.GrayAt(3, 2).Y)
+	back := FromGray(img)
+	assert.Equal(t, 0.6, back.Get(2, 3))
+	assert.EqualEpsilon(t, 1.0, back.Get(0, 1), epsilon)
+}
+
+func TestReadWritePNG(t *testing.T) {
+	img := must.NotBeAnError(ReadImage(testImage))
+	c := FromImage(img)
+	assert.Equal(t, img.Bounds().Dy(), c.R.NumRows())
+	path := filepath.Join(t.TempDir(), "gorilla.png")
+	assert.Nil(t, WritePNG(path, c.ToImage()))
+	back := FromImage(must.NotBeAnError(ReadImage(path)))
+	for _, pair := range [][2]matrix.Matrix[float64]{{c.R, back.R}, {c.G, back.G}, {c.B, back.B}, {c.A, back.A}} {
+		assert.True(t, matrix.Equal(pair[0], pair[1], 1.0/255))
+	}
+
+	gray := filepath.Join(t.TempDir(), "edges.png")
+	assert.Nil(t, WritePNG(gray, ToGray(Normalize(Sobel(c.Gray())))))
+	_, isGray := must.NotBeAnError(ReadImage(gray)).(*image.Gray)
+	assert.True(t, isGray)
+
+	_, err := ReadImage("no-such-file.png")
+	assert.NotNil(t, err)
+	assert.NotNil(t, WritePNG(filepath.Join(t.TempDir(), "no-such-dir", "x.png"), img))
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package imaging
+
+import (
+	"math"
+
+	"github.com/tommika/gorilla/algorithms/matrix"
+)
+
+// Interpolation is a method of interpolating between the pixels of an image.
+type Interpolation int
+
+const (
+	// Bilinear interpolates linearly between the four nearest pixels.
+	Bilinear Interpolation = iota
+	// Bicubic interpolates between the sixteen nearest pixels, using cubic
+	// convolution (Keys' kernel, with a = -0.5), which gives sharper results
+	// than bilinear interpolation.
+	Bicubic
+)
+
+// Resize resizes an image (channel) to the given number of rows and columns,
+// using the given interpolation. Pixels are treated as samples at their
+// centers; samples beyond the edges of the image take the value of the
+// nearest edge pixel. When shrinking an image by a large factor, blur it
+// first (see GaussianBlur), to avoid aliasing. Bicubic interpolation can
+// overshoot, giving values slightly outside [0,1].
+func Resize(m matrix.Matrix[float64], rows, cols int, interpolation Interpolation) matrix.Matrix[float64] {
+	out := matrix.NewMatrix(rows, cols, 0.0)
+	if m.NumRows() == 0 || m.NumCols() == 0 {
+		return out
+	}
+	// interpolate each row, then each column
+	rowScale := float64(m.NumRows()) / float64(rows)
+	colScale := float64(m.NumCols()) / float64(cols)
+	tmp := matrix.NewMatrix(m.NumRows(), cols, 0.0)
+	for y, src := range m.Rows() {
+		dst := tmp.Rows()[y]
+		for x := range dst {
+			dst[x] = interpolate(func(i int) float64 { return src[i] }, len(src), (float64(x)+0.5)*colScale-0.5, interpolation)
+		}
+	}
+	src := tmp.Rows()
+	for y, dst := range out.Rows() {
+		sy := (float64(y)+0.5)*rowScale - 0.5
+		for x := range dst {
+			dst[x] = interpolate(func(i int) float64 { return src[i][x] }, len(src), sy, interpolation)
+		}
+	}
+	return out
+}
+
+// interpolate returns the value at position t of a sequence of n samples.
+func interpolate(sample func(i int) float64, n int, t float64, interpolation Interpolation) float64 {
+	at := func(i int) float64 {
+		return sample(clamp(i, 0, n-1))
+	}
+	i := int(math.Floor(t))
+	f := t - float64(i)
+	if interpolation == Bicubic {
+		sum := 0.0
+		for k := -1; k <= 2; k++ {
+			sum += at(i+k) * cubic(float64(k)-f)
+		}
+		return sum
+	}
+	return at(i)*(1-f) + at(i+1)*f
+}
+
+// cubic is Keys' cubic convolution kernel, with a = -0.5.
+func cubic(x float64) float64 {
+	const a = -0.5
+	x = math.Abs(x)
+	switch {
+	case x <= 1:
+		return ((a+2)*x-(a+3))*x*x + 1
+	case x < 2:
+		return ((a*x-5*a)*x+8*a)*x - 4*a
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2024 Thomas Mikalsen. Subject to the MIT License
+package imaging
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/tommika/gorilla/algorithms/matrix"
+	"github.com/tommika/gorilla/assert"
+	"github.com/tommika/gorilla/must"
+)
+
+func TestResize(t *testing.T) {
+	g := gradient(4, 9)
+	for _, interpolation := range []Interpolation{Bilinear, Bicubic} {
+		// resizing to the same size is the identity
+		assert.True(t, matrix.Equal(g, Resize(g, 4, 9, interpolation), epsilon))
+
+		r := Resize(g, 8, 5, interpolation)
+		assert.Equal(t, 8, r.NumRows())
+		assert.Equal(t, 5, r.NumCols())
+		// a linear gradient stays linear, away from the edges
+		for y := range 8 {
+			assert.EqualEpsilon(t, 0.5, r.Get(y, 2), epsilon)
+			assert.EqualEpsilon(t, r.Get(y, 2)-r.Get(y, 1), r.Get(y, 3)-r.Get(y, 2), epsilon)
+		}
+		// a uniform image stays uniform
+		u := Resize(matrix.NewMatrix(3, 3, 0.7), 10, 7, interpolation)
+		for v := range u.Values() {
+			assert.EqualEpsilon(t, 0.7, v, epsilon)
+		}
+		assert.Equal(t, 0, Resize(matrix.NewMatrix(0, 0, 0.0), 2, 2, interpolation).Get(1, 1))
+	}
+
+	// doubling the size of a 2x2 image
+	m := kernel3x3(0, 1, 0, 0, 0, 0, 0, 0, 0).Sub(0, 2, 0, 2).Clone()
+	r := Resize(m, 4, 4, Bilinear)
+	assert.DeepEqual(t, []float64{0, 0.25, 0.75, 1}, r.Rows()[0])
+	assert.DeepEqual(t, []float64{0, 0.1875, 0.5625, 0.75}, r.Rows()[1])
+	// bicubic overshoots at the step
+	r = Resize(m, 4, 4, Bicubic)
+	assert.True(t, r.Get(0, 0) < 0)
+	assert.True(t, r.Get(0, 3) > 1)
+}
+
+func TestResizeSmooth(t *testing.T) {
+	const rows, cols = 60, 80
+	m := matrix.NewMatrix(rows, cols, 0.0)
+	for y := range rows {
+		for x := range cols {
+			m.Set(y, x, 0.5+0.5*math.Sin(float64(x)/5)*math.Cos(float64(y)/7))
+		}
+	}
+	small := Resize(GaussianBlur(m, 1), rows/2, cols/2, Bilinear)
+	// scaling back up approximates the original; bicubic more closely
+	rmsErrors := []float64{}
+	for _, interpolation := range []Interpolation{Bilinear, Bicubic} {
+		big := Resize(small, rows, cols, interpolation)
+		diff := must.NotBeAnError(matrix.Sub(big, m))
+		total := 0.0
+		for v := range diff.Sub(2, rows-2, 2, cols-2).Values() {
+			total += v * v
+		}
+		rms := math.Sqrt(total / float64((rows-4)*(cols-4)))
+		assert.True(t, rms < 0.05)
+		rmsErrors = append(rmsErrors, rms)
+	}
+	assert.True(t, rmsErrors[1] < rmsErrors[0])
+}
+
+func TestResizeImage(t *testing.T) {
+	c := FromImage(must.NotBeAnError(ReadImage(testImage)))
+	rows, cols := c.R.NumRows(), c.R.NumCols()
+	small := c.Map(func(m matrix.Matrix[float64]) matrix.Matrix[float64] {
+		return Resize(m, rows/3, cols/2, Bicubic)
+	})
+	for _, m := range []matrix.Matrix[float64]{small.R, small.G, small.B, small.A} {
+		assert.Equal(t, rows/3, m.NumRows())
+		assert.Equal(t, cols/2, m.NumCols())
+	}
+	assert.DeepEqual(t, image.Rect(0, 0, cols/2, rows/3), small.ToImage().Bounds())
+}
+
+func BenchmarkResizeBicubic(b *testing.B) {
+	img := FromGray(must.NotBeAnError(ReadImage(testImage)))
+	b.ResetTimer()
+	for range b.N {
+		Resize(img, img.NumRows()*2, img.NumCols()*2, Bicubic)
+	}
+}